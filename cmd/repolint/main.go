@@ -0,0 +1,35 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/Quasilyte/repolint/lint"
+)
+
+// commands are subcommands that are run instead of the linter.
+var commands = map[string]func(args []string) error{
+	"self-update": lint.SelfUpdate,
+	"action":      lint.RunAction,
+	"checkers":    lint.ListCheckers,
+	"verify":      lint.VerifyReports,
+	"schema":      lint.PrintSchema,
+	"tui":         lint.RunTUI,
+}
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			if err := cmd(os.Args[2:]); err != nil {
+				log.Fatalf("%s: %v", os.Args[1], err)
+			}
+			return
+		}
+	}
+
+	if err := lint.NewRunner().Run(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/Quasilyte/repolint
+
+go 1.22
+
+require (
+	github.com/client9/misspell v0.3.4
+	github.com/google/go-github v17.0.0+incompatible
+	github.com/mattn/go-sqlite3 v1.14.33
+	gopkg.in/yaml.v2 v2.4.0
+)
+
+require github.com/google/go-querystring v1.1.0 // indirect
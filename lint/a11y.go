@@ -0,0 +1,117 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// htmlA11yConfig enables the HTML accessibility checker.
+type htmlA11yConfig struct {
+	// Rules are the enabled htmlA11yRules, all of them by default.
+	Rules []string `yaml:"rules"`
+}
+
+// htmlA11yRules are the HTML accessibility checker rules.
+var htmlA11yRules = []string{"lang", "alt", "headings"}
+
+func isKnownA11yRule(rule string) bool {
+	for _, r := range htmlA11yRules {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// htmlMaxFileSize limits the size of the checked HTML files.
+const htmlMaxFileSize = 1 << 20
+
+var (
+	// htmlSkippedRE matches HTML parts that are not checked:
+	// comments, scripts and styles.
+	htmlSkippedRE = regexp.MustCompile(`(?is)<!--.*?-->|<script\b.*?</script\s*>|<style\b.*?</style\s*>`)
+
+	// a11yTagRE matches the opening tags that are checked.
+	// First submatch group is a tag name, the second one is tag attributes.
+	a11yTagRE = regexp.MustCompile(`(?i)<(html|img|h[1-6])\b([^>]*)>`)
+
+	htmlLangRE = regexp.MustCompile(`(?i)(?:^|\s)(?:lang|xml:lang)\s*=`)
+	htmlAltRE  = regexp.MustCompile(`(?i)(?:^|\s)alt(?:\s*=|\s|/|$)`)
+)
+
+// htmlA11yChecker runs basic accessibility checks over HTML files,
+// like the ones of the in-tree documentation sites.
+// It's disabled until the html_accessibility config section is specified.
+type htmlA11yChecker struct {
+	CheckerBase
+
+	// rules is a set of enabled rules, nil if the checker is disabled.
+	rules map[string]bool
+}
+
+func newHTMLA11yChecker() *htmlA11yChecker {
+	return &htmlA11yChecker{}
+}
+
+func (c *htmlA11yChecker) PushFile(f *File) {
+	if c.rules == nil || f.size > htmlMaxFileSize {
+		return
+	}
+	switch strings.ToLower(path.Ext(f.baseName)) {
+	case ".html", ".htm", ".xhtml":
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *htmlA11yChecker) skipGenerated() {}
+
+// Results depend on the enabled rules.
+func (c *htmlA11yChecker) uncachedResults() {}
+
+func (c *htmlA11yChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	for _, f := range c.files {
+		if ctx.Err() != nil {
+			break
+		}
+		warnings = append(warnings, c.checkHTML(f)...)
+	}
+	return warnings
+}
+
+func (c *htmlA11yChecker) checkHTML(f *File) (warnings []string) {
+	// Skipped parts are blanked, so offsets still point to the right lines.
+	doc := htmlSkippedRE.ReplaceAllStringFunc(f.contents, func(s string) string {
+		return strings.Repeat("\n", strings.Count(s, "\n")) + strings.Repeat(" ", len(s)-strings.Count(s, "\n"))
+	})
+
+	prevLevel := 0
+	for _, m := range a11yTagRE.FindAllStringSubmatchIndex(doc, -1) {
+		tag := strings.ToLower(doc[m[2]:m[3]])
+		attrs := doc[m[4]:m[5]]
+		line := lineAt(doc, m[0])
+		switch {
+		case tag == "html":
+			if c.rules["lang"] && !htmlLangRE.MatchString(attrs) {
+				w := fmt.Sprintf("%s:%d: <html> has no lang attribute", f.origName, line)
+				warnings = append(warnings, w)
+			}
+		case tag == "img":
+			if c.rules["alt"] && !htmlAltRE.MatchString(attrs) {
+				w := fmt.Sprintf("%s:%d: <img> has no alt attribute", f.origName, line)
+				warnings = append(warnings, w)
+			}
+		default:
+			level := int(tag[1] - '0')
+			if c.rules["headings"] && prevLevel != 0 && level > prevLevel+1 {
+				w := fmt.Sprintf("%s:%d: <%s> skips heading levels after <h%d>", f.origName, line, tag, prevLevel)
+				warnings = append(warnings, w)
+			}
+			prevLevel = level
+		}
+	}
+	return warnings
+}
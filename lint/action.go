@@ -0,0 +1,195 @@
+package lint
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// maxSummaryWarnings limits the job summary table size.
+// GitHub rejects summaries that are larger than 1MiB.
+const maxSummaryWarnings = 1000
+
+// RunAction runs the linter as a GitHub Action.
+//
+// Inputs are passed as INPUT_* env vars and have the same names as flags.
+// By default, the workflow repository is checked; inside pull request
+// workflows only the pull request files are checked.
+func RunAction(args []string) error {
+	if os.Getenv("TOKEN") == "" {
+		token := actionInput("token")
+		if token == "" {
+			token = os.Getenv("GITHUB_TOKEN")
+		}
+		os.Setenv("TOKEN", token)
+	}
+
+	l := NewRunner()
+	l.action = true
+	return l.Run(args)
+}
+
+// actionInput returns a GitHub Action input value.
+func actionInput(name string) string {
+	name = strings.ToUpper(name)
+	value, ok := os.LookupEnv("INPUT_" + name)
+	if !ok {
+		value = os.Getenv("INPUT_" + strings.Replace(name, "-", "_", -1))
+	}
+	return strings.TrimSpace(value)
+}
+
+// applyActionInputs sets flag values from the GitHub Action inputs
+// and the workflow environment.
+func applyActionInputs(fs *flag.FlagSet) error {
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		value := actionInput(f.Name)
+		if value == "" || err != nil {
+			return
+		}
+		if setErr := fs.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("input %s: %v", f.Name, setErr)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	isEmpty := func(name string) bool {
+		f := fs.Lookup(name)
+		return f.Value.String() == f.DefValue
+	}
+	if isEmpty("user") && isEmpty("repo") {
+		parts := strings.SplitN(os.Getenv("GITHUB_REPOSITORY"), "/", 2)
+		if len(parts) == 2 {
+			fs.Set("user", parts[0])
+			fs.Set("repo", parts[1])
+		}
+	}
+	if isEmpty("pr") && isEmpty("diff") {
+		pr, err := eventPullRequest()
+		if err != nil {
+			return err
+		}
+		if pr != 0 {
+			fs.Set("pr", strconv.Itoa(pr))
+		}
+	}
+	server := os.Getenv("GITHUB_SERVER_URL")
+	if server != "" && server != "https://github.com" && isEmpty("github-url") {
+		// GitHub Enterprise Server.
+		fs.Set("github-url", server)
+		if isEmpty("github-api-url") {
+			fs.Set("github-api-url", os.Getenv("GITHUB_API_URL"))
+		}
+	}
+	return nil
+}
+
+// eventPullRequest returns a pull request number of the workflow event.
+// Returns 0 for non pull request events.
+func eventPullRequest() (int, error) {
+	switch os.Getenv("GITHUB_EVENT_NAME") {
+	case "pull_request", "pull_request_target":
+	default:
+		return 0, nil
+	}
+	data, err := ioutil.ReadFile(os.Getenv("GITHUB_EVENT_PATH"))
+	if err != nil {
+		return 0, fmt.Errorf("read event: %v", err)
+	}
+	var event struct {
+		PullRequest struct {
+			Number int `json:"number"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return 0, fmt.Errorf("parse event: %v", err)
+	}
+	return event.PullRequest.Number, nil
+}
+
+// writeActionOutputs writes GitHub Action step outputs and a job summary.
+func (l *Runner) writeActionOutputs() error {
+	if !l.action {
+		return nil
+	}
+	n := 0
+	score := 100
+	for _, rr := range l.results.Repos {
+		n += len(rr.Warnings)
+		if rr.Score < score {
+			score = rr.Score
+		}
+	}
+	if filename := os.Getenv("GITHUB_OUTPUT"); filename != "" {
+		outputs := fmt.Sprintf("warnings=%d\nrepos=%d\nscore=%d\n", n, len(l.results.Repos), score)
+		if err := appendFile(filename, outputs); err != nil {
+			return err
+		}
+	}
+	if filename := os.Getenv("GITHUB_STEP_SUMMARY"); filename != "" {
+		if err := appendFile(filename, l.results.markdownSummary()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markdownSummary returns the results formatted as a markdown table.
+func (r *runReport) markdownSummary() string {
+	var buf strings.Builder
+	n := 0
+	for _, rr := range r.Repos {
+		n += len(rr.Warnings)
+	}
+	buf.WriteString("## repolint\n\n")
+	if n == 0 {
+		fmt.Fprintf(&buf, "No warnings in %d repositories.\n", len(r.Repos))
+		return buf.String()
+	}
+	fmt.Fprintf(&buf, "Found %d warnings in %d repositories.\n\n", n, len(r.Repos))
+	if len(r.Repos) > 1 {
+		buf.WriteString("| Repository | Score |\n")
+		buf.WriteString("|---|---|\n")
+		for _, rr := range r.Repos {
+			fmt.Fprintf(&buf, "| %s | %d |\n", rr.Name, rr.Score)
+		}
+		buf.WriteString("\n")
+	} else {
+		fmt.Fprintf(&buf, "Score: %d.\n\n", r.Repos[0].Score)
+	}
+	buf.WriteString("| Repository | Checker | Severity | Warning |\n")
+	buf.WriteString("|---|---|---|---|\n")
+	escape := strings.NewReplacer("|", `\|`, "\n", " ", "<", "&lt;", ">", "&gt;")
+	written := 0
+	for _, rr := range r.Repos {
+		for _, w := range rr.Warnings {
+			if written == maxSummaryWarnings {
+				fmt.Fprintf(&buf, "\n%d more warnings are not shown.\n", n-written)
+				return buf.String()
+			}
+			fmt.Fprintf(&buf, "| %s | %s | %s | %s |\n",
+				escape.Replace(rr.Name), w.Checker, w.Severity, escape.Replace(w.Text))
+			written++
+		}
+	}
+	return buf.String()
+}
+
+func appendFile(filename, s string) error {
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(s); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
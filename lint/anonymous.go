@@ -0,0 +1,61 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+// anonymousMinAPICalls is the least number of the unauthenticated API calls
+// left that's enough to check a repository in anonymous mode.
+const anonymousMinAPICalls = 5
+
+// initAnonymousMode validates the anonymous mode flags.
+//
+// Anonymous mode doesn't use a token, so it's limited by the unauthenticated
+// API rate limit of 60 requests per hour, shared by the whole IP address.
+// It checks a single public repository, downloaded as a tarball.
+func (l *Runner) initAnonymousMode() error {
+	if !l.anonymous {
+		return nil
+	}
+	if l.dir != "" {
+		return errors.New("-anonymous can't be used with -dir, local mode doesn't need a token")
+	}
+	if l.repo == "" {
+		return errors.New("-anonymous requires -repo argument")
+	}
+	if l.pr != 0 {
+		return errors.New("-anonymous can't be used with -pr")
+	}
+	if !l.isFlagSet("fetch") {
+		l.fetchMode = "tarball"
+	}
+	return nil
+}
+
+// checkAnonymousRateLimit makes sure there are enough unauthenticated
+// API calls left and limits the run to them.
+func (l *Runner) checkAnonymousRateLimit() error {
+	if !l.anonymous {
+		return nil
+	}
+	limits, _, err := l.client.RateLimits(l.ctx)
+	if err != nil {
+		return fmt.Errorf("get rate limit: %v", err)
+	}
+	core := limits.GetCore()
+	if core == nil {
+		return nil
+	}
+	if core.Remaining < anonymousMinAPICalls {
+		return fmt.Errorf("only %d of %d anonymous github API calls are left until %s, try again later or use a token",
+			core.Remaining, core.Limit, core.Reset.Format("15:04"))
+	}
+	if l.maxAPICalls == 0 || l.maxAPICalls > core.Remaining {
+		l.maxAPICalls = core.Remaining
+	}
+	log.Printf("anonymous mode: %d of %d github API calls are left until %s",
+		core.Remaining, core.Limit, core.Reset.Format("15:04"))
+	return nil
+}
@@ -0,0 +1,72 @@
+package lint
+
+import (
+	"strings"
+	"sync"
+)
+
+// fileArtifacts are the values derived from the file contents, like markdown
+// links and headings. Every artifact is computed on the first use and shared
+// by all checkers, so a document is parsed once, even though the checkers run
+// concurrently. Artifacts must not be modified by the checkers.
+type fileArtifacts struct {
+	proseOnce sync.Once
+	prose     string
+
+	linesOnce sync.Once
+	lines     []string
+
+	linksOnce sync.Once
+	links     []string
+
+	badgesOnce sync.Once
+	badges     []badge
+
+	headingsOnce sync.Once
+	headings     []string
+
+	anchorsOnce sync.Once
+	anchors     map[string]bool
+}
+
+// prose returns the file contents with blanked fenced code blocks, see blankCodeBlocks.
+func (f *File) prose() string {
+	a := &f.artifacts
+	a.proseOnce.Do(func() { a.prose = blankCodeBlocks(f.contents) })
+	return a.prose
+}
+
+// lines returns the file contents lines without line breaks.
+func (f *File) lines() []string {
+	a := &f.artifacts
+	a.linesOnce.Do(func() { a.lines = strings.Split(f.contents, "\n") })
+	return a.lines
+}
+
+// links returns the document links, see extractLinks.
+func (f *File) links() []string {
+	a := &f.artifacts
+	a.linksOnce.Do(func() { a.links = proseLinks(f.prose()) })
+	return a.links
+}
+
+// badges returns the document badges, see extractBadges.
+func (f *File) badges() []badge {
+	a := &f.artifacts
+	a.badgesOnce.Do(func() { a.badges = proseBadges(f.prose()) })
+	return a.badges
+}
+
+// headings returns the markdown document headings, see markdownHeadings.
+func (f *File) headings() []string {
+	a := &f.artifacts
+	a.headingsOnce.Do(func() { a.headings = proseHeadings(f.prose()) })
+	return a.headings
+}
+
+// anchors returns the markdown document anchors, see documentAnchors.
+func (f *File) anchors() map[string]bool {
+	a := &f.artifacts
+	a.anchorsOnce.Do(func() { a.anchors = proseAnchors(f.prose(), f.headings()) })
+	return a.anchors
+}
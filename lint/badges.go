@@ -0,0 +1,186 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// badge is a README image that shows a project status, like a build badge.
+type badge struct {
+	// line is a 1-based badge line number.
+	line int
+
+	// image is a badge image URL.
+	image string
+
+	// link is a page the badge links to.
+	// Empty for badges without a link.
+	link string
+}
+
+var (
+	// linkedBadgeRE matches [![alt](image)](link) badges.
+	linkedBadgeRE = regexp.MustCompile(`\[!\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+["'][^)]*)?\)\]\(\s*<?([^)\s>]+)>?(?:\s+["'][^)]*)?\)`)
+
+	// imageRE matches ![alt](image) images.
+	imageRE = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+["'][^)]*)?\)`)
+
+	// imgTagRE matches <img src="image"> images.
+	imgTagRE = regexp.MustCompile(`(?i)<img\s[^>]*?src\s*=\s*["']([^"']+)["']`)
+)
+
+// extractBadges returns README badges in the order of their appearance.
+// Badges inside fenced code blocks are ignored.
+func extractBadges(doc string) []badge {
+	return proseBadges(blankCodeBlocks(doc))
+}
+
+// proseBadges is extractBadges for a document with blanked code blocks.
+func proseBadges(doc string) []badge {
+	var badges []badge
+	covered := make(map[int]bool)
+	for _, re := range []*regexp.Regexp{linkedBadgeRE, imageRE, imgTagRE} {
+		for _, m := range re.FindAllStringSubmatchIndex(doc, -1) {
+			start := m[2]
+			if covered[start] {
+				// Already matched as a linked badge.
+				continue
+			}
+			covered[start] = true
+			b := badge{line: lineAt(doc, m[0]), image: doc[m[2]:m[3]]}
+			if len(m) > 4 {
+				b.link = doc[m[4]:m[5]]
+			}
+			if isBadgeURL(b.image) {
+				badges = append(badges, b)
+			}
+		}
+	}
+	sort.SliceStable(badges, func(i, j int) bool {
+		return badges[i].line < badges[j].line
+	})
+	return badges
+}
+
+// isBadgeURL reports whether u is a status badge image URL.
+func isBadgeURL(u string) bool {
+	if !isWebLink(u) {
+		return false
+	}
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return false
+	}
+	host := badgeHost(parsed)
+	if isBadgeHost(host) || host == "godoc.org" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(parsed.Path), "badge")
+}
+
+// badgeHost returns a lowercase u host without the www. prefix.
+func badgeHost(u *url.URL) string {
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// badgeChecker finds README badges of dead and deprecated services,
+// and badges which images don't exist.
+type badgeChecker struct {
+	CheckerBase
+
+	// links checks badge image URLs.
+	links *brokenLinkChecker
+
+	// goCode reports whether the repository has Go files.
+	goCode bool
+}
+
+func newBadgeChecker() *badgeChecker {
+	return &badgeChecker{links: newBrokenLinkChecker()}
+}
+
+func (c *badgeChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.goCode = false
+}
+
+func (c *badgeChecker) PushFile(f *File) {
+	if path.Ext(f.baseName) == ".go" || f.baseName == "go.mod" {
+		c.goCode = true
+	}
+	if strings.HasPrefix(strings.ToUpper(f.baseName), "README") {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+// Go code is detected using all repository files.
+func (c *badgeChecker) fullTree() {}
+
+// Badge images may disappear without any changes to the file itself.
+func (c *badgeChecker) uncachedResults() {}
+
+func (c *badgeChecker) networkResults() {}
+
+func (c *badgeChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	type checkedBadge struct {
+		f *File
+		badge
+	}
+	var checked []checkedBadge
+	var urls []string
+	for _, f := range c.files {
+		for _, b := range f.badges() {
+			if problem := c.checkService(b); problem != "" {
+				w := fmt.Sprintf("%s:%d: %s", f.origName, b.line, problem)
+				warnings = append(warnings, w)
+				continue
+			}
+			checked = append(checked, checkedBadge{f: f, badge: b})
+			urls = append(urls, b.image)
+		}
+	}
+
+	results := c.links.checkURLs(ctx, urls)
+	for _, b := range checked {
+		r := results[b.image]
+		if r.StatusCode == http.StatusNotFound || r.StatusCode == http.StatusGone {
+			w := fmt.Sprintf("%s:%d: badge image %s: %s", b.f.origName, b.line, b.image, r.problem())
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// checkService returns a problem of the service that serves b.
+// Returns empty string for badges of the live services.
+func (c *badgeChecker) checkService(b badge) string {
+	image, err := url.Parse(b.image)
+	if err != nil {
+		return ""
+	}
+	linkHost := ""
+	if link, err := url.Parse(b.link); err == nil {
+		linkHost = badgeHost(link)
+	}
+
+	switch host := badgeHost(image); {
+	case host == "travis-ci.org" || linkHost == "travis-ci.org":
+		return "travis-ci.org badge, travis-ci.org is shut down, use travis-ci.com or another CI badge"
+	case host == "godoc.org":
+		importPath := strings.Trim(image.Path, "/")
+		if importPath == "" {
+			return "godoc.org badge is deprecated, use a pkg.go.dev badge"
+		}
+		return fmt.Sprintf("godoc.org badge is deprecated, use https://pkg.go.dev/badge/%s.svg", importPath)
+	case host == "goreportcard.com" && !c.goCode:
+		return "goreportcard badge in a repository without Go code"
+	}
+	return ""
+}
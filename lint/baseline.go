@@ -0,0 +1,112 @@
+package lint
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"regexp"
+	"sort"
+)
+
+// baseline is a set of known warnings that should not be reported.
+type baseline struct {
+	fingerprints map[string]bool
+}
+
+// baselineEntry is a baseline file record.
+// Only fingerprint is used for matching, other fields are
+// there to make the file human-readable.
+type baselineEntry struct {
+	Repo        string `json:"repo"`
+	Checker     string `json:"checker"`
+	Text        string `json:"text"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// lineColRE matches "file:line:" and "file:line:col:" location parts.
+var lineColRE = regexp.MustCompile(`:\d+(?::\d+)?:`)
+
+// normalizeWarningText removes line and column numbers from the warning text,
+// so a warning stays the same after unrelated lines are added or removed.
+func normalizeWarningText(text string) string {
+	return lineColRE.ReplaceAllString(text, ":")
+}
+
+// warningFingerprint returns a stable warning identifier.
+func warningFingerprint(repo string, w Warning) string {
+	h := sha1.New()
+	for _, s := range []string{repo, w.Checker, normalizeWarningText(w.Text)} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func loadBaseline(filename string) (*baseline, error) {
+	entries, err := loadBaselineEntries(filename)
+	if err != nil {
+		return nil, err
+	}
+	b := &baseline{fingerprints: make(map[string]bool, len(entries))}
+	for _, e := range entries {
+		b.fingerprints[e.Fingerprint] = true
+	}
+	return b, nil
+}
+
+func loadBaselineEntries(filename string) ([]baselineEntry, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var entries []baselineEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (b *baseline) contains(repo string, w Warning) bool {
+	return b.fingerprints[warningFingerprint(repo, w)]
+}
+
+// writeBaseline saves all reported warnings as a new baseline file.
+func writeBaseline(filename string, r *runReport) error {
+	entries := []baselineEntry{}
+	for _, rr := range r.Repos {
+		for _, w := range rr.Warnings {
+			entries = append(entries, newBaselineEntry(rr.Name, w))
+		}
+	}
+	return saveBaselineEntries(filename, entries)
+}
+
+func newBaselineEntry(repo string, w Warning) baselineEntry {
+	return baselineEntry{
+		Repo:        repo,
+		Checker:     w.Checker,
+		Text:        normalizeWarningText(w.Text),
+		Fingerprint: warningFingerprint(repo, w),
+	}
+}
+
+// saveBaselineEntries writes the entries without duplicates, sorted by fingerprint.
+func saveBaselineEntries(filename string, all []baselineEntry) error {
+	entries := []baselineEntry{}
+	seen := make(map[string]bool)
+	for _, e := range all {
+		if !seen[e.Fingerprint] {
+			seen[e.Fingerprint] = true
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Fingerprint < entries[j].Fingerprint
+	})
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
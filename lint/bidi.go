@@ -0,0 +1,81 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"path"
+)
+
+// bidiMaxFileSize limits the size of the source files checked for BiDi characters.
+// Larger files are usually generated.
+const bidiMaxFileSize = 1 << 20
+
+// bidiControls are Unicode bidirectional control characters
+// that can make the displayed code differ from the compiled one
+// (CVE-2021-42574, "Trojan Source").
+var bidiControls = map[rune]string{
+	'\u202A': "LEFT-TO-RIGHT EMBEDDING",
+	'\u202B': "RIGHT-TO-LEFT EMBEDDING",
+	'\u202C': "POP DIRECTIONAL FORMATTING",
+	'\u202D': "LEFT-TO-RIGHT OVERRIDE",
+	'\u202E': "RIGHT-TO-LEFT OVERRIDE",
+	'\u2066': "LEFT-TO-RIGHT ISOLATE",
+	'\u2067': "RIGHT-TO-LEFT ISOLATE",
+	'\u2068': "FIRST STRONG ISOLATE",
+	'\u2069': "POP DIRECTIONAL ISOLATE",
+}
+
+// isSourceFile reports whether filename has a known programming language extension.
+func isSourceFile(filename string) bool {
+	ext := path.Ext(filename)
+	if ext == "" {
+		return false
+	}
+	for _, info := range knownLanguages {
+		for _, e := range info.exts {
+			if e == ext {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// bidiChecker finds Unicode BiDi control characters in source and documentation files.
+type bidiChecker struct {
+	CheckerBase
+}
+
+func newBidiChecker() *bidiChecker {
+	return &bidiChecker{}
+}
+
+func (c *bidiChecker) PushFile(f *File) {
+	if (isSourceFile(f.baseName) || isDocumentationFile(f.baseName)) && f.size <= bidiMaxFileSize {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *bidiChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	for _, f := range c.files {
+		if ctx.Err() != nil {
+			break
+		}
+		line := 1
+		for offset, r := range f.contents {
+			if r == '\n' {
+				line++
+				continue
+			}
+			name, ok := bidiControls[r]
+			if !ok {
+				continue
+			}
+			w := fmt.Sprintf("%s:%d: BiDi control character U+%04X %s at byte offset %d",
+				f.origName, line, r, name, offset)
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,77 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// defaultBranchLinkChecker finds documentation links to the repository
+// files on the master branch, while the default branch is different, like main.
+// Such links are broken since the branch was renamed.
+type defaultBranchLinkChecker struct {
+	CheckerBase
+	metadata *repoMetadata
+
+	// linkRE matches links to the repository master branch files,
+	// it's nil in local mode.
+	linkRE *regexp.Regexp
+}
+
+func newDefaultBranchLinkChecker() *defaultBranchLinkChecker {
+	return &defaultBranchLinkChecker{}
+}
+
+func (c *defaultBranchLinkChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.metadata = nil
+}
+
+func (c *defaultBranchLinkChecker) PushFile(f *File) {
+	if isDocumentationFile(f.baseName) {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *defaultBranchLinkChecker) setMetadata(m *repoMetadata) {
+	c.metadata = m
+}
+
+func (c *defaultBranchLinkChecker) setRepoURL(u string) {
+	c.linkRE = nil
+	if u != "" {
+		c.linkRE = regexp.MustCompile(`(?i)` + regexp.QuoteMeta(u) +
+			`/(?:blob|tree|raw|edit)/master(?:[/#?][^\s()<>"'\x60\]]*)?`)
+	}
+}
+
+// Results depend on the repository default branch.
+func (c *defaultBranchLinkChecker) uncachedResults() {}
+
+func (c *defaultBranchLinkChecker) skipGenerated() {}
+
+func (c *defaultBranchLinkChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	m := c.metadata
+	if m == nil || c.linkRE == nil || m.DefaultBranch == "" || m.DefaultBranch == "master" {
+		return nil
+	}
+	for _, f := range c.files {
+		for _, loc := range c.linkRE.FindAllStringIndex(f.contents, -1) {
+			// A longer branch name, like master-v2.
+			if end := loc[1]; end < len(f.contents) && isBranchNameByte(f.contents[end]) {
+				continue
+			}
+			link := strings.TrimRight(f.contents[loc[0]:loc[1]], ".,;:!")
+			w := fmt.Sprintf("%s:%d: link %s points to the master branch, but the default branch is %s",
+				f.origName, lineAt(f.contents, loc[0]), link, m.DefaultBranch)
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+func isBranchNameByte(b byte) bool {
+	return b == '-' || b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
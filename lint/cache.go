@@ -0,0 +1,139 @@
+package lint
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// ruleSetVersion must be incremented every time checkers
+// behavior changes, so outdated cached results are discarded.
+const ruleSetVersion = 6
+
+// resultCache stores per-file checker results keyed by the file blob hash.
+//
+// Re-scans only run checkers over the files which contents has changed.
+// Since the key doesn't include the repository name, files vendored
+// into many repositories are checked only once.
+//
+// The key is the checker name, the blob hash and the base name, nothing else.
+// A checker is only cacheable if its warnings for a file depend on these alone.
+// The checkers which results also depend on
+//
+//   - the file directory or the full path, like path length limits;
+//   - other files, like a README compared with package.json;
+//   - config options or flags, like thresholds and enabled rules;
+//   - the network, git history or installed tools
+//
+// must implement uncachedChecker, or treeChecker if they need all repository files.
+// Warnings that don't start with an accepted file path, like directory warnings,
+// can't be split per file, so such runs are not cached at all.
+//
+// Cached warnings have no fixes, so fixers bypass the cache in -fix mode.
+type resultCache struct {
+	Version int                 `json:"version"`
+	Entries map[string][]string `json:"entries"`
+}
+
+// fileNamePlaceholder replaces the file name inside cached warnings,
+// so they can be used for a file with a different path.
+const fileNamePlaceholder = "\x00file\x00"
+
+// uncachedChecker is implemented by the checkers which results
+// depend on something besides the file contents, like a network state.
+type uncachedChecker interface {
+	uncachedResults()
+}
+
+// treeChecker is implemented by the checkers that need the full repository
+// file listing, like the ones that report missing files.
+//
+// Tree checkers get all files pushed even if other checkers use cached results,
+// and their results are never cached. They're disabled in -diff and -pr modes,
+// since only the changed files are listed there.
+type treeChecker interface {
+	fullTree()
+}
+
+func loadResultCache(filename string) (*resultCache, error) {
+	rc := &resultCache{
+		Version: ruleSetVersion,
+		Entries: make(map[string][]string),
+	}
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return rc, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var saved resultCache
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, err
+	}
+	if saved.Version == ruleSetVersion && saved.Entries != nil {
+		rc.Entries = saved.Entries
+	}
+	return rc, nil
+}
+
+func (rc *resultCache) save(filename string) error {
+	data, err := json.Marshal(rc)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+func (rc *resultCache) key(checker string, f *File) string {
+	// Some checkers only look at the file names,
+	// so base name is also a part of the key.
+	return checker + "/" + f.contentsHash() + "/" + f.baseName
+}
+
+func (rc *resultCache) cacheable(c Checker, f *File) bool {
+	_, uncached := c.(uncachedChecker)
+	_, tree := c.(treeChecker)
+	return !uncached && !tree && f.contentsHash() != ""
+}
+
+// get returns cached warnings for the specified checker and file.
+func (rc *resultCache) get(name string, c Checker, f *File) ([]string, bool) {
+	if !rc.cacheable(c, f) {
+		return nil, false
+	}
+	warnings, ok := rc.Entries[rc.key(name, f)]
+	if !ok {
+		return nil, false
+	}
+	result := make([]string, len(warnings))
+	for i, w := range warnings {
+		result[i] = strings.Replace(w, fileNamePlaceholder, f.origName, -1)
+	}
+	return result, true
+}
+
+// put stores warnings produced by the checker for the checked files.
+func (rc *resultCache) put(name string, c Checker, files []*File, warnings []string) {
+	perFile := make(map[*File][]string, len(files))
+	for _, w := range warnings {
+		f := findWarningFile(files, w)
+		if f == nil {
+			// Can't split results per file.
+			return
+		}
+		w = strings.Replace(w, f.origName, fileNamePlaceholder, -1)
+		perFile[f] = append(perFile[f], w)
+	}
+	for _, f := range files {
+		if !rc.cacheable(c, f) {
+			continue
+		}
+		list := perFile[f]
+		if list == nil {
+			list = []string{}
+		}
+		rc.Entries[rc.key(name, f)] = list
+	}
+}
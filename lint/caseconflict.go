@@ -0,0 +1,102 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// caseConflictChecker finds paths that differ only by case, like README.md
+// and Readme.md. Such repositories can't be checked out on case-insensitive
+// file systems, which are the default on macOS and Windows.
+type caseConflictChecker struct {
+	CheckerBase
+
+	// tree is the checked repository tree index, if available.
+	tree *repoTree
+}
+
+func newCaseConflictChecker() *caseConflictChecker {
+	return &caseConflictChecker{}
+}
+
+func (c *caseConflictChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.tree = nil
+}
+
+func (c *caseConflictChecker) PushFile(f *File) {
+	c.AcceptFile(f)
+}
+
+func (c *caseConflictChecker) setTree(t *repoTree) {
+	c.tree = t
+}
+
+// Results depend on the files that are not pushed to the checker.
+func (c *caseConflictChecker) uncachedResults() {}
+
+func (c *caseConflictChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	tree := c.tree
+	if tree == nil {
+		tree = newRepoTree(c.files)
+	}
+	// Conflicts are only reported if they involve the checked files,
+	// so in -diff mode only the new conflicts are reported.
+	checked := make(map[string]bool, len(c.files))
+	for _, f := range c.files {
+		for p := f.origName; p != "." && p != "/" && !checked[p]; p = path.Dir(p) {
+			checked[p] = true
+		}
+	}
+
+	groups := make(map[string][]string)
+	for p := range tree.paths {
+		key := strings.ToLower(p)
+		groups[key] = append(groups[key], p)
+	}
+	keys := make([]string, 0, len(groups))
+	for key, paths := range groups {
+		if len(paths) > 1 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		paths := groups[key]
+		sort.Strings(paths)
+		if !isTopmostConflict(paths) || !anyChecked(checked, paths) {
+			continue
+		}
+		for _, p := range paths[1:] {
+			w := fmt.Sprintf("%s: differs from %s only by case, they conflict on case-insensitive file systems, like macOS and Windows", p, paths[0])
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// isTopmostConflict reports whether paths have the same parent directory.
+// Otherwise, the conflict is in the parent directories, like docs/Foo/a.md
+// and docs/foo/a.md, and it's reported for them.
+func isTopmostConflict(paths []string) bool {
+	dir := path.Dir(paths[0])
+	for _, p := range paths[1:] {
+		if path.Dir(p) != dir {
+			return false
+		}
+	}
+	return true
+}
+
+func anyChecked(checked map[string]bool, paths []string) bool {
+	for _, p := range paths {
+		if checked[p] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// changelogConfig configures the changelog checker.
+type changelogConfig struct {
+	// Strict checks all CHANGELOG.md files, not only the ones
+	// that link to the Keep a Changelog format.
+	Strict bool `yaml:"strict"`
+}
+
+var (
+	// changelogFileRE matches the changelog file names.
+	changelogFileRE = regexp.MustCompile(`(?i)^CHANGELOG\.md$`)
+
+	// changelogHeadingRE matches the release headings, like "[1.0.0] - 2017-06-20",
+	// "[1.0.0](https://github.com/o/r/compare/v0.9.0...v1.0.0) - 2017-06-20" or "[Unreleased]".
+	changelogHeadingRE = regexp.MustCompile(`^\[?([^\]\s(]+)\]?(?:\([^)]*\))?(?:\s+[-\x{2013}\x{2014}]\s+(.+?))?(?:\s+\[YANKED\])?$`)
+
+	// changelogVersionRE matches semantic versions, like 1.0.0 or v2.0.0-rc.1.
+	changelogVersionRE = regexp.MustCompile(`^v?\d+(?:\.\d+)*(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?$`)
+
+	// isoDateRE matches YYYY-MM-DD dates.
+	isoDateRE = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+// changelogRelease is a level-2 changelog heading.
+type changelogRelease struct {
+	line    int
+	version string
+	date    time.Time
+}
+
+// changelogChecker checks CHANGELOG.md files that follow the Keep a Changelog
+// conventions (https://keepachangelog.com): an Unreleased section on top,
+// released versions with ISO dates, newest first, and git tags for the versions.
+type changelogChecker struct {
+	CheckerBase
+
+	// strict is set by the changelog config section.
+	strict bool
+
+	// tags are the repository git tags, nil if they're unknown.
+	tags []string
+}
+
+func newChangelogChecker() *changelogChecker {
+	return &changelogChecker{}
+}
+
+func (c *changelogChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.tags = nil
+}
+
+func (c *changelogChecker) PushFile(f *File) {
+	if f.origName == f.baseName && changelogFileRE.MatchString(f.baseName) {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *changelogChecker) setTags(tags []string) {
+	c.tags = tags
+}
+
+// Results depend on the repository tags.
+func (c *changelogChecker) uncachedResults() {}
+
+func (c *changelogChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	for _, f := range c.files {
+		if !c.strict && !strings.Contains(f.contents, "keepachangelog.com") {
+			continue
+		}
+		warnings = append(warnings, c.checkChangelog(f)...)
+	}
+	return warnings
+}
+
+func (c *changelogChecker) checkChangelog(f *File) (warnings []string) {
+	report := func(line int, format string, args ...interface{}) {
+		w := fmt.Sprintf("%s:%d: ", f.origName, line) + fmt.Sprintf(format, args...)
+		warnings = append(warnings, w)
+	}
+
+	unreleased := 0
+	var releases []changelogRelease
+	for i, line := range strings.Split(f.prose(), "\n") {
+		if !strings.HasPrefix(line, "## ") {
+			continue
+		}
+		heading := strings.TrimSpace(strings.TrimPrefix(line, "## "))
+		m := changelogHeadingRE.FindStringSubmatch(heading)
+		switch {
+		case m != nil && strings.EqualFold(m[1], "Unreleased"):
+			if unreleased != 0 {
+				continue
+			}
+			unreleased = i + 1
+			if len(releases) != 0 {
+				report(unreleased, "Unreleased section should be above the released versions")
+			}
+			continue
+		case m == nil || !changelogVersionRE.MatchString(m[1]):
+			report(i+1, "%q is not a version heading, like [1.0.0] - 2017-06-20", heading)
+			continue
+		}
+
+		r := changelogRelease{line: i + 1, version: strings.TrimPrefix(m[1], "v")}
+		switch date := m[2]; {
+		case date == "":
+			report(r.line, "version %s has no release date, like [%s] - 2017-06-20", m[1], m[1])
+		case !isoDateRE.MatchString(date):
+			report(r.line, "version %s date %q is not in the YYYY-MM-DD format", m[1], date)
+		default:
+			t, err := time.Parse("2006-01-02", date)
+			if err != nil {
+				report(r.line, "version %s date %s is not a valid date", m[1], date)
+			}
+			r.date = t
+		}
+		if len(releases) != 0 {
+			prev := releases[len(releases)-1]
+			switch cmp := compareReleaseVersions(r.version, prev.version); {
+			case r.version == prev.version:
+				report(r.line, "version %s is listed twice", m[1])
+			case cmp > 0:
+				report(r.line, "version %s is listed below the older version %s, the newest versions should go first", r.version, prev.version)
+			case !r.date.IsZero() && r.date.After(prev.date) && !prev.date.IsZero():
+				report(r.line, "version %s is dated %s, after the newer version %s dated %s",
+					r.version, r.date.Format("2006-01-02"), prev.version, prev.date.Format("2006-01-02"))
+			}
+		}
+		releases = append(releases, r)
+	}
+	if unreleased == 0 {
+		warnings = append([]string{fmt.Sprintf("%s: no Unreleased section", f.origName)}, warnings...)
+	}
+
+	return append(warnings, c.checkTags(f, releases)...)
+}
+
+// checkTags reports versions without git tags. Only repositories
+// that tag at least one of the versions are checked.
+func (c *changelogChecker) checkTags(f *File, releases []changelogRelease) (warnings []string) {
+	if len(c.tags) == 0 {
+		return nil
+	}
+	tagged := make(map[string]bool, len(c.tags))
+	for _, tag := range c.tags {
+		// Monorepo tags are prefixed by a module path or a package name,
+		// like "api/v1.0.0" or "pkg@1.0.0".
+		if i := strings.LastIndexAny(tag, "/@"); i != -1 {
+			tag = tag[i+1:]
+		}
+		tagged[strings.TrimPrefix(tag, "v")] = true
+	}
+	var untagged []changelogRelease
+	for _, r := range releases {
+		if !tagged[r.version] {
+			untagged = append(untagged, r)
+		}
+	}
+	if len(untagged) == len(releases) {
+		return nil
+	}
+	for _, r := range untagged {
+		w := fmt.Sprintf("%s:%d: version %s has no git tag, like v%s", f.origName, r.line, r.version, r.version)
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
+
+// compareReleaseVersions compares semantic versions without the "v" prefix.
+// Pre-releases go before the release, pre-releases of the same version are equal.
+func compareReleaseVersions(a, b string) int {
+	a = strings.SplitN(a, "+", 2)[0]
+	b = strings.SplitN(b, "+", 2)[0]
+	ac := strings.SplitN(a, "-", 2)
+	bc := strings.SplitN(b, "-", 2)
+	if cmp := compareVersions(ac[0], bc[0]); cmp != 0 {
+		return cmp
+	}
+	switch {
+	case len(ac) == len(bc):
+		return 0
+	case len(ac) == 2:
+		return -1
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,423 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/client9/misspell"
+)
+
+// Checker finds issues in the repository files.
+//
+// For every repository, the checker is reset and all repository files are pushed to it.
+// Checker accepts the files it's interested in and, if necessary,
+// requires their contents with File.RequireContents.
+// CheckFiles is called after all requirements are resolved.
+type Checker interface {
+	Reset()
+	PushFile(*File)
+
+	// CheckFiles checks pushed files and returns warnings.
+	// Checkers stop early and return partial results when ctx is done.
+	CheckFiles(ctx context.Context) []string
+
+	// AcceptedFiles returns a list of pushed files that are going to be checked.
+	AcceptedFiles() []*File
+}
+
+// CheckerBase implements Checker methods that keep accepted files.
+// It's intended to be embedded into Checker implementations.
+type CheckerBase struct {
+	files []*File
+}
+
+func (c *CheckerBase) Reset() {
+	c.files = c.files[:0]
+}
+
+func (c *CheckerBase) PushFile(f *File) {
+	c.AcceptFile(f)
+}
+
+func (c *CheckerBase) AcceptedFiles() []*File {
+	return c.files
+}
+
+// AcceptFile adds f to the list of files that are going to be checked.
+func (c *CheckerBase) AcceptFile(f *File) {
+	c.files = append(c.files, f)
+}
+
+func (c *CheckerBase) tempFilenames() []string {
+	names := make([]string, len(c.files))
+	for i, f := range c.files {
+		names[i] = f.tempName
+	}
+	return names
+}
+
+// manifest maps local file names to the accepted repository files.
+//
+// External tools report local file names, manifest makes it possible
+// to map them back without touching the rest of the tool output.
+func (c *CheckerBase) manifest() map[string]*File {
+	m := make(map[string]*File, len(c.files))
+	for _, f := range c.files {
+		m[f.tempName] = f
+	}
+	return m
+}
+
+var docFileRE = regexp.MustCompile(`^(?:README|CONTRIBUTING|TODO).*`)
+
+func isDocumentationFile(filename string) bool {
+	return docFileRE.MatchString(filename)
+}
+
+// misspellConfig configures the misspell checker.
+type misspellConfig struct {
+	// SourceComments enables the source file comments checks.
+	SourceComments bool `yaml:"source_comments"`
+}
+
+// misspellMaxSourceSize limits the size of the source files
+// which comments are checked.
+const misspellMaxSourceSize = 1 << 20
+
+type misspellChecker struct {
+	CheckerBase
+	replacer *misspell.Replacer
+
+	// sourceComments is set by the misspell config section.
+	sourceComments bool
+}
+
+func newMisspellChecker() *misspellChecker {
+	return &misspellChecker{replacer: misspell.New()}
+}
+
+// Typos in generated files must be fixed in their sources.
+func (c *misspellChecker) skipGenerated() {}
+
+func (c *misspellChecker) PushFile(f *File) {
+	isComments := c.sourceComments && isSourceFile(f.baseName) && f.size <= misspellMaxSourceSize
+	if isDocumentationFile(f.baseName) || isComments {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *misspellChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	for _, f := range c.files {
+		if ctx.Err() != nil {
+			break
+		}
+		text := f.contents
+		if comments, ok := sourceLiterals(f.baseName, f.contents, false); ok {
+			// Identifiers and string literals are not English words.
+			text = comments
+		}
+		_, diffs := c.replacer.Replace(text)
+		for _, d := range diffs {
+			w := fmt.Sprintf("%s:%d:%d: %q is a misspelling of %q",
+				f.origName, d.Line, d.Column, d.Original, d.Corrected)
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// unwantedFileKind describes files that should not be committed.
+type unwantedFileKind struct {
+	// re matches the file base names.
+	// For kinds with a path, it matches the file paths.
+	re   *regexp.Regexp
+	path bool
+
+	// gitignore is a .gitignore entry that excludes such files.
+	gitignore string
+
+	// ide reports whether these are IDE project files,
+	// which some teams commit on purpose.
+	ide bool
+}
+
+func (k unwantedFileKind) match(f *File) bool {
+	if k.path {
+		return k.re.MatchString(f.origName)
+	}
+	return k.re.MatchString(f.baseName)
+}
+
+// unwantedFileKinds maps a kind name to its description.
+var unwantedFileKinds = map[string]unwantedFileKind{
+	// -> foo.txt.swp
+	"Vim swap": {re: regexp.MustCompile(`^.*\.swp$`), gitignore: "*.swp"},
+	// -> #foo.txt#
+	"Emacs autosave": {re: regexp.MustCompile(`^#.*#$`), gitignore: `\#*#`},
+	// -> foo.txt~
+	"Emacs backup": {re: regexp.MustCompile(`^.*~$`), gitignore: "*~"},
+	// -> .#foo.txt
+	"Emacs lock file": {re: regexp.MustCompile(`^\.#.*$`), gitignore: ".#*"},
+	// -> .DS_STORE
+	"Mac OS sys file": {re: regexp.MustCompile(`^\.DS_STORE$`), gitignore: ".DS_STORE"},
+	// -> Thumbs.db
+	"Windows sys file": {re: regexp.MustCompile(`^Thumbs\.db$`), gitignore: "Thumbs.db"},
+	// -> .idea/workspace.xml
+	"IntelliJ IDEA": {re: regexp.MustCompile(`(?:^|/)\.idea/`), path: true, gitignore: ".idea/", ide: true},
+	// -> app.iml
+	"IntelliJ module": {re: regexp.MustCompile(`^.*\.iml$`), gitignore: "*.iml", ide: true},
+	// -> .vscode/settings.json
+	"VS Code settings": {re: regexp.MustCompile(`(?:^|/)\.vscode/settings\.json$`), path: true, gitignore: ".vscode/settings.json", ide: true},
+	// -> app.sublime-workspace
+	"Sublime Text workspace": {re: regexp.MustCompile(`^.*\.sublime-workspace$`), gitignore: "*.sublime-workspace", ide: true},
+	// -> .project
+	"Eclipse project": {re: regexp.MustCompile(`^\.project$`), gitignore: ".project", ide: true},
+}
+
+// unwantedFileKindNames are the unwantedFileKinds keys in a stable order.
+var unwantedFileKindNames = func() []string {
+	names := make([]string, 0, len(unwantedFileKinds))
+	for name := range unwantedFileKinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}()
+
+// lookupUnwantedFile returns the kind of an unwanted file.
+// IDE project files are only reported if allowIDE is false.
+// Reports false if the file is not unwanted.
+func lookupUnwantedFile(f *File, allowIDE bool) (string, unwantedFileKind, bool) {
+	for _, name := range unwantedFileKindNames {
+		k := unwantedFileKinds[name]
+		if (!k.ide || !allowIDE) && k.match(f) {
+			return name, k, true
+		}
+	}
+	return "", unwantedFileKind{}, false
+}
+
+type unwantedFileChecker struct {
+	CheckerBase
+	fixBase
+
+	// allowIDE disables IDE project files reports, see allow_ide_files config option.
+	allowIDE bool
+}
+
+func newUnwantedFileChecker() *unwantedFileChecker {
+	return &unwantedFileChecker{}
+}
+
+// Vendored trees are copied as is.
+func (c *unwantedFileChecker) skipGenerated() {}
+
+// Results depend on the full path, like .idea/, and the allow_ide_files config option.
+func (c *unwantedFileChecker) uncachedResults() {}
+
+func (c *unwantedFileChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	c.fixes = c.fixes[:0]
+	for _, f := range c.files {
+		kind, _, ok := lookupUnwantedFile(f, c.allowIDE)
+		if !ok {
+			continue
+		}
+		w := fmt.Sprintf("remove %s file: %s", kind, f.origName)
+		warnings = append(warnings, w)
+		c.fixes = append(c.fixes, &Fix{File: f.origName, Delete: true})
+	}
+	return warnings
+}
+
+type sloppyCopyrightChecker struct {
+	CheckerBase
+	copyrightRE *regexp.Regexp
+}
+
+func newSloppyCopyrightChecker() *sloppyCopyrightChecker {
+	alternatives := []string{
+		`copyright year,?\s*fullname`,
+		`copyright \(c\)\s*year,?\s*fullname`,
+		`copyright ©\s*year,?\s*fullname`,
+	}
+
+	pattern := `(?i)` + strings.Join(alternatives, "|")
+	re := regexp.MustCompile(pattern)
+	return &sloppyCopyrightChecker{copyrightRE: re}
+}
+
+func (c *sloppyCopyrightChecker) PushFile(f *File) {
+	// Only check root files.
+	switch f.origName {
+	case "LICENSE", "LICENSE.md", "LICENSE.txt":
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *sloppyCopyrightChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	for _, f := range c.files {
+		if c.copyrightRE.MatchString(f.contents) {
+			w := fmt.Sprintf("%s: license contains sloppy copyright", f.origName)
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+type acronymChecker struct {
+	CheckerBase
+	fixBase
+	acronymRE  *regexp.Regexp
+	acronymMap map[string]string
+}
+
+func newAcronymChecker() *acronymChecker {
+	fromTo := map[string]string{
+		// TODO: more of these.
+
+		"gnu":  "GNU",
+		"sql":  "SQL",
+		"dsl":  "DSL",
+		"ansi": "ANSI",
+		"bios": "BIOS",
+		"cgi":  "CGI",
+		"ssa":  "SSA",
+		"dpi":  "DPI",
+		"gui":  "GUI",
+		"oop":  "OOP",
+	}
+
+	parts := make([]string, 0, len(fromTo))
+	for from := range fromTo {
+		parts = append(parts, `(?:^|\s)`+from+`(?:$|\s)`)
+	}
+
+	re := regexp.MustCompile(strings.Join(parts, "|"))
+	return &acronymChecker{
+		acronymMap: fromTo,
+		acronymRE:  re,
+	}
+}
+
+// Generated docs follow their generator style.
+func (c *acronymChecker) skipGenerated() {}
+
+func (c *acronymChecker) PushFile(f *File) {
+	if isDocumentationFile(f.baseName) {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *acronymChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	c.fixes = c.fixes[:0]
+	for _, f := range c.files {
+		if ctx.Err() != nil {
+			break
+		}
+		offset := 0
+		for i, l := range f.lines() {
+			for _, loc := range c.acronymRE.FindAllStringIndex(l, -1) {
+				m := strings.TrimSpace(l[loc[0]:loc[1]])
+				w := fmt.Sprintf("%s:%d: replace %s with %s",
+					f.origName, i+1, m, c.acronymMap[m])
+				warnings = append(warnings, w)
+				start := offset + loc[0] + strings.Index(l[loc[0]:loc[1]], m)
+				c.fixes = append(c.fixes, &Fix{
+					File:        f.origName,
+					Start:       start,
+					End:         start + len(m),
+					Replacement: c.acronymMap[m],
+				})
+			}
+			offset += len(l) + len("\n")
+		}
+	}
+	return warnings
+}
+
+type varTypoChecker struct {
+	CheckerBase
+	fixBase
+	varsRE  *regexp.Regexp
+	varsMap map[string]string
+}
+
+func newVarTypoChecker() *varTypoChecker {
+	typos := map[string]string{
+		// TODO: more of these.
+
+		"PAHT": "PATH",
+		"HOEM": "HOME",
+
+		"GOPAHT": "GOPATH",
+
+		"JAAV_HOME": "JAVA_HOME",
+		"JAVA_HOEM": "JAVA_HOME",
+		"JAVE_HOME": "JAVA_HOME",
+
+		"CLASSPAHT": "CLASSPATH",
+		"CLASPATH":  "CLASSPATH",
+	}
+
+	fromTo := make(map[string]string)
+	parts := make([]string, 0, len(fromTo))
+	for typo, corrected := range typos {
+		parts = append(parts, `\$`+typo+`\b`)
+		fromTo[`$`+typo] = corrected
+		parts = append(parts, `\$\{`+typo+`\}`)
+		fromTo[`${`+typo+`}`] = corrected
+	}
+
+	re := regexp.MustCompile(strings.Join(parts, "|"))
+	return &varTypoChecker{
+		varsMap: fromTo,
+		varsRE:  re,
+	}
+}
+
+// Generated files are fixed in their sources, not in the repository.
+func (c *varTypoChecker) skipGenerated() {}
+
+func (c *varTypoChecker) PushFile(f *File) {
+	if isDocumentationFile(f.baseName) {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *varTypoChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	c.fixes = c.fixes[:0]
+	for _, f := range c.files {
+		if ctx.Err() != nil {
+			break
+		}
+		lines := strings.Split(f.contents, "\n")
+		offset := 0
+		for i, l := range lines {
+			for _, loc := range c.varsRE.FindAllStringIndex(l, -1) {
+				m := l[loc[0]:loc[1]]
+				w := fmt.Sprintf("%s:%d: %s could be a misspelling of %s",
+					f.origName, i+1, m, c.varsMap[m])
+				warnings = append(warnings, w)
+				replacement := "$" + c.varsMap[m]
+				if strings.HasPrefix(m, "${") {
+					replacement = "${" + c.varsMap[m] + "}"
+				}
+				c.fixes = append(c.fixes, &Fix{
+					File:        f.origName,
+					Start:       offset + loc[0],
+					End:         offset + loc[1],
+					Replacement: replacement,
+				})
+			}
+			offset += len(l) + len("\n")
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,247 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// codeownersLocations are the CODEOWNERS file paths in the order github looks them up,
+// only the first found file is used.
+var codeownersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// codeownersMaxSize is the largest CODEOWNERS file github loads.
+const codeownersMaxSize = 3 << 20
+
+var (
+	// codeownerUserRE matches user owners, like "@octocat".
+	codeownerUserRE = regexp.MustCompile(`^@[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?$`)
+
+	// codeownerTeamRE matches team owners, like "@octo-org/docs-team".
+	codeownerTeamRE = regexp.MustCompile(`^@([A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?)/([A-Za-z0-9_.-]+)$`)
+
+	// codeownerEmailRE matches email owners.
+	codeownerEmailRE = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+)
+
+// codeownersRule is a CODEOWNERS line with a pattern and its owners.
+type codeownersRule struct {
+	line    int
+	pattern string
+	owners  []string
+}
+
+// codeownersChecker validates the CODEOWNERS file syntax and finds patterns
+// that match no files. With a token, it also finds owners that don't exist.
+type codeownersChecker struct {
+	CheckerBase
+
+	// paths are all repository file and directory paths.
+	paths []string
+
+	// tree is the checked repository tree index, if available.
+	tree *repoTree
+
+	// org is the owner of the checked repository,
+	// team owners from other organizations are not allowed.
+	org string
+
+	// unknown are the owners that don't exist, nil if they're not looked up.
+	unknown map[string]bool
+}
+
+func newCodeownersChecker() *codeownersChecker {
+	return &codeownersChecker{}
+}
+
+func (c *codeownersChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.paths = nil
+	c.tree = nil
+	c.unknown = nil
+}
+
+func (c *codeownersChecker) PushFile(f *File) {
+	c.paths = append(c.paths, f.origName)
+	if containsString(codeownersLocations, f.origName) && !f.dir && f.size <= codeownersMaxSize {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+// Patterns are matched against all repository files.
+func (c *codeownersChecker) fullTree() {}
+
+// Vendored and excluded files are not pushed to the checkers,
+// but CODEOWNERS rules may refer to them.
+func (c *codeownersChecker) setTree(t *repoTree) {
+	c.tree = t
+}
+
+// Results depend on the owner lookups.
+func (c *codeownersChecker) uncachedResults() {}
+
+func (c *codeownersChecker) setRepoURL(u string) {
+	c.org = ""
+	if u != "" {
+		c.org = path.Base(path.Dir(u))
+	}
+}
+
+// file returns the CODEOWNERS file github uses.
+func (c *codeownersChecker) file() *File {
+	for _, loc := range codeownersLocations {
+		for _, f := range c.files {
+			if f.origName == loc {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// referencedOwners returns the valid user and team owners, like "@octocat".
+func (c *codeownersChecker) referencedOwners() []string {
+	f := c.file()
+	if f == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var owners []string
+	rules, _ := parseCodeowners(f.contents)
+	for _, r := range rules {
+		for _, owner := range r.owners {
+			valid := codeownerUserRE.MatchString(owner) || codeownerTeamRE.MatchString(owner)
+			if valid && !seen[strings.ToLower(owner)] {
+				seen[strings.ToLower(owner)] = true
+				owners = append(owners, owner)
+			}
+		}
+	}
+	return owners
+}
+
+func (c *codeownersChecker) setUnknownOwners(unknown map[string]bool) {
+	c.unknown = unknown
+}
+
+func (c *codeownersChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	f := c.file()
+	if f == nil {
+		return nil
+	}
+	for _, other := range c.files {
+		if other != f {
+			w := fmt.Sprintf("%s: ignored, github uses %s", other.origName, f.origName)
+			warnings = append(warnings, w)
+		}
+	}
+
+	rules, syntaxWarnings := parseCodeowners(f.contents)
+	for _, w := range syntaxWarnings {
+		warnings = append(warnings, f.origName+":"+w)
+	}
+	paths := c.paths
+	if c.tree != nil {
+		paths = make([]string, 0, len(c.tree.paths))
+		for p := range c.tree.paths {
+			paths = append(paths, p)
+		}
+	}
+	tree := codeownersTree(paths)
+	for _, r := range rules {
+		re, err := compileGlob(strings.TrimSuffix(r.pattern, "/"))
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s:%d: bad pattern %q: %v", f.origName, r.line, r.pattern, err))
+			continue
+		}
+		if !matchesAny(re, tree) {
+			w := fmt.Sprintf("%s:%d: pattern %s matches no files", f.origName, r.line, r.pattern)
+			warnings = append(warnings, w)
+		}
+		for _, owner := range r.owners {
+			if w := c.checkOwner(owner); w != "" {
+				warnings = append(warnings, fmt.Sprintf("%s:%d: %s", f.origName, r.line, w))
+			}
+		}
+	}
+	return warnings
+}
+
+// checkOwner describes the owner problem, empty string for valid owners.
+func (c *codeownersChecker) checkOwner(owner string) string {
+	if m := codeownerTeamRE.FindStringSubmatch(owner); m != nil {
+		switch {
+		case c.org != "" && !strings.EqualFold(m[1], c.org):
+			return fmt.Sprintf("team %s is not in the %s organization", owner, c.org)
+		case c.unknown[strings.ToLower(owner)]:
+			return fmt.Sprintf("team %s doesn't exist", owner)
+		}
+		return ""
+	}
+	switch {
+	case codeownerUserRE.MatchString(owner):
+		if c.unknown[strings.ToLower(owner)] {
+			return fmt.Sprintf("user %s doesn't exist", owner)
+		}
+		return ""
+	case codeownerEmailRE.MatchString(owner):
+		return ""
+	default:
+		return fmt.Sprintf("invalid owner %q, expected @user, @org/team or an email", owner)
+	}
+}
+
+// parseCodeowners returns the CODEOWNERS rules and the syntax problems
+// prefixed by the line numbers, like "12: ...".
+func parseCodeowners(contents string) (rules []codeownersRule, warnings []string) {
+	for i, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if j := strings.Index(line, " #"); j != -1 {
+			line = strings.TrimSpace(line[:j])
+		}
+		fields := strings.Fields(line)
+		r := codeownersRule{line: i + 1, pattern: fields[0], owners: fields[1:]}
+		switch {
+		case strings.HasPrefix(r.pattern, "!"):
+			warnings = append(warnings, fmt.Sprintf("%d: negated pattern %s is not supported", r.line, r.pattern))
+			continue
+		case strings.ContainsAny(r.pattern, "[]"):
+			warnings = append(warnings, fmt.Sprintf("%d: character ranges in pattern %s are not supported", r.line, r.pattern))
+			continue
+		}
+		rules = append(rules, r)
+	}
+	return rules, warnings
+}
+
+// codeownersTree returns sorted paths along with all their parent directories.
+func codeownersTree(paths []string) []string {
+	seen := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		for ; p != "." && p != "/" && !seen[p]; p = path.Dir(p) {
+			seen[p] = true
+		}
+	}
+	tree := make([]string, 0, len(seen))
+	for p := range seen {
+		tree = append(tree, p)
+	}
+	sort.Strings(tree)
+	return tree
+}
+
+func matchesAny(re *regexp.Regexp, paths []string) bool {
+	for _, p := range paths {
+		if re.MatchString(p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package lint
+
+import (
+	"path"
+	"strings"
+)
+
+// sourceSyntax describes the comments and string literals of a language.
+// It's enough to tell them apart from the code, it's not a full lexer.
+type sourceSyntax struct {
+	lineComments []string
+
+	// blockComment is a pair of block comment delimiters, if any.
+	blockComment [2]string
+
+	// quotes are the string literal delimiters.
+	quotes string
+
+	// multiline are the quotes of the literals that may span lines,
+	// like Go raw strings or JavaScript template literals.
+	multiline string
+
+	// raw are the quotes of the literals without escape sequences.
+	raw string
+
+	// tripleQuotes reports whether """ and ''' strings are supported.
+	tripleQuotes bool
+}
+
+var (
+	cSyntax = &sourceSyntax{
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       `"'`,
+	}
+	jsSyntax = &sourceSyntax{
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+		multiline:    "`",
+	}
+	jvmSyntax = &sourceSyntax{
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       `"'`,
+		tripleQuotes: true,
+	}
+)
+
+// sourceSyntaxes maps source file extensions to their syntax.
+var sourceSyntaxes = map[string]*sourceSyntax{
+	".c": cSyntax, ".h": cSyntax, ".cpp": cSyntax, ".cc": cSyntax, ".cxx": cSyntax,
+	".hpp": cSyntax, ".hh": cSyntax, ".hxx": cSyntax, ".cs": cSyntax, ".java": cSyntax,
+
+	".js": jsSyntax, ".mjs": jsSyntax, ".cjs": jsSyntax, ".jsx": jsSyntax, ".ts": jsSyntax, ".tsx": jsSyntax,
+
+	".kt": jvmSyntax, ".kts": jvmSyntax, ".scala": jvmSyntax, ".swift": jvmSyntax,
+
+	".go": {
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+		multiline:    "`",
+		raw:          "`",
+	},
+	".rs": {
+		// Single quotes are also lifetimes, like 'a, and chars are not interesting.
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       `"`,
+		multiline:    `"`,
+	},
+	".php": {
+		lineComments: []string{"//", "#"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       `"'`,
+		multiline:    `"'`,
+	},
+	".py": {
+		lineComments: []string{"#"},
+		quotes:       `"'`,
+		tripleQuotes: true,
+	},
+	".rb": {
+		lineComments: []string{"#"},
+		blockComment: [2]string{"\n=begin", "\n=end"},
+		quotes:       "\"'`",
+		multiline:    "\"'`",
+	},
+}
+
+// sourceLiterals returns the source file contents where everything except
+// comments and, if withStrings is set, string literals is replaced with spaces.
+// Line breaks are kept, so positions inside the result match the original ones.
+// Reports false for the files of unknown languages.
+func sourceLiterals(filename, contents string, withStrings bool) (string, bool) {
+	syntax := sourceSyntaxes[path.Ext(filename)]
+	if syntax == nil {
+		return "", false
+	}
+
+	buf := []byte(contents)
+	// keep marks the [from, to) range as a comment or string contents.
+	keep := make([]bool, len(buf))
+	mark := func(from, to int) {
+		for i := from; i < to; i++ {
+			keep[i] = true
+		}
+	}
+
+	for i := 0; i < len(contents); {
+		rest := contents[i:]
+		if open := syntax.blockComment[0]; open != "" && strings.HasPrefix(rest, open) {
+			end := strings.Index(rest[len(open):], syntax.blockComment[1])
+			if end == -1 {
+				end = len(rest) - len(open)
+			}
+			mark(i+len(open), i+len(open)+end)
+			i += len(open) + end + len(syntax.blockComment[1])
+			continue
+		}
+		if lineComment := hasAnyPrefix(rest, syntax.lineComments); lineComment != "" {
+			end := strings.IndexByte(rest, '\n')
+			if end == -1 {
+				end = len(rest)
+			}
+			mark(i+len(lineComment), i+end)
+			i += end
+			continue
+		}
+		q := rest[0]
+		if strings.IndexByte(syntax.quotes, q) == -1 {
+			i++
+			continue
+		}
+		if triple := strings.Repeat(string(q), 3); syntax.tripleQuotes && strings.HasPrefix(rest, triple) {
+			end := strings.Index(rest[3:], triple)
+			if end == -1 {
+				end = len(rest) - 3
+			}
+			if withStrings {
+				mark(i+3, i+3+end)
+			}
+			i += 3 + end + 3
+			continue
+		}
+		raw := strings.IndexByte(syntax.raw, q) != -1
+		multiline := strings.IndexByte(syntax.multiline, q) != -1
+		end := 1
+		for ; end < len(rest); end++ {
+			if rest[end] == '\\' && !raw {
+				end++
+				continue
+			}
+			// Unterminated literals end at the line end, so a misplaced quote
+			// doesn't swallow the rest of the file.
+			if rest[end] == q || (rest[end] == '\n' && !multiline) {
+				break
+			}
+		}
+		if end > len(rest) {
+			end = len(rest)
+		}
+		if withStrings {
+			mark(i+1, i+end)
+		}
+		i += end + 1
+	}
+
+	for i := range buf {
+		if !keep[i] && buf[i] != '\n' {
+			buf[i] = ' '
+		}
+	}
+	return string(buf), true
+}
+
+func hasAnyPrefix(s string, prefixes []string) string {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return p
+		}
+	}
+	return ""
+}
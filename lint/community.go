@@ -0,0 +1,109 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// communityFile describes a community health file, like CONTRIBUTING.
+type communityFile struct {
+	// name is a file name used in the config and warnings.
+	name string
+
+	// re matches the file base names, like CONTRIBUTING.md or contributing.rst.
+	re *regexp.Regexp
+
+	// dirs are the directories GitHub looks for the file in.
+	dirs []string
+}
+
+// communityFiles are all community files known to the community files checker.
+var communityFiles = []communityFile{
+	{
+		name: "LICENSE",
+		re:   regexp.MustCompile(`(?i)^(?:LICEN[CS]E|COPYING|UNLICENSE)(?:[-._].*)?$`),
+		dirs: []string{"."},
+	},
+	{
+		name: "README",
+		re:   regexp.MustCompile(`(?i)^README(?:\..*)?$`),
+		dirs: []string{".", ".github", "docs"},
+	},
+	{
+		name: "CONTRIBUTING",
+		re:   regexp.MustCompile(`(?i)^CONTRIBUTING(?:\..*)?$`),
+		dirs: []string{".", ".github", "docs"},
+	},
+	{
+		name: "CODE_OF_CONDUCT.md",
+		re:   regexp.MustCompile(`(?i)^CODE[-_]OF[-_]CONDUCT(?:\..*)?$`),
+		dirs: []string{".", ".github", "docs"},
+	},
+	{
+		name: "SECURITY.md",
+		re:   regexp.MustCompile(`(?i)^SECURITY(?:\..*)?$`),
+		dirs: []string{".", ".github", "docs"},
+	},
+}
+
+// lookupCommunityFile returns a community file by its config name.
+func lookupCommunityFile(name string) (communityFile, bool) {
+	for _, cf := range communityFiles {
+		if strings.EqualFold(cf.name, name) || strings.EqualFold(strings.TrimSuffix(cf.name, ".md"), name) {
+			return cf, true
+		}
+	}
+	return communityFile{}, false
+}
+
+// communityFilesChecker finds repositories without community health files,
+// like LICENSE or CONTRIBUTING.
+type communityFilesChecker struct {
+	CheckerBase
+
+	// required are the checked community files, all of them by default.
+	required []communityFile
+}
+
+func newCommunityFilesChecker() *communityFilesChecker {
+	return &communityFilesChecker{required: communityFiles}
+}
+
+func (c *communityFilesChecker) PushFile(f *File) {
+	// Only directories where GitHub looks for the files matter.
+	switch path.Dir(f.origName) {
+	case ".", ".github", "docs":
+		c.AcceptFile(f)
+	}
+}
+
+// Missing files are found using all repository files.
+func (c *communityFilesChecker) fullTree() {}
+
+func (c *communityFilesChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	for _, cf := range c.required {
+		if !c.has(cf) {
+			w := fmt.Sprintf("%s: missing community file", cf.name)
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+func (c *communityFilesChecker) has(cf communityFile) bool {
+	for _, f := range c.files {
+		if !cf.re.MatchString(f.baseName) {
+			continue
+		}
+		dir := path.Dir(f.origName)
+		for _, d := range cf.dirs {
+			if dir == d {
+				return true
+			}
+		}
+	}
+	return false
+}
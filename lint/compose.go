@@ -0,0 +1,364 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// composeFileNames are Docker Compose file names.
+var composeFileNames = map[string]bool{
+	"docker-compose.yml":  true,
+	"docker-compose.yaml": true,
+	"compose.yml":         true,
+	"compose.yaml":        true,
+}
+
+// composeFile is a part of the Compose file schema that is validated.
+type composeFile struct {
+	Version  interface{}               `yaml:"version"`
+	Services map[string]composeService `yaml:"services"`
+	Include  interface{}               `yaml:"include"`
+}
+
+type composeService struct {
+	Image   string          `yaml:"image"`
+	Build   *composeBuild   `yaml:"build"`
+	EnvFile composeEnvFiles `yaml:"env_file"`
+
+	// Extends is a base service, either a name or a mapping with a file.
+	// Image and build are inherited from it.
+	Extends interface{} `yaml:"extends"`
+}
+
+type composeBuild struct {
+	Context          string `yaml:"context"`
+	Dockerfile       string `yaml:"dockerfile"`
+	DockerfileInline string `yaml:"dockerfile_inline"`
+}
+
+// UnmarshalYAML handles the short build syntax, where only a context is specified.
+func (b *composeBuild) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var context string
+	if err := unmarshal(&context); err == nil {
+		*b = composeBuild{Context: context}
+		return nil
+	}
+	type plain composeBuild
+	return unmarshal((*plain)(b))
+}
+
+type composeEnvFile struct {
+	Path     string `yaml:"path"`
+	Required *bool  `yaml:"required"`
+}
+
+// UnmarshalYAML handles env_file entries that are plain paths.
+func (e *composeEnvFile) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var path string
+	if err := unmarshal(&path); err == nil {
+		*e = composeEnvFile{Path: path}
+		return nil
+	}
+	type plain composeEnvFile
+	return unmarshal((*plain)(e))
+}
+
+type composeEnvFiles []composeEnvFile
+
+// UnmarshalYAML handles a single env_file specified without a list.
+func (e *composeEnvFiles) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var path string
+	if err := unmarshal(&path); err == nil {
+		*e = composeEnvFiles{{Path: path}}
+		return nil
+	}
+	var list []composeEnvFile
+	if err := unmarshal(&list); err != nil {
+		return err
+	}
+	*e = list
+	return nil
+}
+
+// devcontainerConfig is a part of the devcontainer.json schema that is validated.
+type devcontainerConfig struct {
+	Image             string             `json:"image"`
+	Dockerfile        string             `json:"dockerFile"`
+	Build             *devcontainerBuild `json:"build"`
+	DockerComposeFile interface{}        `json:"dockerComposeFile"`
+	Service           string             `json:"service"`
+}
+
+type devcontainerBuild struct {
+	Dockerfile string `json:"dockerfile"`
+	Context    string `json:"context"`
+}
+
+// isDevcontainerFile reports whether filename is a dev container configuration.
+func isDevcontainerFile(filename string) bool {
+	if filename == ".devcontainer.json" {
+		return true
+	}
+	// Both .devcontainer/devcontainer.json and .devcontainer/<name>/devcontainer.json are used.
+	return strings.HasPrefix(filename, ".devcontainer/") && path.Base(filename) == "devcontainer.json" &&
+		strings.Count(filename, "/") <= 2
+}
+
+// containerConfigChecker validates Docker Compose files and dev container configs:
+// syntax and schema errors, references to missing files and deprecated fields.
+type containerConfigChecker struct {
+	CheckerBase
+
+	// paths is a set of all repository file and directory paths.
+	paths map[string]bool
+
+	// tree is the checked repository tree index, if available.
+	tree *repoTree
+}
+
+func newContainerConfigChecker() *containerConfigChecker {
+	return &containerConfigChecker{}
+}
+
+func (c *containerConfigChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.paths = make(map[string]bool)
+	c.tree = nil
+}
+
+func (c *containerConfigChecker) PushFile(f *File) {
+	c.paths[f.origName] = true
+	for dir := path.Dir(f.origName); dir != "."; dir = path.Dir(dir) {
+		c.paths[dir] = true
+	}
+	if composeFileNames[f.baseName] || isDevcontainerFile(f.origName) {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+// References are checked against all repository files.
+func (c *containerConfigChecker) fullTree() {}
+
+// Vendored and excluded files are not pushed to the checkers,
+// but build contexts and env files may be there.
+func (c *containerConfigChecker) setTree(t *repoTree) {
+	c.tree = t
+}
+
+func (c *containerConfigChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	for _, f := range c.files {
+		if composeFileNames[f.baseName] {
+			warnings = append(warnings, c.checkCompose(f)...)
+		} else {
+			warnings = append(warnings, c.checkDevcontainer(f)...)
+		}
+	}
+	return warnings
+}
+
+// missing reports whether a path relative to dir doesn't exist in the repository.
+// Paths that can't be resolved statically, like absolute paths,
+// URLs and paths with variables, are never missing.
+func (c *containerConfigChecker) missing(dir, p string) bool {
+	if p == "" || path.IsAbs(p) || strings.Contains(p, "$") || strings.Contains(p, "://") ||
+		strings.HasPrefix(p, "git@") || strings.HasPrefix(p, "~") {
+		return false
+	}
+	full := path.Join(dir, p)
+	if full == "." {
+		return false
+	}
+	if full == ".." || strings.HasPrefix(full, "../") {
+		// Outside of the repository.
+		return false
+	}
+	if c.tree != nil {
+		return !c.tree.has(full)
+	}
+	return !c.paths[full]
+}
+
+func (c *containerConfigChecker) checkCompose(f *File) (warnings []string) {
+	var compose composeFile
+	if err := yaml.Unmarshal([]byte(f.contents), &compose); err != nil {
+		return []string{fmt.Sprintf("%s: %v", f.origName, err)}
+	}
+	if compose.Version != nil {
+		w := fmt.Sprintf("%s: version field is obsolete and ignored by Compose", f.origName)
+		warnings = append(warnings, w)
+	}
+	if len(compose.Services) == 0 && compose.Include == nil {
+		w := fmt.Sprintf("%s: no services defined", f.origName)
+		warnings = append(warnings, w)
+	}
+
+	dir := path.Dir(f.origName)
+	names := make([]string, 0, len(compose.Services))
+	for name := range compose.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := compose.Services[name]
+		if s.Image == "" && s.Build == nil && s.Extends == nil {
+			w := fmt.Sprintf("%s: service %q has neither image nor build", f.origName, name)
+			warnings = append(warnings, w)
+		}
+		if b := s.Build; b != nil {
+			buildContext := b.Context
+			if buildContext == "" {
+				buildContext = "."
+			}
+			if c.missing(dir, buildContext) {
+				w := fmt.Sprintf("%s: service %q build context %s doesn't exist", f.origName, name, buildContext)
+				warnings = append(warnings, w)
+			} else if b.DockerfileInline == "" {
+				dockerfile := b.Dockerfile
+				if dockerfile == "" {
+					dockerfile = "Dockerfile"
+				}
+				if c.missing(path.Join(dir, buildContext), dockerfile) {
+					w := fmt.Sprintf("%s: service %q dockerfile %s doesn't exist", f.origName, name, dockerfile)
+					warnings = append(warnings, w)
+				}
+			}
+		}
+		for _, env := range s.EnvFile {
+			if env.Required != nil && !*env.Required {
+				continue
+			}
+			if c.missing(dir, env.Path) {
+				w := fmt.Sprintf("%s: service %q env_file %s doesn't exist", f.origName, name, env.Path)
+				warnings = append(warnings, w)
+			}
+		}
+	}
+	return warnings
+}
+
+func (c *containerConfigChecker) checkDevcontainer(f *File) (warnings []string) {
+	var cfg devcontainerConfig
+	if err := json.Unmarshal([]byte(stripJSONComments(f.contents)), &cfg); err != nil {
+		return []string{fmt.Sprintf("%s: %v", f.origName, err)}
+	}
+
+	dir := path.Dir(f.origName)
+	dockerfile := cfg.Dockerfile
+	buildContext := ""
+	if cfg.Build != nil {
+		if cfg.Build.Dockerfile != "" {
+			dockerfile = cfg.Build.Dockerfile
+		}
+		buildContext = cfg.Build.Context
+	}
+	if dockerfile != "" && c.missing(dir, dockerfile) {
+		w := fmt.Sprintf("%s: dockerfile %s doesn't exist", f.origName, dockerfile)
+		warnings = append(warnings, w)
+	}
+	if buildContext != "" && c.missing(dir, buildContext) {
+		w := fmt.Sprintf("%s: build context %s doesn't exist", f.origName, buildContext)
+		warnings = append(warnings, w)
+	}
+
+	var composeFiles []string
+	switch v := cfg.DockerComposeFile.(type) {
+	case string:
+		composeFiles = []string{v}
+	case []interface{}:
+		for _, x := range v {
+			if s, ok := x.(string); ok {
+				composeFiles = append(composeFiles, s)
+			}
+		}
+	}
+	for _, name := range composeFiles {
+		if c.missing(dir, name) {
+			w := fmt.Sprintf("%s: docker compose file %s doesn't exist", f.origName, name)
+			warnings = append(warnings, w)
+		}
+	}
+	if len(composeFiles) != 0 && cfg.Service == "" {
+		w := fmt.Sprintf("%s: dockerComposeFile requires service field", f.origName)
+		warnings = append(warnings, w)
+	}
+	if cfg.Image == "" && dockerfile == "" && len(composeFiles) == 0 {
+		w := fmt.Sprintf("%s: no image, dockerfile or dockerComposeFile specified", f.origName)
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
+
+// stripJSONComments converts JSON with comments (JSONC) into plain JSON.
+// Comments are replaced with spaces and trailing commas are removed.
+func stripJSONComments(s string) string {
+	var buf strings.Builder
+	inString := false
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case inString:
+			buf.WriteByte(ch)
+			if ch == '\\' && i+1 < len(s) {
+				i++
+				buf.WriteByte(s[i])
+			} else if ch == '"' {
+				inString = false
+			}
+		case ch == '"':
+			inString = true
+			buf.WriteByte(ch)
+		case strings.HasPrefix(s[i:], "//"):
+			end := strings.IndexByte(s[i:], '\n')
+			if end == -1 {
+				end = len(s) - i
+			}
+			i += end - 1
+		case strings.HasPrefix(s[i:], "/*"):
+			end := strings.Index(s[i+2:], "*/")
+			if end == -1 {
+				end = len(s) - i - 2
+			}
+			// Keep newlines, so error offsets still point to the right lines.
+			buf.WriteString(strings.Repeat("\n", strings.Count(s[i:i+2+end], "\n")))
+			i += end + 3
+		case ch == ',':
+			rest := strings.TrimLeft(stripLeadingComments(s[i+1:]), " \t\r\n")
+			if !strings.HasPrefix(rest, "}") && !strings.HasPrefix(rest, "]") {
+				buf.WriteByte(ch)
+			}
+		default:
+			buf.WriteByte(ch)
+		}
+	}
+	return buf.String()
+}
+
+// stripLeadingComments removes whitespace and comments at the start of s.
+func stripLeadingComments(s string) string {
+	for {
+		s = strings.TrimLeft(s, " \t\r\n")
+		switch {
+		case strings.HasPrefix(s, "//"):
+			end := strings.IndexByte(s, '\n')
+			if end == -1 {
+				return ""
+			}
+			s = s[end:]
+		case strings.HasPrefix(s, "/*"):
+			end := strings.Index(s, "*/")
+			if end == -1 {
+				return ""
+			}
+			s = s[end+2:]
+		default:
+			return s
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package lint
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// config is a repolint configuration file contents.
+//
+// LinkTimeout and LinkExclude are overridden by the -link-timeout and
+// -link-exclude flags, -exclude patterns are added to Exclude.
+// Other options can only be set in the file.
+type config struct {
+	// Exclude is a list of path patterns that should not be checked.
+	// See compileGlob for the patterns syntax.
+	Exclude []string `yaml:"exclude"`
+
+	// Severity overrides default checkers severity.
+	// Keys are checker names.
+	Severity map[string]Severity `yaml:"severity"`
+
+	// LinkTimeout is a broken link checker timeout for a single link.
+	LinkTimeout time.Duration `yaml:"link_timeout"`
+
+	// LinkExclude is a list of regexps for links that are not checked.
+	// Replaces the default exclusions list.
+	LinkExclude []string `yaml:"link_exclude"`
+
+	// Template is a golden template repository that
+	// checked repositories should follow.
+	Template *templateConfig `yaml:"template"`
+
+	// CommunityFiles are the files required by the community files checker,
+	// like CONTRIBUTING or SECURITY.md. Nil means all known files.
+	CommunityFiles []string `yaml:"community_files"`
+
+	// AllowIDEFiles disables the IDE project files reports, like .idea/ or *.iml,
+	// for teams that commit editor settings on purpose.
+	AllowIDEFiles bool `yaml:"allow_ide_files"`
+
+	// Misspell configures the misspell checker.
+	Misspell *misspellConfig `yaml:"misspell"`
+
+	// ReadmeStructure enables the README length, title and sections checks.
+	ReadmeStructure *readmeStructureConfig `yaml:"readme_structure"`
+
+	// Copyright overrides the stale copyright checker threshold.
+	Copyright *copyrightConfig `yaml:"copyright"`
+
+	// DependencyDirs are the directories reported by the dependency dir checker,
+	// like node_modules. Nil means the default list.
+	DependencyDirs []string `yaml:"dependency_dirs"`
+
+	// I18n overrides the i18n resources checker settings.
+	I18n *i18nConfig `yaml:"i18n"`
+
+	// Migrations configures the migrations checker.
+	Migrations *migrationsConfig `yaml:"migrations"`
+
+	// Changelog configures the changelog checker.
+	Changelog *changelogConfig `yaml:"changelog"`
+
+	// IssueTemplates configures the issue templates checker.
+	IssueTemplates *issueTemplatesConfig `yaml:"issue_templates"`
+
+	// TODO enables the TODO checker.
+	TODO *todoConfig `yaml:"todo"`
+
+	// Dockerfile enables the Dockerfile checker.
+	Dockerfile *dockerfileConfig `yaml:"dockerfile"`
+
+	// Shellcheck enables the shellcheck checker.
+	Shellcheck *shellcheckConfig `yaml:"shellcheck"`
+
+	// NpmScripts enables the npm scripts checker.
+	NpmScripts *npmScriptsConfig `yaml:"npm_scripts"`
+
+	// ContributionFriction enables the contribution friction checker.
+	ContributionFriction *frictionConfig `yaml:"contribution_friction"`
+
+	// APIDocs enables the API docs checker.
+	APIDocs *apiDocsConfig `yaml:"api_docs"`
+
+	// HTMLAccessibility enables the HTML accessibility checker.
+	HTMLAccessibility *htmlA11yConfig `yaml:"html_accessibility"`
+
+	// LargeFiles overrides the large file checker threshold.
+	LargeFiles *largeFilesConfig `yaml:"large_files"`
+
+	// RepoSize overrides the repo size checker thresholds.
+	RepoSize *repoSizeConfig `yaml:"repo_size"`
+
+	// Pinning enables the dependency pinning checker.
+	Pinning *pinningConfig `yaml:"pinning"`
+
+	// Email enables the email digest of the new findings.
+	Email *emailConfig `yaml:"email"`
+
+	// Scoring configures the repository health score.
+	Scoring *scoringConfig `yaml:"scoring"`
+
+	// Plugins are external checkers, see pluginConfig.
+	Plugins []pluginConfig `yaml:"plugins"`
+}
+
+func loadConfig(filename string) (*config, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var cfg config
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %v", filename, err)
+	}
+	return &cfg, nil
+}
+
+// compileGlob converts a path pattern into a regexp.
+//
+// "*" matches any sequence of non-separator characters,
+// "**" matches any sequence of characters, including separators,
+// "?" matches any single non-separator character.
+// Patterns without "/" are matched against a file base name,
+// so "*.min.js" excludes minified files in all directories.
+func compileGlob(pattern string) (*regexp.Regexp, error) {
+	var buf strings.Builder
+	if strings.Contains(pattern, "/") {
+		buf.WriteString(`^`)
+	} else {
+		buf.WriteString(`(?:^|/)`)
+	}
+	pattern = strings.TrimPrefix(pattern, "/")
+	for i := 0; i < len(pattern); i++ {
+		switch ch := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			buf.WriteString(`(?:.*/)?`)
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			buf.WriteString(`.*`)
+			i++
+		case ch == '*':
+			buf.WriteString(`[^/]*`)
+		case ch == '?':
+			buf.WriteString(`[^/]`)
+		default:
+			// Quote bytes, not runes, so multi-byte characters are kept intact.
+			buf.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	buf.WriteString(`$`)
+	return regexp.Compile(buf.String())
+}
+
+// pathMatcher matches file paths against a list of patterns.
+type pathMatcher struct {
+	patterns []*regexp.Regexp
+}
+
+func newPathMatcher(patterns []string) (*pathMatcher, error) {
+	var m pathMatcher
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		re, err := compileGlob(p)
+		if err != nil {
+			return nil, fmt.Errorf("bad pattern %q: %v", p, err)
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	return &m, nil
+}
+
+func (m *pathMatcher) Match(path string) bool {
+	for _, re := range m.patterns {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
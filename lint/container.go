@@ -0,0 +1,66 @@
+package lint
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// externalChecker is implemented by the checkers that run external programs.
+type externalChecker interface {
+	externalTool() string
+}
+
+// flagEnvName returns an environment variable name for the flag.
+// For example, -max-api-calls becomes REPOLINT_MAX_API_CALLS.
+func flagEnvName(name string) string {
+	name = strings.ToUpper(strings.Replace(name, "-", "_", -1))
+	return "REPOLINT_" + name
+}
+
+// applyEnvFlags sets flag values from the environment variables.
+// Command-line arguments take precedence, since they're parsed later.
+func applyEnvFlags(fs *flag.FlagSet) error {
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		value, ok := os.LookupEnv(flagEnvName(f.Name))
+		if !ok || err != nil {
+			return
+		}
+		if setErr := fs.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("%s: %v", flagEnvName(f.Name), setErr)
+		}
+	})
+	return err
+}
+
+// initContainerMode makes sure nothing depends on external programs
+// or a user environment, so repolint can run inside a minimal container.
+// Results are printed to stdout as JSON.
+func (l *Runner) initContainerMode() error {
+	if !l.container {
+		return nil
+	}
+
+	if l.fetchMode != "api" {
+		return fmt.Errorf("-fetch=%s is not supported in container mode", l.fetchMode)
+	}
+	if l.publishURL != "" {
+		return fmt.Errorf("-publish is not supported in container mode")
+	}
+	for name, c := range l.checkers {
+		if c, ok := c.(externalChecker); ok {
+			if l.verbose {
+				log.Printf("\t\tdebug: disable %s checker: it requires %s", name, c.externalTool())
+			}
+			delete(l.checkers, name)
+		}
+	}
+	l.updateCheck = false
+	if l.jsonReport == "" {
+		l.jsonReport = "-"
+	}
+	return nil
+}
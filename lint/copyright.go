@@ -0,0 +1,98 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// copyrightConfig overrides the stale copyright checker threshold.
+type copyrightConfig struct {
+	// MaxLagYears is a number of years the copyright year
+	// can fall behind the last commit year.
+	MaxLagYears int `yaml:"max_lag_years"`
+}
+
+const defaultCopyrightMaxLag = 2
+
+var (
+	// copyrightYearsRE matches copyright notices with years,
+	// like "Copyright (c) 2015-2019, 2021 Author".
+	// The submatch group is a years list.
+	copyrightYearsRE = regexp.MustCompile(`(?im)^.*\bcopyright\b\s*(?:\(c\)|©)?\s*((?:(?:19|20)\d{2}\s*(?:[-–,]\s*)?)+)(present|now)?.*`)
+
+	copyrightYearRE = regexp.MustCompile(`(?:19|20)\d{2}`)
+
+	// copyrightFileRE matches root license and notice files.
+	copyrightFileRE = regexp.MustCompile(`^(?:LICEN[CS]E|COPYING|NOTICE)(?:[.-].*)?$`)
+)
+
+// staleCopyrightChecker finds license and notice files which latest
+// copyright year is far behind the last commit year.
+// Unlike sloppyCopyrightChecker, it only looks at the filled notices.
+type staleCopyrightChecker struct {
+	CheckerBase
+
+	maxLag int
+
+	// commitDate is the last commit date, zero if unknown.
+	commitDate time.Time
+}
+
+func newStaleCopyrightChecker() *staleCopyrightChecker {
+	return &staleCopyrightChecker{maxLag: defaultCopyrightMaxLag}
+}
+
+func (c *staleCopyrightChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.commitDate = time.Time{}
+}
+
+func (c *staleCopyrightChecker) PushFile(f *File) {
+	if f.origName == f.baseName && copyrightFileRE.MatchString(f.baseName) {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *staleCopyrightChecker) setCommitDate(t time.Time) {
+	c.commitDate = t
+}
+
+// Results depend on the last commit date.
+func (c *staleCopyrightChecker) uncachedResults() {}
+
+func (c *staleCopyrightChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	if c.commitDate.IsZero() {
+		return nil
+	}
+	commitYear := c.commitDate.Year()
+	for _, f := range c.files {
+		latest := 0
+		for _, m := range copyrightYearsRE.FindAllStringSubmatch(f.contents, -1) {
+			if strings.Contains(m[0], "Free Software Foundation") {
+				// GPL license texts have their own copyright notice.
+				continue
+			}
+			if m[2] != "" {
+				// Ranges like "2015-present" are always up to date.
+				latest = commitYear
+				break
+			}
+			for _, y := range copyrightYearRE.FindAllString(m[1], -1) {
+				if year, _ := strconv.Atoi(y); year > latest {
+					latest = year
+				}
+			}
+		}
+		if latest != 0 && commitYear-latest > c.maxLag {
+			w := fmt.Sprintf("%s: copyright year %d is %d years behind the last commit in %d",
+				f.origName, latest, commitYear-latest, commitYear)
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
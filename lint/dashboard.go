@@ -0,0 +1,230 @@
+package lint
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// checkerCategory returns a category of the checker,
+// "other" for plugins and checkers added with AddChecker.
+func checkerCategory(name string) string {
+	if info, ok := lookupChecker(name); ok && info.Category != "" {
+		return info.Category
+	}
+	return "other"
+}
+
+// dashboardRepo is a repository tile of the dashboard.
+type dashboardRepo struct {
+	*repoReport
+	Class string
+
+	// Page is a repository page path relative to the dashboard root.
+	Page string
+
+	// Categories are the warning counts by checker category,
+	// used by the category filter.
+	Categories map[string]int
+}
+
+// dashboardWarning is a warning row of a repository page.
+type dashboardWarning struct {
+	Warning
+	Category string
+}
+
+// dashboardCategories returns the categories that have warnings, sorted.
+func dashboardCategories(repos []*dashboardRepo) []string {
+	var categories []string
+	seen := make(map[string]bool)
+	for _, r := range repos {
+		for c := range r.Categories {
+			if !seen[c] {
+				seen[c] = true
+				categories = append(categories, c)
+			}
+		}
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// categoryData formats category counts for the data-categories attribute,
+// like "docs:2 security:1".
+func categoryData(counts map[string]int) string {
+	parts := make([]string, 0, len(counts))
+	for c, n := range counts {
+		parts = append(parts, fmt.Sprintf("%s:%d", c, n))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
+
+const dashboardStyle = `
+body { font-family: sans-serif; margin: 1em 2em; }
+.grid { display: flex; flex-wrap: wrap; gap: 8px; }
+.tile { display: block; width: 180px; padding: 8px; border-radius: 4px; color: #000; text-decoration: none; }
+.tile .score { font-size: 2em; font-weight: bold; }
+.good { background: #9be9a8; } .fair { background: #f9e076; } .poor { background: #f9a66c; } .bad { background: #f47067; }
+td, th { padding: 2px 8px; text-align: left; vertical-align: top; }
+.hidden { display: none; }
+`
+
+// dashboardFilterScript hides the elements without warnings
+// of the selected category and updates the tile counts.
+const dashboardFilterScript = `
+function filterCategory(category) {
+	document.querySelectorAll("[data-categories]").forEach(function(el) {
+		var n = 0;
+		el.dataset.categories.split(" ").forEach(function(kv) {
+			var parts = kv.split(":");
+			if (parts.length == 2 && (category == "" || parts[0] == category)) { n += +parts[1]; }
+		});
+		el.classList.toggle("hidden", category != "" && n == 0);
+		var count = el.querySelector(".count");
+		if (count) { count.textContent = n; }
+	});
+}
+`
+
+var dashboardFuncs = template.FuncMap{
+	"style":  func() template.CSS { return template.CSS(dashboardStyle) },
+	"script": func() template.JS { return template.JS(dashboardFilterScript) },
+}
+
+var dashboardIndexTemplate = template.Must(template.New("index").Funcs(dashboardFuncs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>repolint: {{.User}}</title>
+<style>{{style}}</style>
+<script>{{script}}</script>
+</head>
+<body>
+<h1>{{.User}}</h1>
+<p>Started {{.Started.Format "2006-01-02 15:04:05 MST"}}, checked {{len .Repos}} repositories, average score {{.Average}}.</p>
+<p><label>Category: <select onchange="filterCategory(this.value)">
+<option value="">all</option>
+{{range .Categories}}<option>{{.}}</option>
+{{end}}</select></label></p>
+<div class="grid">
+{{range .Repos}}<a class="tile {{.Class}}" href="{{.Page}}" data-categories="{{.CategoryData}}">
+<div>{{.Name}}</div>
+<div class="score">{{.Score}}</div>
+<div><span class="count">{{len .Warnings}}</span> warnings</div>
+</a>
+{{end}}</div>
+{{if .Skipped}}<h2>Skipped</h2>
+<ul>{{range .Skipped}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{if .Filtered}}<h2>Filtered</h2>
+<ul>{{range .Filtered}}<li>{{.Name}}: {{.Reason}}</li>{{end}}</ul>{{end}}
+</body>
+</html>
+`))
+
+var dashboardRepoTemplate = template.Must(template.New("repo").Funcs(dashboardFuncs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>repolint: {{.User}}/{{.Repo.Name}}</title>
+<style>{{style}}</style>
+<script>{{script}}</script>
+</head>
+<body>
+<p><a href="../index.html">{{.User}}</a></p>
+<h1>{{.Repo.Name}}{{if .Repo.Commit}} <small>{{.Repo.Commit}}</small>{{end}}</h1>
+<p class="tile {{.Repo.Class}}">Score <span class="score">{{.Repo.Score}}</span></p>
+{{if .Warnings}}<p><label>Category: <select onchange="filterCategory(this.value)">
+<option value="">all</option>
+{{range .Categories}}<option>{{.}}</option>
+{{end}}</select></label></p>
+<table>
+<tr><th>Severity</th><th>Category</th><th>Checker</th><th>Warning</th></tr>
+{{range .Warnings}}<tr data-categories="{{.Category}}:1"><td>{{.Severity}}</td><td>{{.Category}}</td><td>{{.Checker}}</td><td>{{.Text}}</td></tr>
+{{end}}</table>
+{{else}}<p>No warnings.</p>{{end}}
+</body>
+</html>
+`))
+
+// CategoryData returns the data-categories attribute value.
+func (r *dashboardRepo) CategoryData() string {
+	return categoryData(r.Categories)
+}
+
+// writeDashboard writes a static HTML dashboard into dir:
+// index.html with a repository grid colored by the compliance score
+// and a repos/<name>.html page with the findings for every repository.
+// The directory can be hosted as is, for example, with GitHub Pages.
+func (r *runReport) writeDashboard(dir string) error {
+	if err := os.MkdirAll(filepath.Join(dir, "repos"), 0755); err != nil {
+		return err
+	}
+
+	repos := make([]*dashboardRepo, 0, len(r.Repos))
+	total := 0
+	for _, rr := range r.Repos {
+		total += rr.Score
+		dr := &dashboardRepo{
+			repoReport: rr,
+			Class:      scoreClass(rr.Score),
+			Page:       "repos/" + rr.Name + ".html",
+			Categories: make(map[string]int),
+		}
+		for _, w := range rr.Warnings {
+			dr.Categories[checkerCategory(w.Checker)]++
+		}
+		repos = append(repos, dr)
+	}
+	// The worst repositories go first.
+	sort.SliceStable(repos, func(i, j int) bool {
+		return repos[i].Score < repos[j].Score
+	})
+	average := 100
+	if len(repos) != 0 {
+		average = total / len(repos)
+	}
+
+	index := struct {
+		*runReport
+		Repos      []*dashboardRepo
+		Categories []string
+		Average    int
+	}{r, repos, dashboardCategories(repos), average}
+	if err := writeTemplate(filepath.Join(dir, "index.html"), dashboardIndexTemplate, index); err != nil {
+		return err
+	}
+
+	for _, dr := range repos {
+		var warnings []dashboardWarning
+		for _, w := range dr.Warnings {
+			warnings = append(warnings, dashboardWarning{Warning: w, Category: checkerCategory(w.Checker)})
+		}
+		page := struct {
+			User       string
+			Repo       *dashboardRepo
+			Warnings   []dashboardWarning
+			Categories []string
+		}{r.User, dr, warnings, dashboardCategories([]*dashboardRepo{dr})}
+		if err := writeTemplate(filepath.Join(dir, filepath.FromSlash(dr.Page)), dashboardRepoTemplate, page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTemplate(filename string, tmpl *template.Template, data interface{}) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := tmpl.Execute(f, data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,123 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultDependencyDirs are the directories reported by the dependency dir checker.
+var defaultDependencyDirs = []string{"node_modules", "bower_components", "__pycache__", ".terraform"}
+
+// dependencyDirChecker finds committed dependency and cache directories,
+// like node_modules or __pycache__.
+type dependencyDirChecker struct {
+	CheckerBase
+
+	// names is a set of reported directory names.
+	names map[string]bool
+
+	// counts maps the outermost dependency directories to their file counts.
+	counts map[string]int
+
+	// untracked reports whether the files are listed from a working tree,
+	// so ignored directories are listed too.
+	untracked bool
+	rootDir   string
+
+	// tree is the checked repository tree index, if available.
+	tree *repoTree
+}
+
+func newDependencyDirChecker() *dependencyDirChecker {
+	c := &dependencyDirChecker{}
+	c.setNames(defaultDependencyDirs)
+	return c
+}
+
+func (c *dependencyDirChecker) setNames(names []string) {
+	c.names = make(map[string]bool)
+	for _, name := range names {
+		c.names[strings.Trim(name, "/")] = true
+	}
+}
+
+func (c *dependencyDirChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.counts = make(map[string]int)
+	c.untracked = false
+	c.rootDir = ""
+	c.tree = nil
+}
+
+// Vendored trees are copied as is.
+func (c *dependencyDirChecker) skipGenerated() {}
+
+// Directories are found using all repository files.
+func (c *dependencyDirChecker) fullTree() {}
+
+// Vendored and excluded files are not pushed to the checkers,
+// so the directories are found in the tree index when it's available.
+func (c *dependencyDirChecker) setTree(t *repoTree) {
+	c.tree = t
+}
+
+func (c *dependencyDirChecker) PushFile(f *File) {
+	if c.countFile(f) {
+		c.AcceptFile(f)
+	}
+}
+
+// countFile counts f if it's inside of a dependency directory.
+func (c *dependencyDirChecker) countFile(f *File) bool {
+	if f.dir {
+		return false
+	}
+	parts := strings.Split(f.origName, "/")
+	for i, part := range parts[:len(parts)-1] {
+		if c.names[part] {
+			c.counts[strings.Join(parts[:i+1], "/")]++
+			if f.sha == "" && f.rootDir != "" {
+				c.untracked = true
+				c.rootDir = f.rootDir
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (c *dependencyDirChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	if c.tree != nil {
+		c.counts = make(map[string]int)
+		for _, f := range c.tree.files {
+			c.countFile(f)
+		}
+	}
+	dirs := make([]string, 0, len(c.counts))
+	for dir := range c.counts {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		count := c.counts[dir]
+		if c.untracked {
+			// Local working trees list ignored files as well,
+			// so only the files known to git are counted.
+			out, err := gitOutput(ctx, c.rootDir, "ls-files", "--", dir)
+			if err != nil || out == "" {
+				continue
+			}
+			count = strings.Count(out, "\n") + 1
+		}
+		files := fmt.Sprintf("%d files", count)
+		if count == 1 {
+			files = "1 file"
+		}
+		w := fmt.Sprintf("%s/: committed dependency directory with %s, remove it and add it to .gitignore",
+			dir, files)
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
@@ -0,0 +1,181 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/client9/misspell"
+)
+
+// topicHint suggests a topic when a manifest file matches the pattern.
+type topicHint struct {
+	// files are manifest file names in the repository root.
+	files []string
+
+	// pattern should match the manifest contents.
+	// Nil pattern means that any manifest contents match.
+	pattern *regexp.Regexp
+
+	topic string
+}
+
+var topicHints = []topicHint{
+	{files: []string{"go.mod"}, topic: "golang"},
+	{files: []string{"go.mod"}, pattern: regexp.MustCompile(`github\.com/(?:spf13/cobra|urfave/cli)`), topic: "cli"},
+	{files: []string{"go.mod"}, pattern: regexp.MustCompile(`google\.golang\.org/grpc`), topic: "grpc"},
+	{files: []string{"go.mod"}, pattern: regexp.MustCompile(`github\.com/gin-gonic/gin`), topic: "gin"},
+
+	{files: []string{"package.json"}, pattern: regexp.MustCompile(`"typescript"\s*:`), topic: "typescript"},
+	{files: []string{"package.json"}, pattern: regexp.MustCompile(`"bin"\s*:`), topic: "cli"},
+	{files: []string{"package.json"}, pattern: regexp.MustCompile(`"react"\s*:`), topic: "react"},
+	{files: []string{"package.json"}, pattern: regexp.MustCompile(`"vue"\s*:`), topic: "vue"},
+	{files: []string{"package.json"}, pattern: regexp.MustCompile(`"@angular/core"\s*:`), topic: "angular"},
+	{files: []string{"package.json"}, pattern: regexp.MustCompile(`"express"\s*:`), topic: "express"},
+	{files: []string{"package.json"}, topic: "javascript"},
+
+	{files: []string{"Cargo.toml"}, topic: "rust"},
+	{files: []string{"Cargo.toml"}, pattern: regexp.MustCompile(`(?m)^clap\s*=`), topic: "cli"},
+
+	{files: []string{"setup.py", "pyproject.toml", "requirements.txt"}, topic: "python"},
+	{files: []string{"setup.py", "pyproject.toml", "requirements.txt"}, pattern: regexp.MustCompile(`(?i)\bdjango\b`), topic: "django"},
+	{files: []string{"setup.py", "pyproject.toml", "requirements.txt"}, pattern: regexp.MustCompile(`(?i)\bflask\b`), topic: "flask"},
+
+	{files: []string{"Gemfile"}, topic: "ruby"},
+	{files: []string{"Gemfile"}, pattern: regexp.MustCompile(`gem\s+["']rails["']`), topic: "rails"},
+
+	{files: []string{"pom.xml", "build.gradle", "build.gradle.kts"}, topic: "java"},
+	{files: []string{"pom.xml", "build.gradle", "build.gradle.kts"}, pattern: regexp.MustCompile(`spring-boot`), topic: "spring-boot"},
+
+	{files: []string{"composer.json"}, topic: "php"},
+	{files: []string{"composer.json"}, pattern: regexp.MustCompile(`"laravel/framework"`), topic: "laravel"},
+
+	{files: []string{"Dockerfile"}, topic: "docker"},
+}
+
+// descriptionChecker finds typos in the repository description and topics.
+// For repositories without topics, it suggests topics based on the manifests.
+type descriptionChecker struct {
+	CheckerBase
+	replacer *misspell.Replacer
+	acronyms *acronymChecker
+	metadata *repoMetadata
+
+	// skipMissingTopics is set when the repo metadata checker
+	// reports missing topics, so they're not reported twice.
+	skipMissingTopics bool
+}
+
+func newDescriptionChecker() *descriptionChecker {
+	return &descriptionChecker{
+		replacer: misspell.New(),
+		acronyms: newAcronymChecker(),
+	}
+}
+
+func (c *descriptionChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.metadata = nil
+}
+
+func (c *descriptionChecker) PushFile(f *File) {
+	if isTopicHintFile(f) {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *descriptionChecker) setMetadata(m *repoMetadata) {
+	c.metadata = m
+}
+
+// Results depend on the repository metadata.
+func (c *descriptionChecker) uncachedResults() {}
+
+func (c *descriptionChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	if c.metadata == nil {
+		return nil
+	}
+
+	_, diffs := c.replacer.Replace(c.metadata.Description)
+	for _, d := range diffs {
+		w := fmt.Sprintf("description: %q is a misspelling of %q", d.Original, d.Corrected)
+		warnings = append(warnings, w)
+	}
+	for _, m := range c.acronyms.acronymRE.FindAllString(c.metadata.Description, -1) {
+		m = strings.TrimSpace(m)
+		w := fmt.Sprintf("description: replace %s with %s", m, c.acronyms.acronymMap[m])
+		warnings = append(warnings, w)
+	}
+
+	for _, topic := range c.metadata.Topics {
+		// Topics are lowercase words separated by hyphens.
+		_, diffs := c.replacer.Replace(strings.Replace(topic, "-", " ", -1))
+		for _, d := range diffs {
+			w := fmt.Sprintf("topic %s: %q is a misspelling of %q", topic, d.Original, d.Corrected)
+			warnings = append(warnings, w)
+		}
+	}
+
+	if len(c.metadata.Topics) == 0 && !c.skipMissingTopics {
+		if topics := suggestTopics(c.files); len(topics) != 0 {
+			w := fmt.Sprintf("no topics, consider adding: %s", strings.Join(topics, ", "))
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// isTopicHintFile reports whether f is a manifest that topics are suggested from.
+func isTopicHintFile(f *File) bool {
+	for _, hint := range topicHints {
+		if containsString(hint.files, f.origName) {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestTopics returns sorted topics derived from the manifest files.
+func suggestTopics(files []*File) []string {
+	seen := make(map[string]bool)
+	var topics []string
+	for _, hint := range topicHints {
+		for _, f := range files {
+			if seen[hint.topic] || !containsString(hint.files, f.origName) {
+				continue
+			}
+			if hint.pattern == nil || hint.pattern.MatchString(f.contents) {
+				seen[hint.topic] = true
+				topics = append(topics, hint.topic)
+			}
+		}
+	}
+	if seen["typescript"] {
+		// TypeScript projects have package.json too.
+		topics = removeString(topics, "javascript")
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+func containsString(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(list []string, s string) []string {
+	filtered := list[:0]
+	for _, x := range list {
+		if x != s {
+			filtered = append(filtered, x)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,98 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/go-github/github"
+)
+
+// initDiff collects a set of files changed by -diff or -pr.
+// Only these files are passed to the checkers later.
+func (l *Runner) initDiff() error {
+	if l.diff == "" && l.pr == 0 {
+		return nil
+	}
+	if l.diff != "" && l.pr != 0 {
+		return errors.New("-diff and -pr can't be used together")
+	}
+	if l.repo == "" {
+		return errors.New("-diff and -pr require -repo argument")
+	}
+
+	var changed []*github.CommitFile
+	var err error
+	if l.pr != 0 {
+		changed, err = l.pullRequestFiles()
+	} else {
+		changed, err = l.compareFiles()
+	}
+	if err != nil {
+		return err
+	}
+
+	l.onlyFiles = make(map[string]bool)
+	for _, f := range changed {
+		if f.GetStatus() == "removed" {
+			continue
+		}
+		l.onlyFiles[f.GetFilename()] = true
+	}
+	for name, c := range l.checkers {
+		if _, ok := c.(treeChecker); ok {
+			if l.verbose {
+				log.Printf("\t\tdebug: disable %s checker: it needs all repository files", name)
+			}
+			delete(l.checkers, name)
+		}
+	}
+	return nil
+}
+
+func (l *Runner) compareFiles() ([]*github.CommitFile, error) {
+	// Both "base..head" and "base...head" forms are accepted.
+	parts := strings.SplitN(strings.Replace(l.diff, "...", "..", 1), "..", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("bad -diff value %q: expected base..head", l.diff)
+	}
+	base, head := parts[0], parts[1]
+	cmp, _, err := l.client.Repositories.CompareCommits(l.ctx, l.user, l.repo, base, head)
+	l.countRequest()
+	if err != nil {
+		return nil, fmt.Errorf("compare %s: %v", l.diff, err)
+	}
+	l.ref = head
+
+	files := make([]*github.CommitFile, len(cmp.Files))
+	for i := range cmp.Files {
+		files[i] = &cmp.Files[i]
+	}
+	return files, nil
+}
+
+func (l *Runner) pullRequestFiles() ([]*github.CommitFile, error) {
+	pr, _, err := l.client.PullRequests.Get(l.ctx, l.user, l.repo, l.pr)
+	l.countRequest()
+	if err != nil {
+		return nil, fmt.Errorf("get PR #%d: %v", l.pr, err)
+	}
+	l.ref = pr.GetHead().GetSHA()
+
+	var files []*github.CommitFile
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		list, resp, err := l.client.PullRequests.ListFiles(l.ctx, l.user, l.repo, l.pr, opts)
+		l.countRequest()
+		if err != nil {
+			return nil, fmt.Errorf("list PR #%d files (page=%d): %v", l.pr, opts.Page, err)
+		}
+		files = append(files, list...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return files, nil
+}
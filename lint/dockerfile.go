@@ -0,0 +1,263 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// dockerfileConfig enables the Dockerfile checker.
+type dockerfileConfig struct {
+	// Ignore are the hadolint rule codes that are not reported, like DL3008.
+	// They apply to the built-in rules too.
+	Ignore []string `yaml:"ignore"`
+}
+
+var (
+	// hadolintRE matches hadolint tty output lines,
+	// like "Dockerfile:3 DL3007 warning: Using latest is prone to errors".
+	hadolintRE = regexp.MustCompile(`^(.+?):(\d+) ((?:DL|SC)\d+) (?:\w+: )?(.+)$`)
+
+	// dockerfileInstructionRE matches the Dockerfile instruction keyword.
+	dockerfileInstructionRE = regexp.MustCompile(`^([A-Za-z]+)\s+(.*)$`)
+
+	// dockerShellSplitRE splits RUN commands into the separate commands.
+	dockerShellSplitRE = regexp.MustCompile(`&&|\|\||;|\|`)
+
+	// archiveURLRE matches ADD sources that COPY can't replace.
+	archiveURLRE = regexp.MustCompile(`^(?:https?://|git@)|\.(?:tar|tgz|tar\.gz|tar\.bz2|tar\.xz|txz|tbz2)$`)
+)
+
+// dockerfileInstruction is a Dockerfile instruction with the line continuations joined.
+type dockerfileInstruction struct {
+	line    int
+	keyword string
+	args    string
+}
+
+// dockerfileChecker lints Dockerfiles anywhere in the tree.
+//
+// In clone and local modes, it runs hadolint when it's installed.
+// Otherwise, a built-in subset of the hadolint rules is used:
+// untagged and latest base images, unpinned apt-get and apk packages,
+// and ADD used instead of COPY.
+// It's disabled until the dockerfile config section is specified.
+type dockerfileChecker struct {
+	CheckerBase
+
+	enabled bool
+
+	// ignore are the rule codes that are not reported.
+	ignore map[string]bool
+}
+
+func newDockerfileChecker() *dockerfileChecker {
+	return &dockerfileChecker{}
+}
+
+// Vendored Dockerfiles are not maintained here.
+func (c *dockerfileChecker) skipGenerated() {}
+
+// Results depend on the installed linter.
+func (c *dockerfileChecker) uncachedResults() {}
+
+func (c *dockerfileChecker) PushFile(f *File) {
+	if c.enabled && !f.dir && isDockerfile(f.baseName) && !isMarkdownFile(f.baseName) {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *dockerfileChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	if len(c.files) == 0 {
+		return nil
+	}
+	if rootDir := c.files[0].rootDir; rootDir != "" {
+		if warnings, ok := c.runHadolint(ctx, rootDir); ok {
+			return warnings
+		}
+	}
+	for _, f := range c.files {
+		warnings = append(warnings, c.checkDockerfile(f)...)
+	}
+	return warnings
+}
+
+// runHadolint runs hadolint inside rootDir.
+// Reports false if hadolint is not installed or it failed to run.
+func (c *dockerfileChecker) runHadolint(ctx context.Context, rootDir string) ([]string, bool) {
+	if _, err := exec.LookPath("hadolint"); err != nil {
+		return nil, false
+	}
+	args := []string{"--no-fail", "--no-color", "--format", "tty"}
+	for code := range c.ignore {
+		args = append(args, "--ignore", code)
+	}
+	for _, f := range c.files {
+		args = append(args, f.origName)
+	}
+	cmd := exec.CommandContext(ctx, "hadolint", args...)
+	cmd.Dir = rootDir
+
+	out, err := cmd.CombinedOutput()
+	var warnings []string
+	for _, line := range strings.Split(string(out), "\n") {
+		m := hadolintRE.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		w := fmt.Sprintf("%s:%s: %s %s", m[1], m[2], m[3], strings.TrimSpace(m[4]))
+		warnings = append(warnings, w)
+	}
+	if err != nil && len(warnings) == 0 {
+		if ctx.Err() == nil {
+			log.Printf("\terror: hadolint: %v: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil, false
+	}
+	return warnings, true
+}
+
+// checkDockerfile applies the built-in rules.
+func (c *dockerfileChecker) checkDockerfile(f *File) (warnings []string) {
+	report := func(line int, code, format string, args ...interface{}) {
+		if c.ignore[code] {
+			return
+		}
+		w := fmt.Sprintf("%s:%d: %s ", f.origName, line, code) + fmt.Sprintf(format, args...)
+		warnings = append(warnings, w)
+	}
+
+	stages := make(map[string]bool)
+	for _, inst := range parseDockerfile(f.contents) {
+		fields := strings.Fields(inst.args)
+		switch inst.keyword {
+		case "FROM":
+			var image string
+			for i, field := range fields {
+				if strings.HasPrefix(field, "--") {
+					continue
+				}
+				image = field
+				if i+2 < len(fields) && strings.EqualFold(fields[i+1], "AS") {
+					stages[strings.ToLower(fields[i+2])] = true
+				}
+				break
+			}
+			if image == "" || image == "scratch" || strings.Contains(image, "$") || stages[strings.ToLower(image)] {
+				continue
+			}
+			name := image
+			if i := strings.LastIndexByte(name, '/'); i != -1 {
+				name = name[i+1:]
+			}
+			switch {
+			case strings.Contains(name, "@"):
+				// Pinned by a digest.
+			case strings.HasSuffix(name, ":latest"):
+				report(inst.line, "DL3007", "image %s uses the latest tag, pin an explicit version", image)
+			case !strings.Contains(name, ":"):
+				report(inst.line, "DL3006", "image %s has no tag, pin an explicit version", image)
+			}
+		case "ADD":
+			sources := addSources(fields)
+			archive := false
+			for _, src := range sources {
+				if archiveURLRE.MatchString(src) {
+					archive = true
+				}
+			}
+			if len(sources) != 0 && !archive {
+				report(inst.line, "DL3020", "use COPY instead of ADD for files and directories")
+			}
+		case "RUN":
+			for _, cmd := range dockerShellSplitRE.Split(inst.args, -1) {
+				args := strings.Fields(cmd)
+				switch {
+				case len(args) >= 2 && args[0] == "apt-get" && containsString(args, "install"):
+					for _, pkg := range unpinnedPackages(args[1:], "install", nil) {
+						report(inst.line, "DL3008", "pin apt-get package versions, like %s=<version>", pkg)
+					}
+				case len(args) >= 2 && args[0] == "apk" && args[1] == "add":
+					for _, pkg := range unpinnedPackages(args[2:], "", []string{"--virtual", "-t", "--repository", "-X"}) {
+						report(inst.line, "DL3018", "pin apk package versions, like %s=<version>", pkg)
+					}
+				}
+			}
+		}
+	}
+	return warnings
+}
+
+// parseDockerfile returns the Dockerfile instructions, the line continuations joined.
+// Comments and heredoc bodies are skipped.
+func parseDockerfile(contents string) []dockerfileInstruction {
+	var insts []dockerfileInstruction
+	var cur *dockerfileInstruction
+	heredoc := ""
+	for i, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSpace(strings.TrimSuffix(line, "\r"))
+		if heredoc != "" {
+			if line == heredoc {
+				heredoc = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		continued := strings.HasSuffix(line, "\\")
+		line = strings.TrimSuffix(line, "\\")
+		if cur != nil {
+			cur.args += " " + line
+		} else if m := dockerfileInstructionRE.FindStringSubmatch(line); m != nil {
+			insts = append(insts, dockerfileInstruction{line: i + 1, keyword: strings.ToUpper(m[1]), args: m[2]})
+			cur = &insts[len(insts)-1]
+		}
+		if cur != nil && !continued {
+			if j := strings.Index(cur.args, "<<"); j != -1 {
+				heredoc = strings.Trim(strings.Fields(cur.args[j+2:] + " x")[0], `-"'`)
+			}
+			cur = nil
+		}
+	}
+	return insts
+}
+
+// addSources returns the ADD instruction sources, flags skipped.
+func addSources(fields []string) []string {
+	var args []string
+	for _, field := range fields {
+		if !strings.HasPrefix(field, "--") {
+			args = append(args, field)
+		}
+	}
+	if len(args) < 2 {
+		return nil
+	}
+	return args[:len(args)-1]
+}
+
+// unpinnedPackages returns the package arguments without a version after the subcommand.
+// valueFlags are the flags followed by a value that is not a package.
+func unpinnedPackages(args []string, subcommand string, valueFlags []string) []string {
+	var pkgs []string
+	started := subcommand == ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case !started:
+			started = arg == subcommand
+		case containsString(valueFlags, arg):
+			i++
+		case strings.HasPrefix(arg, "-"), strings.ContainsAny(arg, "=$<>*"), strings.HasSuffix(arg, ".deb"):
+			// Flags, pinned packages, variables and local packages.
+		default:
+			pkgs = append(pkgs, arg)
+		}
+	}
+	return pkgs
+}
@@ -0,0 +1,310 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// emailConfig configures the email digest sent after the run.
+type emailConfig struct {
+	// SMTP is a mail server address, like "smtp.example.com:587".
+	SMTP string `yaml:"smtp"`
+
+	// Username enables the PLAIN authentication,
+	// the password is read from the SMTP_PASSWORD environment variable.
+	Username string `yaml:"username"`
+
+	From string   `yaml:"from"`
+	To   []string `yaml:"to"`
+
+	// Subject overrides the default digest subject.
+	Subject string `yaml:"subject"`
+
+	// StateFile stores the findings of the last run, so the digest
+	// lists only the new ones. Defaults to a file in the user cache directory.
+	StateFile string `yaml:"state_file"`
+
+	// SkipEmpty disables sending digests without new findings.
+	SkipEmpty bool `yaml:"skip_empty"`
+}
+
+// digestState is a digest state file contents.
+type digestState struct {
+	// Findings map the warning fingerprints to the repository names.
+	Findings map[string]string `json:"findings"`
+}
+
+// digestRepo is a per-repository digest summary.
+type digestRepo struct {
+	Name     string
+	Score    int
+	Warnings int
+	New      []Warning
+	Fixed    int
+}
+
+// digest is an email digest contents.
+type digest struct {
+	User     string
+	Started  time.Time
+	Repos    []*digestRepo
+	New      int
+	Fixed    int
+	FirstRun bool
+}
+
+// sendMail is replaced in tests.
+var sendMail = smtp.SendMail
+
+// newDigest compares the run results with the previous run findings.
+// Nil prev means there is no previous run, so all findings are new.
+func newDigest(r *runReport, prev *digestState) *digest {
+	d := &digest{User: r.User, Started: r.Started, FirstRun: prev == nil}
+	current := make(map[string]bool)
+	byName := make(map[string]*digestRepo, len(r.Repos))
+	for _, rr := range r.Repos {
+		dr := &digestRepo{Name: rr.Name, Score: rr.Score, Warnings: len(rr.Warnings)}
+		for _, w := range rr.Warnings {
+			fp := warningFingerprint(rr.Name, w)
+			if current[fp] {
+				continue
+			}
+			current[fp] = true
+			if prev == nil || prev.Findings[fp] == "" {
+				dr.New = append(dr.New, w)
+			}
+		}
+		d.New += len(dr.New)
+		byName[rr.Name] = dr
+		d.Repos = append(d.Repos, dr)
+	}
+	if prev != nil {
+		for fp, repo := range prev.Findings {
+			// Repositories that were not checked this time are not fixed.
+			if dr := byName[repo]; dr != nil && !current[fp] {
+				dr.Fixed++
+				d.Fixed++
+			}
+		}
+	}
+	sort.SliceStable(d.Repos, func(i, j int) bool {
+		return d.Repos[i].Score < d.Repos[j].Score
+	})
+	return d
+}
+
+func (d *digest) subject() string {
+	return fmt.Sprintf("repolint: %d new findings in %s (%d repositories)", d.New, d.User, len(d.Repos))
+}
+
+func (d *digest) plainText() string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "repolint %s, started %s\n\n", d.User, d.Started.Format("2006-01-02 15:04:05 MST"))
+	if d.FirstRun {
+		buf.WriteString("This is the first run, all findings are new.\n")
+	}
+	fmt.Fprintf(&buf, "%d new findings, %d fixed since the last run.\n\n", d.New, d.Fixed)
+	for _, dr := range d.Repos {
+		fmt.Fprintf(&buf, "%s: score %d, %d warnings, %d new, %d fixed\n", dr.Name, dr.Score, dr.Warnings, len(dr.New), dr.Fixed)
+	}
+	for _, dr := range d.Repos {
+		if len(dr.New) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "\nNew in %s:\n", dr.Name)
+		for _, w := range dr.New {
+			fmt.Fprintf(&buf, "  %s: %s: %s\n", w.Severity, w.Checker, w.Text)
+		}
+	}
+	return buf.String()
+}
+
+var digestTemplate = template.Must(template.New("digest").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif">
+<h1>repolint {{.User}}</h1>
+<p>Started {{.Started.Format "2006-01-02 15:04:05 MST"}}.
+{{if .FirstRun}}This is the first run, all findings are new.{{end}}
+{{.New}} new findings, {{.Fixed}} fixed since the last run.</p>
+<table>
+<tr><th align="left">Repository</th><th>Score</th><th>Warnings</th><th>New</th><th>Fixed</th></tr>
+{{range .Repos}}<tr><td>{{.Name}}</td><td>{{.Score}}</td><td>{{.Warnings}}</td><td>{{len .New}}</td><td>{{.Fixed}}</td></tr>
+{{end}}</table>
+{{range .Repos}}{{if .New}}<h2>New in {{.Name}}</h2>
+<ul>{{range .New}}<li>{{.Severity}}: {{.Checker}}: {{.Text}}</li>{{end}}</ul>
+{{end}}{{end}}
+</body>
+</html>
+`))
+
+// message returns a multipart/alternative email with plain text and HTML bodies.
+func (d *digest) message(cfg *emailConfig) ([]byte, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	var html bytes.Buffer
+	if err := digestTemplate.Execute(&html, d); err != nil {
+		return nil, err
+	}
+	parts := []struct {
+		contentType string
+		data        string
+	}{
+		{"text/plain; charset=utf-8", d.plainText()},
+		{"text/html; charset=utf-8", html.String()},
+	}
+	for _, p := range parts {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := qw.Write([]byte(p.data)); err != nil {
+			return nil, err
+		}
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	subject := cfg.Subject
+	if subject == "" {
+		subject = d.subject()
+	}
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(cfg.To, ", "))
+	// Custom and translated subjects may be non-ASCII.
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+func loadDigestState(filename string) (*digestState, error) {
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state digestState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// saveDigestState records the run findings for the next digest.
+// Findings of the repositories that were not checked this time are kept.
+func saveDigestState(filename string, r *runReport, prev *digestState) error {
+	state := digestState{Findings: make(map[string]string)}
+	checked := make(map[string]bool, len(r.Repos))
+	for _, rr := range r.Repos {
+		checked[rr.Name] = true
+		for _, w := range rr.Warnings {
+			state.Findings[warningFingerprint(rr.Name, w)] = rr.Name
+		}
+	}
+	if prev != nil {
+		for fp, repo := range prev.Findings {
+			if !checked[repo] {
+				state.Findings[fp] = repo
+			}
+		}
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+// checkEmailConfig validates the email config section.
+func checkEmailConfig(cfg *emailConfig) error {
+	switch {
+	case cfg.SMTP == "":
+		return errors.New("smtp server address is not set")
+	case cfg.From == "":
+		return errors.New("from address is not set")
+	case len(cfg.To) == 0:
+		return errors.New("no recipients")
+	}
+	if _, _, err := net.SplitHostPort(cfg.SMTP); err != nil {
+		return fmt.Errorf("smtp: %v", err)
+	}
+	return nil
+}
+
+// sendDigest emails the new findings since the last run to the email config recipients.
+func (l *Runner) sendDigest() error {
+	cfg := l.config.Email
+	if cfg == nil {
+		return nil
+	}
+	stateFile := cfg.StateFile
+	if stateFile == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return fmt.Errorf("state file is not set and there is no cache dir: %v", err)
+		}
+		name := l.user
+		if name == "" {
+			name = "local"
+		}
+		stateFile = filepath.Join(dir, "repolint", "digest", name+".json")
+	}
+	prev, err := loadDigestState(stateFile)
+	if err != nil {
+		return fmt.Errorf("load %s: %v", stateFile, err)
+	}
+
+	d := newDigest(&l.results, prev)
+	if d.New == 0 && cfg.SkipEmpty {
+		log.Printf("\tno new findings, email digest is not sent")
+	} else {
+		msg, err := d.message(cfg)
+		if err != nil {
+			return err
+		}
+		var auth smtp.Auth
+		if cfg.Username != "" {
+			host, _, _ := net.SplitHostPort(cfg.SMTP)
+			auth = smtp.PlainAuth("", cfg.Username, os.Getenv("SMTP_PASSWORD"), host)
+		}
+		if err := sendMail(cfg.SMTP, auth, cfg.From, cfg.To, msg); err != nil {
+			return err
+		}
+		log.Printf("\temail digest sent to %s", strings.Join(cfg.To, ", "))
+	}
+	// The state is only updated after a successful delivery,
+	// so the findings are not lost if the mail server is down.
+	return saveDigestState(stateFile, &l.results, prev)
+}
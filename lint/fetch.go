@@ -0,0 +1,339 @@
+package lint
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/github"
+)
+
+// repoFetcher is a way to get repository files to the local machine.
+type repoFetcher interface {
+	// CollectFiles returns a list of all repo files.
+	// Only file names are filled at this point.
+	CollectFiles(repo string) ([]*File, error)
+
+	// ResolveRequirements fetches everything f.require asks for.
+	ResolveRequirements(repo string, f *File)
+
+	// LinkTarget returns a symlink f target path.
+	LinkTarget(repo string, f *File) (string, error)
+
+	// RequestsCost estimates how many API calls
+	// ResolveRequirements is going to make for f.
+	RequestsCost(f *File) int
+
+	// CommitSHA returns a hash of the commit being checked.
+	CommitSHA(repo string) (string, error)
+
+	// Cleanup releases all resources associated with repo.
+	Cleanup(repo string)
+}
+
+// newRepoFetcher returns a fetcher for the specified fetch mode.
+func newRepoFetcher(l *Runner, mode string) (repoFetcher, error) {
+	switch mode {
+	case "api":
+		return &apiFetcher{l: l}, nil
+	case "tarball":
+		return &tarballFetcher{l: l}, nil
+	case "clone":
+		if _, err := exec.LookPath("git"); err != nil {
+			return nil, fmt.Errorf("clone mode requires git: %v", err)
+		}
+		return &cloneFetcher{l: l}, nil
+	default:
+		return nil, fmt.Errorf("unknown fetch mode %q", mode)
+	}
+}
+
+// normalizeRepoPath converts a file name into a repo-relative
+// path that uses forward slashes as a separator on every OS.
+func normalizeRepoPath(name string) string {
+	p := path.Clean(filepath.ToSlash(name))
+	return strings.TrimPrefix(p, "/")
+}
+
+// apiFetcher downloads every required file separately with github API.
+type apiFetcher struct {
+	l *Runner
+}
+
+func (api *apiFetcher) CollectFiles(repo string) ([]*File, error) {
+	l := api.l
+	tree, _, err := l.client.Git.GetTree(l.ctx, l.user, repo, l.treeRef(), true)
+	l.countRequest()
+	if err != nil {
+		return nil, fmt.Errorf("get %s tree: %v", repo, err)
+	}
+	if tree.GetTruncated() {
+		l.truncatedTree = true
+		if l.verbose {
+			log.Printf("\t\tdebug: %s tree is truncated", repo)
+		}
+	}
+
+	var files []*File
+	for _, entry := range tree.Entries {
+		if entry.Path == nil {
+			continue
+		}
+		files = append(files, &File{
+			origName: *entry.Path,
+			baseName: filepath.Base(*entry.Path),
+			sha:      entry.GetSHA(),
+			symlink:  entry.GetMode() == "120000",
+			dir:      entry.GetType() == "tree",
+			size:     int64(entry.GetSize()),
+		})
+	}
+	return files, nil
+}
+
+func (api *apiFetcher) ResolveRequirements(repo string, f *File) {
+	if f.require.contents {
+		f.require.localCopy = true
+	}
+
+	if f.require.localCopy && f.tempName == "" {
+		api.createLocalCopy(repo, f)
+	}
+}
+
+func (api *apiFetcher) LinkTarget(repo string, f *File) (string, error) {
+	l := api.l
+	// Symlink blob contents is the link target path.
+	data, _, err := l.client.Git.GetBlobRaw(l.ctx, l.user, repo, f.sha)
+	l.countRequest()
+	return string(data), err
+}
+
+func (api *apiFetcher) RequestsCost(f *File) int {
+	if api.l.rawURL != "" {
+		return 0
+	}
+	if (f.require.contents || f.require.localCopy) && f.tempName == "" {
+		return 1
+	}
+	return 0
+}
+
+func (api *apiFetcher) CommitSHA(repo string) (string, error) {
+	l := api.l
+	sha, _, err := l.client.Repositories.GetCommitSHA1(l.ctx, l.user, repo, l.treeRef(), "")
+	l.countRequest()
+	return sha, err
+}
+
+func (api *apiFetcher) Cleanup(repo string) {}
+
+func (api *apiFetcher) createLocalCopy(repo string, f *File) {
+	flatPath := strings.Replace(f.origName, "/", "_(slash)_", -1)
+	filename := filepath.Join(api.l.tempDir, flatPath)
+	data := api.getContents(repo, f.origName)
+	if f.require.contents {
+		f.contents = data
+	}
+	if err := ioutil.WriteFile(filename, []byte(data), 0644); err != nil {
+		panic(fmt.Sprintf("write %s: %v", f.origName, err))
+	}
+	f.tempName = filename
+}
+
+func (api *apiFetcher) getContents(repo, path string) string {
+	l := api.l
+	if l.rawURL != "" {
+		s, err := getRawContents(l.ctx, l.rawClient, expandRawURL(l.rawURL, l.user, repo, l.treeRef(), path))
+		if err != nil {
+			log.Printf("\terror: get %s/%s contents: %v", repo, path, err)
+		}
+		return s
+	}
+	opts := &github.RepositoryContentGetOptions{Ref: l.treeRef()}
+	f, _, _, err := l.client.Repositories.GetContents(l.ctx, l.user, repo, path, opts)
+	l.countRequest()
+	if err != nil {
+		log.Printf("\terror: get %s/%s contents: %v", repo, path, err)
+		return ""
+	}
+	if f == nil {
+		log.Printf("\terror: %s/%s contents is nil", repo, path)
+		return ""
+	}
+	s, err := f.GetContent()
+	if err != nil {
+		panic(fmt.Sprintf("get %s contents: %v", path, err))
+	}
+	return s
+}
+
+// cloneFetcher makes a shallow git clone of the whole repository.
+//
+// It costs a single network round trip per repository and
+// keeps the original tree layout, so relative file paths
+// can be resolved by the checkers.
+type cloneFetcher struct {
+	l *Runner
+}
+
+func (cf *cloneFetcher) repoDir(repo string) string {
+	return filepath.Join(cf.l.tempDir, "clone", repo)
+}
+
+func (cf *cloneFetcher) CollectFiles(repo string) ([]*File, error) {
+	dir := cf.repoDir(repo)
+	if err := removeAll(dir); err != nil {
+		return nil, err
+	}
+
+	if err := cf.clone(repo, dir); err != nil {
+		return nil, fmt.Errorf("clone %s: %v", repo, err)
+	}
+
+	index, err := readGitIndex(cf.l.ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return walkRepoDir(dir, index)
+}
+
+// walkRepoDir returns all files of a local repository checkout.
+// index maps file paths to their git index entries, it can be nil.
+func walkRepoDir(dir string, index map[string]indexEntry) ([]*File, error) {
+	var files []*File
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		entry := index[filepath.ToSlash(rel)]
+		f := &File{
+			origName: filepath.ToSlash(rel),
+			baseName: info.Name(),
+			sha:      entry.sha,
+			symlink:  info.Mode()&os.ModeSymlink != 0 || entry.mode == gitSymlinkMode,
+			dir:      info.IsDir(),
+			rootDir:  dir,
+		}
+		if info.Mode().IsRegular() && !f.symlink {
+			f.tempName = path
+			f.size = info.Size()
+		}
+		files = append(files, f)
+		return nil
+	})
+	return files, err
+}
+
+func (cf *cloneFetcher) clone(repo, dir string) error {
+	l := cf.l
+	url := fmt.Sprintf("%s/%s/%s.git", strings.TrimSuffix(l.webURL, "/"), l.user, repo)
+	var env []string
+	if l.tokens != nil {
+		// Anonymous mode clones public repositories without a token.
+		// The header is passed through the environment, the command line is visible to other users.
+		token := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + l.tokens.pick().token))
+		env = append(os.Environ(),
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+token)
+	}
+	git := func(args ...string) error {
+		// Files are checked out as they're committed, like in api mode,
+		// even if the user config converts line endings, like Git for Windows does.
+		args = append([]string{"-c", "core.autocrlf=false"}, args...)
+		cmd := exec.CommandContext(l.ctx, "git", args...)
+		cmd.Env = env
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%v: %s", err, out)
+		}
+		return nil
+	}
+
+	blobs := l.config.RepoSize != nil && l.config.RepoSize.History
+	history := l.staleDocsYears != 0 || l.history || blobs
+	if l.ref == "" && !history {
+		return git("clone", "--quiet", "--depth=1", url, dir)
+	}
+	// Arbitrary refs (like commit SHA) can't be cloned directly.
+	steps := [][]string{
+		{"init", "--quiet", dir},
+		{"-C", dir, "fetch", "--quiet", "--depth=1", url, l.ref},
+		{"-C", dir, "checkout", "--quiet", "FETCH_HEAD"},
+	}
+	if history {
+		// History is needed for the stale docs and secret history checkers,
+		// but not the old file contents, unless history blob sizes are checked.
+		ref := l.ref
+		if ref == "" {
+			ref = "HEAD"
+		}
+		clone := []string{"clone", "--quiet", "--filter=blob:none", "--no-checkout", url, dir}
+		if blobs {
+			clone = []string{"clone", "--quiet", "--no-checkout", url, dir}
+		}
+		steps = [][]string{
+			clone,
+			{"-C", dir, "fetch", "--quiet", "origin", ref},
+			{"-C", dir, "checkout", "--quiet", "FETCH_HEAD"},
+		}
+	}
+	for _, args := range steps {
+		if err := git(args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cf *cloneFetcher) ResolveRequirements(repo string, f *File) {
+	readLocalContents(repo, f)
+}
+
+// readLocalContents reads f contents from its local file, if required.
+func readLocalContents(repo string, f *File) {
+	if !f.require.contents || f.tempName == "" {
+		return
+	}
+	data, err := ioutil.ReadFile(f.tempName)
+	if err != nil {
+		log.Printf("\terror: read %s/%s: %v", repo, f.origName, err)
+		return
+	}
+	f.contents = string(data)
+}
+
+func (cf *cloneFetcher) LinkTarget(repo string, f *File) (string, error) {
+	return readLinkTarget(filepath.Join(cf.repoDir(repo), filepath.FromSlash(f.origName)))
+}
+
+func (cf *cloneFetcher) RequestsCost(f *File) int { return 0 }
+
+func (cf *cloneFetcher) CommitSHA(repo string) (string, error) {
+	out, err := exec.CommandContext(cf.l.ctx, "git", "-C", cf.repoDir(repo), "rev-parse", "HEAD").Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+func (cf *cloneFetcher) Cleanup(repo string) {
+	if err := removeAll(cf.repoDir(repo)); err != nil {
+		log.Printf("\terror: remove %s clone: %v", repo, err)
+	}
+}
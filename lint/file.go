@@ -0,0 +1,100 @@
+package lint
+
+import "path"
+
+// File is a repository file passed to the checkers.
+type File struct {
+	// origName is file original name as in github repo.
+	origName string
+
+	// baseName is a filepath.Base(origName) result.
+	baseName string
+
+	// sha is a git blob hash of the file contents.
+	sha string
+
+	// symlink reports whether this file is a symbolic link.
+	symlink bool
+
+	// dir reports whether this entry is a directory.
+	// Directories are listed along with their files.
+	dir bool
+
+	// size is a file size in bytes.
+	// Zero for directories and symlinks.
+	size int64
+
+	// linkTarget is a repo file this symlink points to.
+	// Nil for regular files and links pointing outside of the repo.
+	linkTarget *File
+
+	// linkPath is a symlink target path, as it's stored in the repository.
+	// Empty for regular files and links which target can't be read.
+	linkPath string
+
+	// tempName is a full filename on a local filesystem.
+	// If empty, no local file is associated.
+	tempName string
+
+	// contents is a local file copy contents.
+	contents string
+
+	// rootDir is a local repository checkout directory that contains tempName.
+	// If empty, tempName is a standalone copy and relative paths
+	// inside the file can't be resolved.
+	rootDir string
+
+	// generated reports whether .gitattributes marks the file
+	// as linguist-generated or linguist-vendored.
+	generated bool
+
+	// artifacts are the parsed contents shared by the checkers.
+	artifacts fileArtifacts
+
+	require struct {
+		localCopy bool
+		contents  bool
+	}
+}
+
+// NewFile returns a file with the specified repository path and contents.
+// Such files are never downloaded, so they're only
+// useful for checkers that work with the file contents.
+func NewFile(filename, contents string) *File {
+	f := &File{
+		origName: normalizeRepoPath(filename),
+		contents: contents,
+		size:     int64(len(contents)),
+	}
+	f.baseName = path.Base(f.origName)
+	return f
+}
+
+// Path returns a repo-relative file path with forward slashes.
+func (f *File) Path() string { return f.origName }
+
+// Name returns the last path element.
+func (f *File) Name() string { return f.baseName }
+
+// Size returns the file size in bytes from the tree listing.
+// It's available without the file contents.
+func (f *File) Size() int64 { return f.size }
+
+// IsDir reports whether the entry is a directory.
+func (f *File) IsDir() bool { return f.dir }
+
+// Contents returns the file contents.
+// It's only available if RequireContents was called inside Checker.PushFile.
+func (f *File) Contents() string { return f.contents }
+
+// RequireContents asks the file contents to be fetched before the check.
+func (f *File) RequireContents() { f.require.contents = true }
+
+// contentsHash returns a git blob hash of the file contents.
+// For symlinks, it's a link target contents hash.
+func (f *File) contentsHash() string {
+	if f.linkTarget != nil {
+		return f.linkTarget.sha
+	}
+	return f.sha
+}
@@ -0,0 +1,170 @@
+package lint
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Fix is a safe automatic fix for a warning.
+//
+// It replaces the [Start, End) byte range of the file contents
+// with Replacement or, if Delete is set, removes the whole file.
+type Fix struct {
+	// File is a repo-relative path of the fixed file.
+	File string `json:"file"`
+
+	Start       int    `json:"start"`
+	End         int    `json:"end"`
+	Replacement string `json:"replacement"`
+
+	Delete bool `json:"delete,omitempty"`
+
+	// applied is set after the fix is written to the file.
+	applied bool
+}
+
+// Fixer is implemented by the checkers that can fix their warnings.
+type Fixer interface {
+	// Fixes returns fixes for the warnings of the last CheckFiles call,
+	// in the same order. Nil fix means the warning can't be fixed automatically.
+	Fixes() []*Fix
+}
+
+// fixBase implements Fixer for the checkers that embed it.
+// Such checkers record a fix (or nil) for every warning they report.
+type fixBase struct {
+	fixes []*Fix
+}
+
+func (b *fixBase) Fixes() []*Fix { return b.fixes }
+
+// fixAt returns a fix for the i-th checker warning.
+func fixAt(c Checker, i int) *Fix {
+	fixer, ok := c.(Fixer)
+	if !ok {
+		return nil
+	}
+	fixes := fixer.Fixes()
+	if i >= len(fixes) {
+		return nil
+	}
+	return fixes[i]
+}
+
+// applyFixes writes fixes to the -dir files.
+// Fixes that overlap with already applied ones are skipped,
+// the next run will report them again.
+//
+// In -dry-run mode, a unified diff of the changes is printed to stdout instead.
+func (l *Runner) applyFixes(fixes []*Fix) {
+	byFile := make(map[string][]*Fix)
+	var names []string
+	for _, fix := range fixes {
+		if _, ok := byFile[fix.File]; !ok {
+			names = append(names, fix.File)
+		}
+		byFile[fix.File] = append(byFile[fix.File], fix)
+	}
+	for _, name := range names {
+		filename := filepath.Join(l.dir, filepath.FromSlash(name))
+		ff, err := readFixedFile(filename, byFile[name])
+		if err == nil {
+			if l.dryRun {
+				fmt.Print(ff.diff(name))
+			} else {
+				err = ff.write(filename)
+			}
+		}
+		if err != nil {
+			log.Printf("\terror: fix %s: %v", name, err)
+		}
+	}
+}
+
+// fixedFile is a file contents before and after the fixes.
+type fixedFile struct {
+	old string
+	new string
+
+	// deleted is set if the file is removed.
+	deleted bool
+
+	applied []*Fix
+}
+
+func readFixedFile(filename string, fixes []*Fix) (*fixedFile, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	ff := &fixedFile{old: string(data)}
+	for _, fix := range fixes {
+		if fix.Delete {
+			ff.deleted = true
+			ff.applied = []*Fix{fix}
+			return ff, nil
+		}
+	}
+	ff.new, ff.applied = applyEdits(ff.old, fixes)
+	return ff, nil
+}
+
+func (ff *fixedFile) write(filename string) error {
+	if ff.deleted {
+		if err := os.Remove(filename); err != nil {
+			return err
+		}
+	} else {
+		info, err := os.Stat(filename)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filename, []byte(ff.new), info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	for _, fix := range ff.applied {
+		fix.applied = true
+	}
+	return nil
+}
+
+// diff returns a unified diff of the changes, name is a repo-relative file path.
+func (ff *fixedFile) diff(name string) string {
+	if !ff.deleted {
+		return unifiedDiff("a/"+name, "b/"+name, ff.old, ff.new)
+	}
+	if strings.ContainsRune(ff.old, 0) || !utf8.ValidString(ff.old) {
+		return fmt.Sprintf("Binary files a/%s and /dev/null differ\n", name)
+	}
+	return unifiedDiff("a/"+name, "/dev/null", ff.old, "")
+}
+
+// applyEdits returns contents with non-overlapping fixes applied.
+func applyEdits(contents string, fixes []*Fix) (string, []*Fix) {
+	sorted := append([]*Fix(nil), fixes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Start < sorted[j].Start
+	})
+
+	var buf strings.Builder
+	var applied []*Fix
+	pos := 0
+	for _, fix := range sorted {
+		if fix.Start < pos || fix.End < fix.Start || fix.End > len(contents) {
+			continue
+		}
+		buf.WriteString(contents[pos:fix.Start])
+		buf.WriteString(fix.Replacement)
+		pos = fix.End
+		applied = append(applied, fix)
+	}
+	buf.WriteString(contents[pos:])
+	return buf.String(), applied
+}
@@ -0,0 +1,76 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// forkDriftMinCommits is a number of fork commits
+// that makes it a separate project rather than a patch.
+const forkDriftMinCommits = 10
+
+// forkDriftChecker finds forks that diverged from the upstream,
+// but their README still describes the upstream project.
+type forkDriftChecker struct {
+	CheckerBase
+	metadata *repoMetadata
+
+	// upstreamLineRE matches README lines that usually point to the project itself.
+	upstreamLineRE *regexp.Regexp
+}
+
+func newForkDriftChecker() *forkDriftChecker {
+	return &forkDriftChecker{
+		upstreamLineRE: regexp.MustCompile(`(?i)!\[|<img\s|` +
+			`\b(?:go get|go install|git clone|pip install|npm install|npm i|yarn add|cargo install|gem install|brew install|docker pull|docker run)\b`),
+	}
+}
+
+func (c *forkDriftChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.metadata = nil
+}
+
+func (c *forkDriftChecker) PushFile(f *File) {
+	// Only the root README describes the project.
+	if strings.HasPrefix(f.origName, "README") && !strings.Contains(f.origName, "/") {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *forkDriftChecker) setMetadata(m *repoMetadata) {
+	c.metadata = m
+}
+
+// Results depend on the upstream repository state.
+func (c *forkDriftChecker) uncachedResults() {}
+
+func (c *forkDriftChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	m := c.metadata
+	if m == nil || m.Parent == "" || m.AheadBy < forkDriftMinCommits {
+		return nil
+	}
+	upstream := strings.ToLower(m.Parent)
+	for _, f := range c.files {
+		var claims []string
+		for _, line := range strings.Split(f.contents, "\n") {
+			if strings.Contains(strings.ToLower(line), upstream) && c.upstreamLineRE.MatchString(line) {
+				claims = append(claims, strings.TrimSpace(line))
+			}
+		}
+		switch {
+		case len(claims) != 0:
+			w := fmt.Sprintf("%s: fork is %d commits ahead of %s, but %d badge or install lines still point to it, like %q",
+				f.origName, m.AheadBy, m.Parent, len(claims), claims[0])
+			warnings = append(warnings, w)
+		case !m.ChangedFiles[f.origName]:
+			w := fmt.Sprintf("%s: fork is %d commits ahead of %s, but README is unchanged",
+				f.origName, m.AheadBy, m.Parent)
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,138 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// frictionConfig enables the contribution friction checker.
+type frictionConfig struct {
+	// MinScore is a friction score that is reported, 1 by default.
+	MinScore int `yaml:"min_score"`
+}
+
+// frictionReport is a contribution friction score with the problems that make it.
+type frictionReport struct {
+	Score int      `json:"score"`
+	Max   int      `json:"max"`
+	Items []string `json:"items"`
+}
+
+var (
+	// buildInstructionsRE matches documentation headings of build instructions.
+	buildInstructionsRE = regexp.MustCompile(`(?im)^#{1,6}\s.*\b(?:install|installation|installing|build|building|getting started|quick ?start|setup|development|developing|compiling)\b`)
+
+	// ciConfigRE matches CI configuration files.
+	ciConfigRE = regexp.MustCompile(`^(?:\.github/workflows/[^/]+\.ya?ml|\.travis\.yml|\.gitlab-ci\.yml|\.circleci/config\.yml|azure-pipelines\.yml|Jenkinsfile|\.drone\.yml|appveyor\.yml|\.appveyor\.yml|bitbucket-pipelines\.yml|\.buildkite/.+|\.woodpecker\.yml|\.cirrus\.yml)$`)
+
+	// testFileRE matches test files and directories in common languages.
+	testFileRE = regexp.MustCompile(`(?:^|/)(?:tests?|__tests__|spec|specs)/|_test\.(?:go|py|rb|exs?)$|(?:^|/)test_[^/]+\.py$|\.(?:test|spec)\.[jt]sx?$|(?:Test|Tests)\.(?:java|kt|cs|swift)$`)
+)
+
+// frictionChecker scores how hard it is to contribute to a repository:
+// missing build instructions, CI, CONTRIBUTING and tests, and disabled issues.
+// All problems are reported as a single warning.
+// It's disabled until the contribution_friction config section is specified.
+type frictionChecker struct {
+	CheckerBase
+
+	// minScore is a reported score, zero if the checker is disabled.
+	minScore int
+
+	metadata *repoMetadata
+
+	buildDocs    bool
+	ci           bool
+	contributing bool
+	tests        bool
+
+	// report is the last CheckFiles result, nil if nothing was reported.
+	report *frictionReport
+}
+
+func newFrictionChecker() *frictionChecker {
+	return &frictionChecker{}
+}
+
+func (c *frictionChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.metadata = nil
+	c.buildDocs = false
+	c.ci = false
+	c.contributing = false
+	c.tests = false
+	c.report = nil
+}
+
+func (c *frictionChecker) PushFile(f *File) {
+	if c.minScore == 0 {
+		return
+	}
+	if ciConfigRE.MatchString(f.origName) {
+		c.ci = true
+	}
+	if testFileRE.MatchString(f.origName) {
+		c.tests = true
+	}
+	contributing, _ := lookupCommunityFile("CONTRIBUTING")
+	if contributing.re.MatchString(f.baseName) {
+		for _, dir := range contributing.dirs {
+			if path.Dir(f.origName) == dir {
+				c.contributing = true
+			}
+		}
+	}
+	switch path.Dir(f.origName) {
+	case ".", ".github", "docs":
+		if isDocumentationFile(f.baseName) {
+			f.require.contents = true
+			c.AcceptFile(f)
+		}
+	}
+}
+
+func (c *frictionChecker) setMetadata(m *repoMetadata) {
+	c.metadata = m
+}
+
+// Missing files are found using all repository files.
+func (c *frictionChecker) fullTree() {}
+
+// Results depend on the repository metadata.
+func (c *frictionChecker) uncachedResults() {}
+
+func (c *frictionChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	if c.minScore == 0 {
+		return nil
+	}
+	for _, f := range c.files {
+		if buildInstructionsRE.MatchString(f.contents) {
+			c.buildDocs = true
+		}
+	}
+
+	r := &frictionReport{}
+	check := func(ok bool, item string) {
+		r.Max++
+		if !ok {
+			r.Score++
+			r.Items = append(r.Items, item)
+		}
+	}
+	check(c.buildDocs, "no build instructions")
+	check(c.ci, "no CI")
+	check(c.contributing, "no CONTRIBUTING")
+	check(c.tests, "no tests")
+	if c.metadata != nil {
+		check(c.metadata.HasIssues, "issues disabled")
+	}
+	if r.Score < c.minScore {
+		return nil
+	}
+	c.report = r
+	w := fmt.Sprintf("contribution friction %d/%d: %s", r.Score, r.Max, strings.Join(r.Items, ", "))
+	return append(warnings, w)
+}
@@ -0,0 +1,206 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// binaryAssetExts are the extensions of the binary assets, like images and fonts,
+// apart from the archiveExts and executableExts.
+var binaryAssetExts = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".ico": true,
+	".webp": true, ".bmp": true, ".tif": true, ".tiff": true, ".psd": true,
+	".pdf": true, ".woff": true, ".woff2": true, ".ttf": true, ".otf": true, ".eot": true,
+	".mp3": true, ".mp4": true, ".wav": true, ".ogg": true, ".webm": true, ".mov": true,
+	".docx": true, ".xlsx": true, ".pptx": true,
+}
+
+// gitattributesBuiltins are the attributes git and github understand.
+var gitattributesBuiltins = map[string]bool{
+	"text": true, "eol": true, "crlf": true, "binary": true, "diff": true, "merge": true,
+	"whitespace": true, "export-ignore": true, "export-subst": true, "delta": true,
+	"encoding": true, "working-tree-encoding": true, "ident": true, "filter": true,
+	"conflict-marker-size": true, "lockable": true,
+
+	"linguist-vendored": true, "linguist-generated": true, "linguist-documentation": true,
+	"linguist-detectable": true, "linguist-language": true,
+}
+
+// gitattributesValues are the allowed values of the attributes with fixed values.
+var gitattributesValues = map[string][]string{
+	"eol":  {"lf", "crlf"},
+	"text": {"auto"},
+}
+
+// extensionPatternRE matches file extension patterns, like "*.png".
+var extensionPatternRE = regexp.MustCompile(`^\*\.[^/*?\[]+$`)
+
+// gitattributesChecker recommends a .gitattributes file for repositories
+// with binary assets or mixed line endings, and validates the existing ones:
+// unknown attributes and patterns that match nothing are reported.
+type gitattributesChecker struct {
+	CheckerBase
+
+	// tree is the checked repository tree index, if available.
+	tree *repoTree
+}
+
+func newGitattributesChecker() *gitattributesChecker {
+	return &gitattributesChecker{}
+}
+
+func (c *gitattributesChecker) PushFile(f *File) {
+	if f.dir || f.symlink {
+		return
+	}
+	// Line endings are only checked in the files the whitespace checker fetches anyway.
+	if f.baseName == ".gitattributes" || isWhitespaceCheckedFile(f.baseName) {
+		f.require.contents = true
+	}
+	c.AcceptFile(f)
+}
+
+func (c *gitattributesChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.tree = nil
+}
+
+// Missing .gitattributes and unmatched patterns are found using all repository files.
+func (c *gitattributesChecker) fullTree() {}
+
+// Vendored and excluded files are not pushed to the checkers,
+// but .gitattributes patterns often mark them, like vendor/** linguist-vendored.
+func (c *gitattributesChecker) setTree(t *repoTree) {
+	c.tree = t
+}
+
+func (c *gitattributesChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	var attributes []*File
+	var binary *File
+	crlf, lf := 0, 0
+	for _, f := range c.files {
+		if f.baseName == ".gitattributes" {
+			attributes = append(attributes, f)
+			continue
+		}
+		ext := strings.ToLower(path.Ext(f.baseName))
+		if binary == nil && (binaryAssetExts[ext] || archiveExts[ext] || executableExts[ext]) {
+			binary = f
+		}
+		if f.require.contents {
+			n := strings.Count(f.contents, "\r\n")
+			if n != 0 {
+				crlf++
+			}
+			if strings.Count(f.contents, "\n") > n {
+				lf++
+			}
+		}
+	}
+
+	if len(attributes) == 0 {
+		if binary != nil {
+			w := fmt.Sprintf("no .gitattributes, but binary assets, like %s, are committed; mark them, like *%s binary",
+				binary.origName, strings.ToLower(path.Ext(binary.baseName)))
+			warnings = append(warnings, w)
+		}
+		if crlf != 0 && lf != 0 {
+			w := fmt.Sprintf("no .gitattributes, but line endings are mixed: %d files with CRLF, %d with LF; add * text=auto", crlf, lf)
+			warnings = append(warnings, w)
+		}
+		return warnings
+	}
+
+	macros := make(map[string]bool)
+	for _, f := range attributes {
+		for _, line := range strings.Split(f.contents, "\n") {
+			if fields := strings.Fields(line); len(fields) != 0 && strings.HasPrefix(fields[0], "[attr]") {
+				macros[strings.TrimPrefix(fields[0], "[attr]")] = true
+			}
+		}
+	}
+	for _, f := range attributes {
+		warnings = append(warnings, c.checkAttributes(f, macros)...)
+	}
+	return warnings
+}
+
+// checkAttributes validates a single .gitattributes file.
+func (c *gitattributesChecker) checkAttributes(f *File, macros map[string]bool) (warnings []string) {
+	dir := path.Dir(f.origName)
+	for i, line := range strings.Split(f.contents, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "[attr]") {
+			continue
+		}
+		if strings.HasPrefix(fields[0], `"`) {
+			// Quoted patterns can contain spaces and escapes, they're not parsed.
+			continue
+		}
+		report := func(format string, args ...interface{}) {
+			w := fmt.Sprintf("%s:%d: ", f.origName, i+1) + fmt.Sprintf(format, args...)
+			warnings = append(warnings, w)
+		}
+
+		for _, attr := range fields[1:] {
+			name, value := strings.TrimLeft(attr, "-!"), ""
+			if j := strings.IndexByte(name, '='); j != -1 {
+				name, value = name[:j], name[j+1:]
+			}
+			if !gitattributesBuiltins[name] && !macros[name] {
+				report("unknown attribute %s", name)
+				continue
+			}
+			if allowed := gitattributesValues[name]; value != "" && allowed != nil && !containsString(allowed, value) {
+				report("%s value should be one of: %s", name, strings.Join(allowed, ", "))
+			}
+		}
+
+		pattern := fields[0]
+		switch {
+		case strings.HasPrefix(pattern, "!"):
+			report("negative pattern %s is not allowed in .gitattributes", pattern)
+		case strings.HasSuffix(pattern, "/"):
+			report("pattern %s matches nothing, directories are not matched recursively; use %s**", pattern, pattern)
+		case extensionPatternRE.MatchString(pattern):
+			// Extension lists are often copied from templates,
+			// so they're expected to mention unused file types.
+		case !c.matchesFiles(dir, pattern):
+			report("pattern %s matches no files", pattern)
+		}
+	}
+	return warnings
+}
+
+// matchesFiles reports whether the pattern of the dir/.gitattributes file
+// matches any of the files.
+func (c *gitattributesChecker) matchesFiles(dir, pattern string) bool {
+	re, err := compileGlob(pattern)
+	if err != nil {
+		return false
+	}
+	files := c.files
+	if c.tree != nil {
+		files = c.tree.files
+	}
+	prefix := dir + "/"
+	for _, f := range files {
+		if f.dir {
+			continue
+		}
+		name := f.origName
+		if dir != "." {
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			name = strings.TrimPrefix(name, prefix)
+		}
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
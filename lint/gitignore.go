@@ -0,0 +1,122 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// gitignoreChecker finds repositories without a root .gitignore
+// and suggests .gitignore entries for the committed unwanted files.
+type gitignoreChecker struct {
+	CheckerBase
+
+	gitignore *File
+
+	// unwanted are the committed unwanted files.
+	unwanted []*File
+
+	// allowIDE disables IDE project files suggestions, like in the unwanted file checker.
+	allowIDE bool
+}
+
+func newGitignoreChecker() *gitignoreChecker {
+	return &gitignoreChecker{}
+}
+
+func (c *gitignoreChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.gitignore = nil
+	c.unwanted = c.unwanted[:0]
+}
+
+// Unwanted files are only reported outside of generated and vendored trees,
+// like in the unwanted file checker.
+func (c *gitignoreChecker) skipGenerated() {}
+
+func (c *gitignoreChecker) PushFile(f *File) {
+	if f.origName == ".gitignore" {
+		f.require.contents = true
+		c.gitignore = f
+		c.AcceptFile(f)
+		return
+	}
+	if _, _, ok := lookupUnwantedFile(f, c.allowIDE); ok {
+		c.unwanted = append(c.unwanted, f)
+		c.AcceptFile(f)
+	}
+}
+
+// A missing .gitignore is found using all repository files.
+func (c *gitignoreChecker) fullTree() {}
+
+func (c *gitignoreChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	var patterns []string
+	if c.gitignore != nil {
+		patterns = gitignorePatterns(c.gitignore.contents)
+	}
+
+	entries := make(map[string]bool)
+	for _, f := range c.unwanted {
+		if gitignoreMatch(patterns, f.origName) {
+			continue
+		}
+		_, k, _ := lookupUnwantedFile(f, c.allowIDE)
+		entries[k.gitignore] = true
+	}
+	suggested := make([]string, 0, len(entries))
+	for entry := range entries {
+		suggested = append(suggested, entry)
+	}
+	sort.Strings(suggested)
+
+	switch {
+	case c.gitignore == nil && len(suggested) == 0:
+		warnings = append(warnings, ".gitignore: missing")
+	case c.gitignore == nil:
+		w := fmt.Sprintf(".gitignore: missing, add entries for the committed unwanted files: %s",
+			strings.Join(suggested, " "))
+		warnings = append(warnings, w)
+	case len(suggested) != 0:
+		w := fmt.Sprintf(".gitignore: add entries for the committed unwanted files: %s",
+			strings.Join(suggested, " "))
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
+
+// gitignorePatterns returns non-negated .gitignore patterns.
+func gitignorePatterns(contents string) []string {
+	var patterns []string
+	for _, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		patterns = append(patterns, strings.TrimSuffix(line, "/"))
+	}
+	return patterns
+}
+
+// gitignoreMatch reports whether filename or any of its parent directories
+// is ignored by any of the patterns.
+// Only the common cases are supported: base name patterns and
+// path patterns without "**" in the middle.
+func gitignoreMatch(patterns []string, filename string) bool {
+	for name := filename; name != "." && name != "/"; name = path.Dir(name) {
+		for _, p := range patterns {
+			p = strings.TrimPrefix(p, "**/")
+			target := path.Base(name)
+			if strings.Contains(p, "/") {
+				p = strings.TrimPrefix(p, "/")
+				target = name
+			}
+			if ok, _ := path.Match(p, target); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// goLatestMinor is the latest Go 1.N release, it should be updated
+	// after every major Go release.
+	goLatestMinor = 27
+
+	// goMaxLag is a number of major Go releases the go directive
+	// can fall behind the latest release.
+	goMaxLag = 4
+)
+
+var (
+	// goDirectiveRE matches the go directive, like "go 1.21" or "go 1.21.5".
+	goDirectiveRE = regexp.MustCompile(`^go\s+1\.(\d+)(?:\.\d+)?$`)
+
+	// majorSuffixRE matches major version module path suffixes, like "/v2".
+	majorSuffixRE = regexp.MustCompile(`/v\d+$`)
+)
+
+// goModFile is a part of the go.mod contents that is checked.
+type goModFile struct {
+	module string
+
+	// goMinor is N of the "go 1.N" directive, zero if it's missing.
+	goMinor int
+
+	requires int
+}
+
+// parseGoMod parses the go.mod directives that are checked.
+func parseGoMod(contents string) goModFile {
+	var mod goModFile
+	inRequire := false
+	for _, line := range strings.Split(contents, "\n") {
+		if i := strings.Index(line, "//"); i != -1 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case inRequire:
+			if line == ")" {
+				inRequire = false
+			} else if line != "" {
+				mod.requires++
+			}
+		case line == "require (" || line == "require(":
+			inRequire = true
+		case strings.HasPrefix(line, "require "):
+			mod.requires++
+		case strings.HasPrefix(line, "module "):
+			mod.module = strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`+"`")
+		default:
+			if m := goDirectiveRE.FindStringSubmatch(line); m != nil {
+				mod.goMinor, _ = strconv.Atoi(m[1])
+			}
+		}
+	}
+	return mod
+}
+
+// goModChecker finds go.mod files with a module path that doesn't match
+// the repository URL, outdated go directives and missing go.sum files.
+type goModChecker struct {
+	CheckerBase
+
+	// repoURL is the checked repository web URL.
+	// Empty if unknown, like in local mode.
+	repoURL string
+
+	// paths is a set of the repository file paths.
+	paths map[string]bool
+}
+
+func newGoModChecker() *goModChecker {
+	return &goModChecker{}
+}
+
+func (c *goModChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.paths = make(map[string]bool)
+}
+
+func (c *goModChecker) PushFile(f *File) {
+	c.paths[f.origName] = true
+	if f.baseName == "go.mod" && !strings.Contains("/"+f.origName, "/testdata/") {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *goModChecker) setRepoURL(u string) {
+	c.repoURL = u
+}
+
+// Missing go.sum files are found using all repository files.
+func (c *goModChecker) fullTree() {}
+
+func (c *goModChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	for _, f := range c.files {
+		mod := parseGoMod(f.contents)
+		dir := path.Dir(f.origName)
+		if want := c.modulePath(dir); want != "" && mod.module != "" && !matchesModulePath(mod.module, want) {
+			w := fmt.Sprintf("%s: module path %s doesn't match the repository URL, expected %s",
+				f.origName, mod.module, want)
+			warnings = append(warnings, w)
+		}
+		if mod.goMinor != 0 && goLatestMinor-mod.goMinor >= goMaxLag {
+			w := fmt.Sprintf("%s: go directive 1.%d is %d releases behind the latest Go 1.%d",
+				f.origName, mod.goMinor, goLatestMinor-mod.goMinor, goLatestMinor)
+			warnings = append(warnings, w)
+		}
+		if mod.requires != 0 && !c.paths[path.Join(dir, "go.sum")] {
+			w := fmt.Sprintf("%s: go.sum is missing, while the module has dependencies", f.origName)
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// modulePath returns the expected path of a module inside dir.
+// Returns empty string if the repository URL is unknown.
+func (c *goModChecker) modulePath(dir string) string {
+	u, err := url.Parse(c.repoURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	p := u.Host + strings.TrimSuffix(u.Path, "/")
+	if dir != "." {
+		p += "/" + dir
+	}
+	return strings.ToLower(p)
+}
+
+// matchesModulePath reports whether module is the expected module path.
+// Major version suffixes are allowed, modules hosted elsewhere
+// (vanity import paths, like go.uber.org/zap) always match.
+func matchesModulePath(module, want string) bool {
+	module = strings.ToLower(module)
+	host := strings.SplitN(want, "/", 2)[0]
+	if !strings.HasPrefix(module, host+"/") {
+		return true
+	}
+	return module == want || majorSuffixRE.ReplaceAllString(module, "") == want
+}
@@ -0,0 +1,110 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// sensitiveFileRE matches base names of files that usually contain
+	// secrets, like private keys and .env files.
+	sensitiveFileRE = regexp.MustCompile(`(?i)^(?:\.env(?:\.[\w.-]+)?|id_(?:rsa|dsa|ecdsa|ed25519)|.+\.(?:pem|key|p12|pfx|jks|keystore|ppk|tfstate)|\.npmrc|\.pypirc|\.netrc|\.htpasswd|credentials(?:\.json)?|secrets?\.(?:ya?ml|json))$`)
+
+	// sensitiveTemplateRE matches example files that are safe to commit, like .env.example.
+	sensitiveTemplateRE = regexp.MustCompile(`(?i)[._-](?:example|sample|template|dist)(?:\.|$)`)
+)
+
+// isSensitiveFile reports whether filename usually contains secrets.
+func isSensitiveFile(filename string) bool {
+	base := path.Base(filename)
+	return sensitiveFileRE.MatchString(base) && !sensitiveTemplateRE.MatchString(base)
+}
+
+// secretHistoryChecker finds sensitive files, like private keys or .env files,
+// that were deleted, but remain in the repository git history.
+//
+// It needs the repository history, so it only works in clone and local modes.
+type secretHistoryChecker struct {
+	CheckerBase
+
+	// enabled is set by the -history flag.
+	enabled bool
+
+	// rootDir is a repository checkout directory.
+	rootDir string
+
+	// paths is a set of the repository file paths.
+	paths map[string]bool
+}
+
+func newSecretHistoryChecker() *secretHistoryChecker {
+	return &secretHistoryChecker{}
+}
+
+func (c *secretHistoryChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.rootDir = ""
+	c.paths = make(map[string]bool)
+}
+
+func (c *secretHistoryChecker) PushFile(f *File) {
+	if !c.enabled || f.rootDir == "" {
+		return
+	}
+	c.rootDir = f.rootDir
+	c.paths[f.origName] = true
+}
+
+// Deleted files are found using all repository files.
+func (c *secretHistoryChecker) fullTree() {}
+
+func (c *secretHistoryChecker) externalTool() string { return "git" }
+
+// Results depend on the repository history.
+func (c *secretHistoryChecker) uncachedResults() {}
+
+func (c *secretHistoryChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	if c.rootDir == "" {
+		return nil
+	}
+	// Renames detection needs blobs, which are not fetched by the history clone.
+	out, err := gitOutput(ctx, c.rootDir, "log", "--no-renames", "--diff-filter=A",
+		"--name-only", "--format=commit %H", "HEAD")
+	if err != nil {
+		return nil
+	}
+
+	// introduced maps a deleted file to the oldest commit that added it.
+	introduced := make(map[string]string)
+	commit := ""
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "commit "):
+			commit = strings.TrimPrefix(line, "commit ")
+		case line != "" && !c.paths[line] && isSensitiveFile(line):
+			// Commits are listed from the newest to the oldest.
+			introduced[line] = commit
+		}
+	}
+
+	files := make([]string, 0, len(introduced))
+	for f := range introduced {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	for _, f := range files {
+		commit := introduced[f]
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		w := fmt.Sprintf("%s: deleted sensitive file remains in git history, added in commit %s; rewrite the history to remove it",
+			f, commit)
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
@@ -0,0 +1,72 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// insecureLinkChecker finds http:// documentation links
+// which targets are also served over HTTPS.
+type insecureLinkChecker struct {
+	CheckerBase
+
+	// links checks the https:// link variants, it shares
+	// the client and the cache with the broken link checker.
+	links *brokenLinkChecker
+}
+
+func newInsecureLinkChecker() *insecureLinkChecker {
+	return &insecureLinkChecker{links: newBrokenLinkChecker()}
+}
+
+// Generated and vendored docs are maintained elsewhere.
+func (c *insecureLinkChecker) skipGenerated() {}
+
+func (c *insecureLinkChecker) PushFile(f *File) {
+	if isDocumentationFile(f.baseName) {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+// Sites may start serving HTTPS without any changes to the file itself.
+func (c *insecureLinkChecker) uncachedResults() {}
+
+func (c *insecureLinkChecker) networkResults() {}
+
+func (c *insecureLinkChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	links := make(map[*File][]string, len(c.files))
+	var urls []string
+	for _, f := range c.files {
+		seen := make(map[string]bool)
+		for _, link := range f.links() {
+			if !strings.HasPrefix(link, "http://") || seen[link] {
+				continue
+			}
+			if c.links.excludeRE != nil && c.links.excludeRE.MatchString(link) {
+				continue
+			}
+			seen[link] = true
+			links[f] = append(links[f], link)
+			urls = append(urls, httpsLink(link))
+		}
+	}
+
+	results := c.links.checkURLs(ctx, urls)
+	for _, f := range c.files {
+		for _, link := range links[f] {
+			secure := httpsLink(link)
+			if r, ok := results[secure]; !ok || r.Err != nil || r.StatusCode >= 400 {
+				continue
+			}
+			w := fmt.Sprintf("%s: %s: reachable over HTTPS, use %s", f.origName, link, secure)
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+func httpsLink(link string) string {
+	return "https://" + strings.TrimPrefix(link, "http://")
+}
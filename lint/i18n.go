@@ -0,0 +1,386 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// i18nConfig overrides the i18n resources checker settings.
+type i18nConfig struct {
+	// BaseLocale is a locale that translations are compared with, "en" by default.
+	BaseLocale string `yaml:"base_locale"`
+}
+
+const defaultBaseLocale = "en"
+
+// i18nMaxListedKeys is a number of missing or extra keys listed in a warning.
+const i18nMaxListedKeys = 5
+
+var (
+	// localeCodeRE matches locale codes, like "en", "pt-BR" or "zh_Hans".
+	localeCodeRE = regexp.MustCompile(`^[a-z]{2,3}(?:[-_][A-Za-z]{2,4})?$`)
+
+	// i18nPlaceholderRE matches interpolation placeholders of the common
+	// i18n libraries: {{name}}, {name}, %s, %1$d and %(name)s.
+	i18nPlaceholderRE = regexp.MustCompile(`\{\{\s*[\w.]+\s*\}\}|\{[\w.]*\}|%\(\w+\)[sdif]|%(?:\d+\$)?[-+#0]*\d*(?:\.\d+)?[sdifuxXeEgGc]`)
+)
+
+// localeDirs are the directory names of JSON locale files.
+var localeDirs = map[string]bool{
+	"locales": true, "locale": true, "i18n": true, "l10n": true, "lang": true,
+	"langs": true, "translations": true, "messages": true,
+}
+
+// localeFile is a translation resource of a single locale.
+type localeFile struct {
+	file   *File
+	locale string
+
+	// group is a key of the files that translate the same resource,
+	// like "locales/*/common.json".
+	group string
+}
+
+// i18nChecker finds keys that are present in the base locale but missing
+// in translations and vice versa, and translations which placeholders
+// don't match the base locale. JSON locale files and gettext .po files are checked.
+type i18nChecker struct {
+	CheckerBase
+
+	baseLocale string
+
+	jsonFiles []localeFile
+	poFiles   []*File
+	potFiles  []*File
+}
+
+func newI18nChecker() *i18nChecker {
+	return &i18nChecker{baseLocale: defaultBaseLocale}
+}
+
+func (c *i18nChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.jsonFiles = nil
+	c.poFiles = nil
+	c.potFiles = nil
+}
+
+// Vendored translations are maintained elsewhere.
+func (c *i18nChecker) skipGenerated() {}
+
+// Translations are compared with the files of other locales.
+func (c *i18nChecker) fullTree() {}
+
+func (c *i18nChecker) PushFile(f *File) {
+	switch path.Ext(f.baseName) {
+	case ".json":
+		lf, ok := parseLocalePath(f)
+		if !ok {
+			return
+		}
+		c.jsonFiles = append(c.jsonFiles, lf)
+	case ".po":
+		c.poFiles = append(c.poFiles, f)
+	case ".pot":
+		c.potFiles = append(c.potFiles, f)
+	default:
+		return
+	}
+	f.require.contents = true
+	c.AcceptFile(f)
+}
+
+// parseLocalePath returns a locale file for the JSON file paths
+// like "locales/en.json" or "locales/en/common.json".
+func parseLocalePath(f *File) (localeFile, bool) {
+	dir := path.Dir(f.origName)
+	if code := strings.TrimSuffix(f.baseName, ".json"); localeCodeRE.MatchString(code) && localeDirs[path.Base(dir)] {
+		return localeFile{file: f, locale: code, group: path.Join(dir, "*.json")}, true
+	}
+	parent := path.Dir(dir)
+	if code := path.Base(dir); localeCodeRE.MatchString(code) && localeDirs[path.Base(parent)] {
+		return localeFile{file: f, locale: code, group: path.Join(parent, "*", f.baseName)}, true
+	}
+	return localeFile{}, false
+}
+
+func (c *i18nChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	warnings = append(warnings, c.checkJSON()...)
+	warnings = append(warnings, c.checkPO()...)
+	return warnings
+}
+
+func (c *i18nChecker) checkJSON() (warnings []string) {
+	groups := make(map[string][]localeFile)
+	var keys []string
+	for _, lf := range c.jsonFiles {
+		if groups[lf.group] == nil {
+			keys = append(keys, lf.group)
+		}
+		groups[lf.group] = append(groups[lf.group], lf)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		files := groups[key]
+		var base *localeFile
+		for i := range files {
+			if files[i].locale == c.baseLocale {
+				base = &files[i]
+			}
+		}
+		if base == nil || len(files) < 2 {
+			continue
+		}
+		baseMessages, err := flattenLocaleJSON(base.file.contents)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", base.file.origName, err))
+			continue
+		}
+		for _, lf := range files {
+			if lf.locale == c.baseLocale {
+				continue
+			}
+			messages, err := flattenLocaleJSON(lf.file.contents)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s: %v", lf.file.origName, err))
+				continue
+			}
+			warnings = append(warnings, compareMessages(lf.file.origName, base.file.baseName, baseMessages, messages)...)
+		}
+	}
+	return warnings
+}
+
+func (c *i18nChecker) checkPO() (warnings []string) {
+	// Templates are found in the same directory or by the gettext domain,
+	// like "locale/ru/LC_MESSAGES/app.po" for "locale/app.pot".
+	potByDir := make(map[string]*File)
+	potByDomain := make(map[string]*File)
+	for _, f := range c.potFiles {
+		potByDir[path.Dir(f.origName)] = f
+		potByDomain[strings.TrimSuffix(f.baseName, ".pot")] = f
+	}
+
+	for _, f := range c.poFiles {
+		entries := parsePO(f.contents)
+		for _, e := range entries {
+			if e.plural || e.msgstr == "" {
+				continue
+			}
+			if !samePlaceholders(e.msgid, e.msgstr) {
+				w := fmt.Sprintf("%s: placeholders of %q don't match the source: %s vs %s",
+					f.origName, e.msgid, formatPlaceholders(e.msgid), formatPlaceholders(e.msgstr))
+				warnings = append(warnings, w)
+			}
+		}
+
+		pot := potByDir[path.Dir(f.origName)]
+		if pot == nil {
+			pot = potByDomain[strings.TrimSuffix(f.baseName, ".po")]
+		}
+		if pot == nil {
+			continue
+		}
+		baseMessages := make(map[string]string)
+		for _, e := range parsePO(pot.contents) {
+			baseMessages[e.key()] = e.msgid
+		}
+		messages := make(map[string]string)
+		for _, e := range entries {
+			messages[e.key()] = e.msgid
+		}
+		// Placeholders are already compared with msgid.
+		warnings = append(warnings, compareKeys(f.origName, pot.baseName, baseMessages, messages)...)
+	}
+	return warnings
+}
+
+// compareMessages compares translations with the base locale messages.
+func compareMessages(filename, baseName string, base, messages map[string]string) (warnings []string) {
+	warnings = compareKeys(filename, baseName, base, messages)
+	for _, k := range sortedMessageKeys(messages) {
+		baseText, ok := base[k]
+		if !ok || samePlaceholders(baseText, messages[k]) {
+			continue
+		}
+		w := fmt.Sprintf("%s: placeholders of %q don't match %s: %s vs %s",
+			filename, k, baseName, formatPlaceholders(baseText), formatPlaceholders(messages[k]))
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
+
+// compareKeys reports the keys missing in messages comparing to base and vice versa.
+func compareKeys(filename, baseName string, base, messages map[string]string) (warnings []string) {
+	var missing, extra []string
+	for _, k := range sortedMessageKeys(base) {
+		if _, ok := messages[k]; !ok {
+			missing = append(missing, k)
+		}
+	}
+	for _, k := range sortedMessageKeys(messages) {
+		if _, ok := base[k]; !ok {
+			extra = append(extra, k)
+		}
+	}
+	if len(missing) != 0 {
+		w := fmt.Sprintf("%s: missing keys of %s (%d): %s", filename, baseName, len(missing), listKeys(missing))
+		warnings = append(warnings, w)
+	}
+	if len(extra) != 0 {
+		w := fmt.Sprintf("%s: keys not in %s (%d): %s", filename, baseName, len(extra), listKeys(extra))
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
+
+func listKeys(keys []string) string {
+	quoted := make([]string, 0, i18nMaxListedKeys)
+	for i, k := range keys {
+		if i == i18nMaxListedKeys {
+			return strings.Join(quoted, ", ") + fmt.Sprintf(" and %d more", len(keys)-i)
+		}
+		quoted = append(quoted, strconv.Quote(k))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func sortedMessageKeys(messages map[string]string) []string {
+	keys := make([]string, 0, len(messages))
+	for k := range messages {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// samePlaceholders reports whether a and b have the same placeholders,
+// regardless of their order.
+func samePlaceholders(a, b string) bool {
+	return formatPlaceholders(a) == formatPlaceholders(b)
+}
+
+// formatPlaceholders returns sorted text placeholders, like "{count} {name}",
+// or "none" if there are no placeholders.
+func formatPlaceholders(text string) string {
+	placeholders := i18nPlaceholderRE.FindAllString(text, -1)
+	if len(placeholders) == 0 {
+		return "none"
+	}
+	for i, p := range placeholders {
+		placeholders[i] = strings.Replace(p, " ", "", -1)
+	}
+	sort.Strings(placeholders)
+	return strings.Join(placeholders, " ")
+}
+
+// flattenLocaleJSON returns locale messages keyed by their dotted paths,
+// like "errors.notFound" for nested objects.
+func flattenLocaleJSON(contents string) (map[string]string, error) {
+	var root map[string]interface{}
+	if err := json.Unmarshal([]byte(contents), &root); err != nil {
+		return nil, err
+	}
+	messages := make(map[string]string)
+	var walk func(prefix string, v interface{})
+	walk = func(prefix string, v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for k, child := range v {
+				if prefix != "" {
+					k = prefix + "." + k
+				}
+				walk(k, child)
+			}
+		case string:
+			messages[prefix] = v
+		default:
+			messages[prefix] = fmt.Sprint(v)
+		}
+	}
+	walk("", root)
+	return messages, nil
+}
+
+// poEntry is a gettext catalog message.
+type poEntry struct {
+	msgctxt string
+	msgid   string
+	msgstr  string
+	plural  bool
+}
+
+func (e poEntry) key() string {
+	if e.msgctxt != "" {
+		return e.msgctxt + "|" + e.msgid
+	}
+	return e.msgid
+}
+
+// parsePO returns the .po or .pot file messages.
+// The header and obsolete messages are skipped.
+func parsePO(contents string) []poEntry {
+	var entries []poEntry
+	var e poEntry
+	var field *string
+	hasID := false
+	flush := func() {
+		if hasID && e.msgid != "" {
+			entries = append(entries, e)
+		}
+		e = poEntry{}
+		field = nil
+		hasID = false
+	}
+	for _, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSpace(line)
+		keyword := line
+		if i := strings.IndexByte(line, ' '); i != -1 {
+			keyword = line[:i]
+		}
+		value := func() string {
+			s, _ := strconv.Unquote(strings.TrimSpace(strings.TrimPrefix(line, keyword)))
+			return s
+		}
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			// Obsolete "#~" messages are comments too.
+			if line == "" {
+				flush()
+			}
+		case strings.HasPrefix(line, `"`):
+			if field != nil {
+				s, _ := strconv.Unquote(line)
+				*field += s
+			}
+		case keyword == "msgctxt":
+			flush()
+			e.msgctxt = value()
+			field = &e.msgctxt
+		case keyword == "msgid":
+			if hasID {
+				flush()
+			}
+			hasID = true
+			e.msgid = value()
+			field = &e.msgid
+		case keyword == "msgid_plural":
+			e.plural = true
+			field = nil
+		case keyword == "msgstr" || keyword == "msgstr[0]":
+			e.msgstr = value()
+			field = &e.msgstr
+		default:
+			field = nil
+		}
+	}
+	flush()
+	return entries
+}
@@ -0,0 +1,117 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// invisibleChars are the zero-width characters that can hide
+// text or make identical-looking identifiers and strings differ.
+var invisibleChars = map[rune]string{
+	'\u200B': "ZERO WIDTH SPACE",
+	'\u2060': "WORD JOINER",
+	'\uFEFF': "ZERO WIDTH NO-BREAK SPACE",
+	'\u180E': "MONGOLIAN VOWEL SEPARATOR",
+	'\u2062': "INVISIBLE TIMES",
+	'\u2063': "INVISIBLE SEPARATOR",
+	'\u2064': "INVISIBLE PLUS",
+}
+
+// joinerChars are the zero-width joiners. They are legitimate in emoji
+// sequences and some scripts, like Persian, so they are only reported
+// between ASCII characters.
+var joinerChars = map[rune]string{
+	'\u200C': "ZERO WIDTH NON-JOINER",
+	'\u200D': "ZERO WIDTH JOINER",
+}
+
+const utf8BOM = "\uFEFF"
+
+// invisibleCharsChecker finds documentation and source files with invalid UTF-8,
+// byte order marks and zero-width characters.
+// BiDi control characters are reported by the bidi checker.
+type invisibleCharsChecker struct {
+	CheckerBase
+	fixBase
+}
+
+func newInvisibleCharsChecker() *invisibleCharsChecker {
+	return &invisibleCharsChecker{}
+}
+
+// Generated files are up to the generator.
+func (c *invisibleCharsChecker) skipGenerated() {}
+
+func (c *invisibleCharsChecker) PushFile(f *File) {
+	if (isSourceFile(f.baseName) || isDocumentationFile(f.baseName)) && f.size <= bidiMaxFileSize {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *invisibleCharsChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	c.fixes = c.fixes[:0]
+	for _, f := range c.files {
+		if ctx.Err() != nil {
+			break
+		}
+		contents := f.contents
+		switch {
+		case strings.HasPrefix(contents, "\xff\xfe") || strings.HasPrefix(contents, "\xfe\xff"):
+			warnings = append(warnings, fmt.Sprintf("%s: UTF-16 encoded file, use UTF-8", f.origName))
+			c.fixes = append(c.fixes, nil)
+			continue
+		case strings.ContainsRune(contents, 0):
+			// Binary file with a source file extension.
+			continue
+		case strings.HasPrefix(contents, utf8BOM):
+			warnings = append(warnings, fmt.Sprintf("%s:1: UTF-8 byte order mark", f.origName))
+			c.fixes = append(c.fixes, &Fix{File: f.origName, Start: 0, End: len(utf8BOM)})
+			// Blank the BOM, so it's not reported as a zero-width character again.
+			contents = strings.Repeat(" ", len(utf8BOM)) + contents[len(utf8BOM):]
+		}
+
+		line := 1
+		invalid := false
+		for offset, r := range contents {
+			switch r {
+			case '\n':
+				line++
+				continue
+			case utf8.RuneError:
+				if !invalid && !strings.HasPrefix(contents[offset:], "\uFFFD") {
+					// Only the first invalid byte is reported, the rest are likely the same.
+					invalid = true
+					w := fmt.Sprintf("%s:%d: invalid UTF-8 at byte offset %d", f.origName, line, offset)
+					warnings = append(warnings, w)
+					c.fixes = append(c.fixes, nil)
+				}
+				continue
+			}
+			name, ok := invisibleChars[r]
+			if !ok {
+				name, ok = joinerChars[r]
+				ok = ok && isASCIIAround(contents, offset, utf8.RuneLen(r))
+			}
+			if !ok {
+				continue
+			}
+			w := fmt.Sprintf("%s:%d: invisible character U+%04X %s at byte offset %d",
+				f.origName, line, r, name, offset)
+			warnings = append(warnings, w)
+			c.fixes = append(c.fixes, nil)
+		}
+	}
+	return warnings
+}
+
+// isASCIIAround reports whether the [offset, offset+n) range of s is surrounded
+// by printable ASCII characters.
+func isASCIIAround(s string, offset, n int) bool {
+	isASCII := func(i int) bool {
+		return i >= 0 && i < len(s) && s[i] > ' ' && s[i] < utf8.RuneSelf
+	}
+	return isASCII(offset-1) && isASCII(offset+n)
+}
@@ -0,0 +1,151 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// issueTemplatesConfig configures the issue templates checker.
+type issueTemplatesConfig struct {
+	// Required are the template kinds that must exist: issue and pull_request.
+	// Both are required by default, an empty list only validates the templates.
+	Required []string `yaml:"required"`
+}
+
+// issueTemplateKinds are the template kinds known to the issue templates checker.
+var issueTemplateKinds = []string{"issue", "pull_request"}
+
+var (
+	// issueTemplateDirRE matches the issue templates directory files.
+	issueTemplateDirRE = regexp.MustCompile(`(?i)^\.github/ISSUE_TEMPLATE/[^/]+$`)
+
+	// issueTemplateRE matches single issue template files.
+	issueTemplateRE = regexp.MustCompile(`(?i)^(?:\.github/|docs/)?ISSUE_TEMPLATE\.(?:md|txt)$`)
+
+	// prTemplateRE matches pull request templates,
+	// including the multiple templates directory files.
+	prTemplateRE = regexp.MustCompile(`(?i)^(?:\.github/|docs/)?PULL_REQUEST_TEMPLATE(?:\.(?:md|txt)|/[^/]+)$`)
+)
+
+// issueTemplatesChecker finds repositories without issue or pull request templates
+// and validates the issue templates: Markdown templates need the YAML front matter
+// with name and about, issue forms need name, description and body.
+type issueTemplatesChecker struct {
+	CheckerBase
+
+	// required are the template kinds that must exist.
+	required []string
+}
+
+func newIssueTemplatesChecker() *issueTemplatesChecker {
+	return &issueTemplatesChecker{required: issueTemplateKinds}
+}
+
+func (c *issueTemplatesChecker) PushFile(f *File) {
+	if f.dir {
+		return
+	}
+	if issueTemplateDirRE.MatchString(f.origName) {
+		f.require.contents = true
+		c.AcceptFile(f)
+		return
+	}
+	if templateKind(f) != "" {
+		c.AcceptFile(f)
+	}
+}
+
+// Missing templates are found using all repository files.
+func (c *issueTemplatesChecker) fullTree() {}
+
+// templateKind returns the kind of a template file, empty for other files.
+// Templates are looked up in the same directories as GitHub does.
+func templateKind(f *File) string {
+	switch {
+	case issueTemplateDirRE.MatchString(f.origName):
+		if strings.EqualFold(f.baseName, "config.yml") {
+			// The template chooser config is not a template.
+			return ""
+		}
+		return "issue"
+	case issueTemplateRE.MatchString(f.origName):
+		return "issue"
+	case prTemplateRE.MatchString(f.origName):
+		return "pull_request"
+	default:
+		return ""
+	}
+}
+
+func (c *issueTemplatesChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	kinds := make(map[string]bool)
+	for _, f := range c.files {
+		kinds[templateKind(f)] = true
+	}
+	for _, kind := range c.required {
+		if !kinds[kind] {
+			w := fmt.Sprintf("no %s template", strings.Replace(kind, "_", " ", -1))
+			warnings = append(warnings, w)
+		}
+	}
+
+	for _, f := range c.files {
+		if !issueTemplateDirRE.MatchString(f.origName) {
+			continue
+		}
+		switch strings.ToLower(path.Ext(f.baseName)) {
+		case ".md":
+			warnings = append(warnings, c.checkMarkdownTemplate(f)...)
+		case ".yml", ".yaml":
+			warnings = append(warnings, c.checkIssueForm(f)...)
+		}
+	}
+	return warnings
+}
+
+// checkMarkdownTemplate validates the Markdown template front matter.
+func (c *issueTemplatesChecker) checkMarkdownTemplate(f *File) []string {
+	contents := strings.Replace(f.contents, "\r\n", "\n", -1)
+	if !strings.HasPrefix(contents, "---\n") {
+		return []string{fmt.Sprintf("%s: no YAML front matter, the template is not listed in the template chooser", f.origName)}
+	}
+	// The leading line break makes an empty front matter end match too.
+	rest := contents[len("---"):]
+	end := strings.Index(rest, "\n---")
+	if end == -1 {
+		return []string{fmt.Sprintf("%s: unterminated YAML front matter", f.origName)}
+	}
+	var fm map[string]interface{}
+	if err := yaml.Unmarshal([]byte(rest[:end]), &fm); err != nil {
+		return []string{fmt.Sprintf("%s: front matter: %v", f.origName, err)}
+	}
+	return missingTemplateFields(f, fm, "name", "about")
+}
+
+// checkIssueForm validates the YAML issue form.
+func (c *issueTemplatesChecker) checkIssueForm(f *File) []string {
+	var form map[string]interface{}
+	if err := yaml.Unmarshal([]byte(f.contents), &form); err != nil {
+		return []string{fmt.Sprintf("%s: %v", f.origName, err)}
+	}
+	if strings.EqualFold(f.baseName, "config.yml") {
+		return nil
+	}
+	return missingTemplateFields(f, form, "name", "description", "body")
+}
+
+// missingTemplateFields reports the required fields that are missing or empty.
+func missingTemplateFields(f *File, fields map[string]interface{}, required ...string) (warnings []string) {
+	for _, name := range required {
+		if v, ok := fields[name]; !ok || v == nil || v == "" {
+			w := fmt.Sprintf("%s: missing required field %q", f.origName, name)
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,248 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// languagesChecker is implemented by the checkers that need
+// repository language statistics from the github API.
+type languagesChecker interface {
+	// setLanguages passes language name to size in bytes mapping.
+	setLanguages(languages map[string]int)
+}
+
+// fetchLanguages passes repo language statistics to the checkers that need it.
+func (l *Runner) fetchLanguages(repo string) {
+	var checkers []languagesChecker
+	for _, c := range l.checkers {
+		if c, ok := c.(languagesChecker); ok {
+			checkers = append(checkers, c)
+		}
+	}
+	if len(checkers) == 0 {
+		return
+	}
+	languages, _, err := l.client.Repositories.ListLanguages(l.ctx, l.user, repo)
+	l.countRequest()
+	if err != nil {
+		log.Printf("\terror: get %s languages: %v", repo, err)
+	}
+	for _, c := range checkers {
+		c.setLanguages(languages)
+	}
+}
+
+// languageInfo describes how to recognize a language project.
+type languageInfo struct {
+	// exts are the language source file extensions.
+	exts []string
+
+	// entrypoints are base name patterns of the files
+	// that make a project buildable, like build manifests.
+	entrypoints []string
+}
+
+var knownLanguages = map[string]languageInfo{
+	"C": {
+		exts:        []string{".c", ".h"},
+		entrypoints: []string{"Makefile", "CMakeLists.txt", "meson.build", "configure", "configure.ac", "BUILD", "BUILD.bazel"},
+	},
+	"C++": {
+		exts:        []string{".cpp", ".cc", ".cxx", ".hpp", ".hh", ".hxx"},
+		entrypoints: []string{"CMakeLists.txt", "Makefile", "meson.build", "configure", "configure.ac", "BUILD", "BUILD.bazel", "*.vcxproj", "*.sln"},
+	},
+	"C#": {
+		exts:        []string{".cs"},
+		entrypoints: []string{"*.csproj", "*.sln"},
+	},
+	"Go": {
+		exts:        []string{".go"},
+		entrypoints: []string{"go.mod", "main.go", "Gopkg.toml", "glide.yaml"},
+	},
+	"Java": {
+		exts:        []string{".java"},
+		entrypoints: []string{"pom.xml", "build.gradle", "build.gradle.kts", "build.xml"},
+	},
+	"JavaScript": {
+		exts:        []string{".js", ".mjs", ".cjs", ".jsx"},
+		entrypoints: []string{"package.json"},
+	},
+	"Kotlin": {
+		exts:        []string{".kt", ".kts"},
+		entrypoints: []string{"build.gradle", "build.gradle.kts", "pom.xml"},
+	},
+	"PHP": {
+		exts:        []string{".php"},
+		entrypoints: []string{"composer.json", "index.php"},
+	},
+	"Python": {
+		exts:        []string{".py"},
+		entrypoints: []string{"setup.py", "pyproject.toml", "setup.cfg", "requirements.txt", "Pipfile", "__main__.py", "manage.py"},
+	},
+	"Ruby": {
+		exts:        []string{".rb"},
+		entrypoints: []string{"Gemfile", "*.gemspec", "Rakefile"},
+	},
+	"Rust": {
+		exts:        []string{".rs"},
+		entrypoints: []string{"Cargo.toml"},
+	},
+	"Scala": {
+		exts:        []string{".scala"},
+		entrypoints: []string{"build.sbt", "pom.xml", "build.sc"},
+	},
+	"Swift": {
+		exts:        []string{".swift"},
+		entrypoints: []string{"Package.swift", "*.xcodeproj", "Podfile"},
+	},
+	"TypeScript": {
+		exts:        []string{".ts", ".tsx"},
+		entrypoints: []string{"package.json", "tsconfig.json"},
+	},
+}
+
+// generatedPathRE matches paths that usually contain vendored
+// or generated code, but are not recognized by linguist by default.
+var generatedPathRE = regexp.MustCompile(`(?:^|/)(?:third_party|thirdparty|external|extern|deps|dist|build|out|gen|generated)/|` +
+	`\.min\.(?:js|css)$|\.bundle\.js$|\.pb\.go$|_pb2\.py$|\.g\.dart$|[._]generated\.[a-z]+$`)
+
+// languageStatsChecker finds repositories which displayed
+// language is skewed by vendored or generated code, and
+// repositories that can't be built with their primary language tools.
+type languageStatsChecker struct {
+	CheckerBase
+
+	languages     map[string]int
+	gitattributes *File
+
+	// tree is the checked repository tree index, if available.
+	tree *repoTree
+}
+
+func newLanguageStatsChecker() *languageStatsChecker {
+	return &languageStatsChecker{}
+}
+
+func (c *languageStatsChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.languages = nil
+	c.gitattributes = nil
+	c.tree = nil
+}
+
+func (c *languageStatsChecker) PushFile(f *File) {
+	if f.origName == ".gitattributes" {
+		f.require.contents = true
+		c.gitattributes = f
+	}
+	c.AcceptFile(f)
+}
+
+func (c *languageStatsChecker) setLanguages(languages map[string]int) {
+	c.languages = languages
+}
+
+// Results depend on the whole repository contents.
+func (c *languageStatsChecker) uncachedResults() {}
+
+// Language statistics describe all files, so the checker
+// is disabled in -diff mode, where only the changed files are pushed.
+func (c *languageStatsChecker) fullTree() {}
+
+// Excluded files are not pushed to the checkers,
+// but they are a part of the language statistics.
+func (c *languageStatsChecker) setTree(t *repoTree) {
+	c.tree = t
+}
+
+func (c *languageStatsChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	primary := c.primaryLanguage()
+	info, ok := knownLanguages[primary]
+	if !ok {
+		return nil
+	}
+
+	var total, generated int64
+	hasEntrypoint := false
+	files := c.files
+	if c.tree != nil {
+		files = c.tree.files
+	}
+	for _, f := range files {
+		if vendorPathRE.MatchString(f.origName) {
+			// Linguist doesn't count vendored dependencies either.
+			continue
+		}
+		isGenerated := generatedPathRE.MatchString(f.origName)
+		if !isGenerated && matchAnyPattern(info.entrypoints, f.baseName) {
+			hasEntrypoint = true
+		}
+		if hasAnyExt(info.exts, f.baseName) {
+			total += f.size
+			if isGenerated {
+				generated += f.size
+			}
+		}
+	}
+
+	switch {
+	case total != 0 && generated*2 > total:
+		if c.gitattributes != nil && strings.Contains(c.gitattributes.contents, "linguist-") {
+			// Linguist overrides are already there, maybe they
+			// are not applied yet or intentionally keep the language.
+			return nil
+		}
+		w := fmt.Sprintf("primary language %s is mostly vendored or generated code (%d%%), mark it as linguist-vendored or linguist-generated in .gitattributes",
+			primary, generated*100/total)
+		warnings = append(warnings, w)
+	case !hasEntrypoint:
+		w := fmt.Sprintf("primary language %s has no build entrypoint, like %s",
+			primary, info.entrypoints[0])
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
+
+// primaryLanguage returns a language that github displays for the repository.
+func (c *languageStatsChecker) primaryLanguage() string {
+	names := make([]string, 0, len(c.languages))
+	for name := range c.languages {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		x, y := c.languages[names[i]], c.languages[names[j]]
+		if x != y {
+			return x > y
+		}
+		return names[i] < names[j]
+	})
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
+
+func matchAnyPattern(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyExt(exts []string, name string) bool {
+	ext := path.Ext(name)
+	for _, x := range exts {
+		if ext == x {
+			return true
+		}
+	}
+	return false
+}
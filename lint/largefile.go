@@ -0,0 +1,81 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// largeFilesConfig overrides the large file checker threshold.
+type largeFilesConfig struct {
+	// MaxSizeMB is a file size that is reported.
+	MaxSizeMB int64 `yaml:"max_size_mb"`
+}
+
+const defaultMaxFileMB = 5
+
+// archiveExts are the extensions of committed archives.
+// Multi-part extensions, like ".tar.gz", are matched by their last part.
+var archiveExts = map[string]bool{
+	".zip": true, ".tar": true, ".gz": true, ".tgz": true, ".bz2": true, ".tbz2": true,
+	".xz": true, ".txz": true, ".zst": true, ".7z": true, ".rar": true,
+	".jar": true, ".war": true, ".ear": true,
+}
+
+// executableExts are the extensions of compiled executables and libraries.
+var executableExts = map[string]bool{
+	".exe": true, ".dll": true, ".so": true, ".dylib": true, ".msi": true,
+	".o": true, ".a": true, ".lib": true, ".obj": true, ".class": true,
+	".deb": true, ".rpm": true, ".dmg": true, ".apk": true,
+}
+
+// buildWrapperFiles are binaries that build tools expect to be committed.
+var buildWrapperFiles = map[string]bool{
+	"gradle/wrapper/gradle-wrapper.jar": true,
+	".mvn/wrapper/maven-wrapper.jar":    true,
+}
+
+// largeFileChecker finds committed archives, executables and files
+// above the size threshold. Only the tree listing sizes are used,
+// so file contents are never downloaded.
+type largeFileChecker struct {
+	CheckerBase
+
+	maxSize int64
+}
+
+func newLargeFileChecker() *largeFileChecker {
+	return &largeFileChecker{maxSize: defaultMaxFileMB << 20}
+}
+
+// Vendored trees are copied as is.
+func (c *largeFileChecker) skipGenerated() {}
+
+// Results depend on the max_size_mb config option and the build wrapper paths.
+func (c *largeFileChecker) uncachedResults() {}
+
+func (c *largeFileChecker) PushFile(f *File) {
+	if !f.symlink && !buildWrapperFiles[f.origName] {
+		c.AcceptFile(f)
+	}
+}
+
+func (c *largeFileChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	for _, f := range c.files {
+		ext := strings.ToLower(path.Ext(f.baseName))
+		switch {
+		case archiveExts[ext]:
+			w := fmt.Sprintf("%s: committed archive, publish it as a release asset instead", f.origName)
+			warnings = append(warnings, w)
+		case executableExts[ext]:
+			w := fmt.Sprintf("%s: committed binary, publish it as a release asset instead", f.origName)
+			warnings = append(warnings, w)
+		case f.size > c.maxSize:
+			w := fmt.Sprintf("%s: file size is %s, exceeds %s, consider moving it to Git LFS",
+				f.origName, formatSize(f.size), formatSize(c.maxSize))
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
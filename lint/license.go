@@ -0,0 +1,335 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// licenseFileRE matches root license file names.
+var licenseFileRE = regexp.MustCompile(`^(?i)(?:LICEN[CS]E|COPYING)(?:\.md|\.txt)?$`)
+
+// licensePatterns detect SPDX license identifiers by the license text.
+// More specific licenses go first.
+var licensePatterns = []struct {
+	id string
+	re *regexp.Regexp
+}{
+	{"AGPL-3.0", regexp.MustCompile(`GNU AFFERO GENERAL PUBLIC LICENSE\s+Version 3`)},
+	{"LGPL-3.0", regexp.MustCompile(`GNU LESSER GENERAL PUBLIC LICENSE\s+Version 3`)},
+	{"LGPL-2.1", regexp.MustCompile(`GNU LESSER GENERAL PUBLIC LICENSE\s+Version 2\.1`)},
+	{"GPL-3.0", regexp.MustCompile(`GNU GENERAL PUBLIC LICENSE\s+Version 3`)},
+	{"GPL-2.0", regexp.MustCompile(`GNU GENERAL PUBLIC LICENSE\s+Version 2`)},
+	{"Apache-2.0", regexp.MustCompile(`Apache License,?\s+Version 2\.0`)},
+	{"MPL-2.0", regexp.MustCompile(`Mozilla Public License,?\s+(?:Version|v\.)\s*2\.0`)},
+	{"BSD-3-Clause", regexp.MustCompile(`(?s)Redistribution and use in source and binary forms.*Neither the name`)},
+	{"BSD-2-Clause", regexp.MustCompile(`Redistribution and use in source and binary forms`)},
+	{"ISC", regexp.MustCompile(`Permission to use, copy, modify, and/or distribute this software for any purpose`)},
+	{"MIT", regexp.MustCompile(`Permission is hereby granted, free of charge`)},
+	{"Unlicense", regexp.MustCompile(`This is free and unencumbered software`)},
+}
+
+// detectLicense returns an SPDX identifier of the license text.
+// Returns empty string for unknown licenses.
+func detectLicense(text string) string {
+	for _, p := range licensePatterns {
+		if p.re.MatchString(text) {
+			return p.id
+		}
+	}
+	return ""
+}
+
+// licenseTemplates are canonical texts of the short licenses without
+// the copyright line. {{}} marks a part that may be replaced,
+// usually by the copyright holder name.
+var licenseTemplates = map[string]string{
+	"MIT": `Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL {{}}
+BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF
+CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.`,
+
+	"ISC": `Permission to use, copy, modify, and/or distribute this software for any
+purpose with or without fee is hereby granted, provided that the above
+copyright notice and this permission notice appear in all copies.
+
+THE SOFTWARE IS PROVIDED "AS IS" AND {{}} DISCLAIMS ALL WARRANTIES
+WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL {{}} BE LIABLE FOR
+ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.`,
+
+	"BSD-2-Clause": bsdLicenseHead + bsdLicenseTail,
+
+	"BSD-3-Clause": bsdLicenseHead + `
+3. Neither the name of {{}} nor the names of its
+   contributors may be used to endorse or promote products derived from
+   this software without specific prior written permission.
+` + bsdLicenseTail,
+}
+
+const bsdLicenseHead = `Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+1. Redistributions of source code must retain the above copyright notice, this
+   list of conditions and the following disclaimer.
+
+2. Redistributions in binary form must reproduce the above copyright notice,
+   this list of conditions and the following disclaimer in the documentation
+   and/or other materials provided with the distribution.
+`
+
+const bsdLicenseTail = `
+THIS SOFTWARE IS PROVIDED BY {{}} "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+DISCLAIMED. IN NO EVENT SHALL {{}} BE LIABLE
+FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.`
+
+// licenseSections are the section titles of the long licenses
+// that limit the authors liability.
+var licenseSections = map[string][]string{
+	"Apache-2.0": {"Disclaimer of Warranty", "Limitation of Liability"},
+	"GPL-2.0":    {"NO WARRANTY"},
+	"GPL-3.0":    {"Disclaimer of Warranty", "Limitation of Liability"},
+	"AGPL-3.0":   {"Disclaimer of Warranty", "Limitation of Liability"},
+	"MPL-2.0":    {"Disclaimer of Warranty", "Limitation of Liability"},
+}
+
+var (
+	// copyrightLineRE matches copyright lines which are
+	// different for every project.
+	copyrightLineRE = regexp.MustCompile(`(?im)^[\s#*]*(?:copyright\s*(?:\(c\)|©|\d{4})|\(c\)|©).*$|all rights reserved\.?`)
+
+	nonWordRE = regexp.MustCompile(`[^a-z0-9{}]+`)
+)
+
+// licenseWords splits a license text into normalized words.
+func licenseWords(text string) []string {
+	text = copyrightLineRE.ReplaceAllString(text, " ")
+	text = strings.Replace(text, "{{}}", " {{}} ", -1)
+	return strings.Fields(nonWordRE.ReplaceAllString(strings.ToLower(text), " "))
+}
+
+// licenseEndings are the closing phrases of the long licenses.
+// A license text without its closing phrase is likely truncated.
+// Short licenses end with the last words of their templates.
+var licenseEndings = map[string]string{
+	"AGPL-3.0":   "END OF TERMS AND CONDITIONS",
+	"LGPL-3.0":   "permanent authorization for you to choose that version for the Library",
+	"LGPL-2.1":   "END OF TERMS AND CONDITIONS",
+	"GPL-3.0":    "END OF TERMS AND CONDITIONS",
+	"GPL-2.0":    "END OF TERMS AND CONDITIONS",
+	"Apache-2.0": "END OF TERMS AND CONDITIONS",
+	"MPL-2.0":    "as defined by the Mozilla Public License",
+	"Unlicense":  "For more information, please refer to",
+}
+
+// licenseEnding returns the normalized closing phrase of the license.
+func licenseEnding(id string) string {
+	if ending, ok := licenseEndings[id]; ok {
+		return strings.Join(licenseWords(ending), " ")
+	}
+	words := licenseWords(licenseTemplates[id])
+	if len(words) > 5 {
+		words = words[len(words)-5:]
+	}
+	return strings.Join(words, " ")
+}
+
+// spdxIdentifierRE matches SPDX license identifier tags,
+// like "SPDX-License-Identifier: MIT OR Apache-2.0".
+var spdxIdentifierRE = regexp.MustCompile(`SPDX-License-Identifier:\s*([\w.+-]+(?:\s+(?:OR|AND|WITH)\s+[\w.+-]+)*)`)
+
+// licenseDetectionChecker classifies the license files by their text
+// and finds the texts that are not recognized or are truncated.
+// Modified texts of the recognized licenses are reported by licenseTamperingChecker.
+type licenseDetectionChecker struct {
+	CheckerBase
+
+	// license is an SPDX identifier detected during the last check.
+	license string
+}
+
+func newLicenseDetectionChecker() *licenseDetectionChecker {
+	return &licenseDetectionChecker{}
+}
+
+func (c *licenseDetectionChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.license = ""
+}
+
+func (c *licenseDetectionChecker) PushFile(f *File) {
+	if licenseFileRE.MatchString(f.origName) {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+// Results also set the detected license, which is not cached.
+func (c *licenseDetectionChecker) uncachedResults() {}
+
+func (c *licenseDetectionChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	for _, f := range c.files {
+		if strings.TrimSpace(f.contents) == "" {
+			// Nothing to classify, the file is probably a stub.
+			continue
+		}
+		id := detectLicense(f.contents)
+		if id == "" {
+			if m := spdxIdentifierRE.FindStringSubmatch(f.contents); m != nil {
+				// The license text is replaced by its identifier.
+				id = m[1]
+			} else {
+				w := fmt.Sprintf("%s: unrecognized license text, compliance tools can't classify it", f.origName)
+				warnings = append(warnings, w)
+				continue
+			}
+		} else if ending := licenseEnding(id); !strings.Contains(strings.Join(licenseWords(f.contents), " "), ending) {
+			w := fmt.Sprintf("%s: %s license text is truncated, it should end with %q", f.origName, id, ending)
+			warnings = append(warnings, w)
+		}
+		if c.license == "" {
+			c.license = id
+		}
+	}
+	return warnings
+}
+
+// licenseTamperingMinWords is a number of added or removed words
+// that makes a license text change substantive.
+// Smaller changes are usually formatting or bullets numbering.
+const licenseTamperingMinWords = 3
+
+// licenseTamperingChecker finds license files which text differs from
+// the canonical text of the detected license: added clauses or removed
+// warranty disclaimers silently change the legal terms.
+type licenseTamperingChecker struct {
+	CheckerBase
+}
+
+func newLicenseTamperingChecker() *licenseTamperingChecker {
+	return &licenseTamperingChecker{}
+}
+
+func (c *licenseTamperingChecker) PushFile(f *File) {
+	if licenseFileRE.MatchString(f.origName) {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *licenseTamperingChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	for _, f := range c.files {
+		id := detectLicense(f.contents)
+		if template, ok := licenseTemplates[id]; ok {
+			for _, change := range licenseChanges(licenseWords(template), licenseWords(f.contents)) {
+				w := fmt.Sprintf("%s: %s license text is modified, %s", f.origName, id, change)
+				warnings = append(warnings, w)
+			}
+		}
+		text := strings.Join(licenseWords(f.contents), " ")
+		for _, section := range licenseSections[id] {
+			if !strings.Contains(text, strings.Join(licenseWords(section), " ")) {
+				w := fmt.Sprintf("%s: %s license has no %q section", f.origName, id, section)
+				warnings = append(warnings, w)
+			}
+		}
+	}
+	return warnings
+}
+
+// licenseChanges returns substantive differences between
+// the canonical license words and the actual ones.
+func licenseChanges(canonical, actual []string) []string {
+	if len(actual) > 10*len(canonical) {
+		// Probably several licenses in one file, don't try to diff them.
+		return nil
+	}
+
+	ops := diffLines(canonical, actual)
+
+	// Slide changes up while they end with the preceding common word,
+	// so added sentences start at their beginning: "the x" + "the y"
+	// is better than "the" + "x the" + "y".
+	for start := 0; start < len(ops); {
+		end := start
+		for end < len(ops) && ops[end].kind != ' ' && ops[end].kind == ops[start].kind {
+			end++
+		}
+		if end == start {
+			start++
+			continue
+		}
+		for start > 0 && ops[start-1].kind == ' ' && ops[start-1].line == ops[end-1].line {
+			ops[start-1].kind, ops[end-1].kind = ops[start].kind, ' '
+			start--
+			end--
+		}
+		start = end
+	}
+
+	var changes []string
+	var removed, added []string
+	placeholder := false
+	matched := false
+	flush := func() {
+		// Words around placeholders are expected to differ.
+		// Added words before the license body are usually its title.
+		if !placeholder {
+			if len(removed) >= licenseTamperingMinWords {
+				changes = append(changes, "removed: "+quoteWords(removed))
+			}
+			if len(added) >= licenseTamperingMinWords && matched {
+				changes = append(changes, "added: "+quoteWords(added))
+			}
+		}
+		removed, added = removed[:0], added[:0]
+		placeholder = false
+	}
+	for _, op := range ops {
+		switch {
+		case op.kind == ' ':
+			flush()
+			matched = true
+		case op.line == "{{}}":
+			placeholder = true
+		case op.kind == '-':
+			removed = append(removed, op.line)
+		default:
+			added = append(added, op.line)
+		}
+	}
+	flush()
+	return changes
+}
+
+// quoteWords returns a quoted beginning of the words list.
+func quoteWords(words []string) string {
+	const maxWords = 10
+	if len(words) > maxWords {
+		return fmt.Sprintf("%q", strings.Join(words[:maxWords], " ")+" ...")
+	}
+	return fmt.Sprintf("%q", strings.Join(words, " "))
+}
@@ -0,0 +1,125 @@
+package lint
+
+import (
+	"strings"
+)
+
+// generatedSkipper is implemented by the checkers that don't check
+// generated and vendored files, like spelling and hygiene checkers.
+//
+// Files are marked as generated or vendored by the linguist-generated and
+// linguist-vendored attributes in the root .gitattributes file.
+type generatedSkipper interface {
+	skipGenerated()
+}
+
+// linguistAttrs are .gitattributes attributes that exclude files
+// from the generatedSkipper checkers.
+var linguistAttrs = []string{"linguist-generated", "linguist-vendored"}
+
+// gitattributesRule is a single .gitattributes line that sets or unsets
+// any of the linguistAttrs.
+type gitattributesRule struct {
+	matcher *pathMatcher
+
+	// generated reports whether the rule marks files as generated.
+	// False means the marker is unset by the rule.
+	generated bool
+}
+
+// parseLinguistRules returns linguistAttrs rules from the .gitattributes contents.
+// Lines with malformed patterns are ignored.
+func parseLinguistRules(contents string) []gitattributesRule {
+	var rules []gitattributesRule
+	for _, line := range strings.Split(contents, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		matcher, err := newPathMatcher(fields[:1])
+		if err != nil {
+			continue
+		}
+		for _, attr := range fields[1:] {
+			if generated, ok := linguistAttrValue(attr); ok {
+				rules = append(rules, gitattributesRule{matcher: matcher, generated: generated})
+			}
+		}
+	}
+	return rules
+}
+
+// linguistAttrValue parses a single .gitattributes attribute.
+// ok is false if attr is not one of the linguistAttrs.
+func linguistAttrValue(attr string) (generated, ok bool) {
+	value := true
+	switch {
+	case strings.HasPrefix(attr, "-"), strings.HasPrefix(attr, "!"):
+		attr = attr[1:]
+		value = false
+	case strings.HasSuffix(attr, "=false"):
+		attr = strings.TrimSuffix(attr, "=false")
+		value = false
+	case strings.HasSuffix(attr, "=true"):
+		attr = strings.TrimSuffix(attr, "=true")
+	}
+	for _, name := range linguistAttrs {
+		if attr == name {
+			return value, true
+		}
+	}
+	return false, false
+}
+
+// markGenerated sets the generated flag for files marked
+// by the root .gitattributes linguist attributes.
+// The .gitattributes contents must be already fetched.
+func markGenerated(files []*File) {
+	var rules []gitattributesRule
+	for _, f := range files {
+		if f.origName == ".gitattributes" {
+			rules = parseLinguistRules(f.contents)
+		}
+	}
+	if len(rules) == 0 {
+		return
+	}
+	for _, f := range files {
+		// The last matching rule wins, like in git.
+		for _, r := range rules {
+			if r.matcher.Match(f.origName) {
+				f.generated = r.generated
+			}
+		}
+	}
+}
+
+// pushFiles pushes files to c, skipping the generated ones if c doesn't check them,
+// and the dangling symlinks if c is not a danglingLinkChecker.
+func (l *Runner) pushFiles(c Checker, files []*File) {
+	_, skip := c.(generatedSkipper)
+	_, links := c.(danglingLinkChecker)
+	for _, f := range files {
+		if skip && f.generated && !l.includeGenerated {
+			continue
+		}
+		if f.isDanglingLink() && !links {
+			continue
+		}
+		c.PushFile(f)
+	}
+}
+
+// fetchGitattributes fetches the root .gitattributes contents,
+// so generated files are known before files are pushed to the checkers.
+func (l *Runner) fetchGitattributes(repo string, files []*File) {
+	if l.includeGenerated {
+		return
+	}
+	for _, f := range files {
+		if f.origName == ".gitattributes" && !f.symlink {
+			f.require.contents = true
+			l.fetcher.ResolveRequirements(repo, f)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package lint
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// linkCache stores web link check results between runs,
+// so links shared by many repositories (like badges) are fetched once.
+type linkCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	Entries map[string]linkCacheEntry `json:"entries"`
+}
+
+type linkCacheEntry struct {
+	StatusCode int       `json:"status,omitempty"`
+	Checked    time.Time `json:"checked"`
+}
+
+func loadLinkCache(filename string, ttl time.Duration) (*linkCache, error) {
+	lc := &linkCache{
+		ttl:     ttl,
+		Entries: make(map[string]linkCacheEntry),
+	}
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return lc, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, lc); err != nil {
+		return nil, err
+	}
+	if lc.Entries == nil {
+		lc.Entries = make(map[string]linkCacheEntry)
+	}
+	return lc, nil
+}
+
+// save writes all non-expired entries to the file.
+func (lc *linkCache) save(filename string) error {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	for u, e := range lc.Entries {
+		if time.Since(e.Checked) > lc.ttl {
+			delete(lc.Entries, u)
+		}
+	}
+	data, err := json.Marshal(lc)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+// get returns a cached result for u, if it's not expired.
+func (lc *linkCache) get(u string) (linkResult, bool) {
+	lc.mu.Lock()
+	e, ok := lc.Entries[u]
+	lc.mu.Unlock()
+	// Older cache files have network errors stored without a status.
+	if !ok || e.StatusCode == 0 || time.Since(e.Checked) > lc.ttl {
+		return linkResult{}, false
+	}
+	return linkResult{StatusCode: e.StatusCode}, true
+}
+
+// put stores r as a u check result.
+// Only HTTP responses are stored: network errors, like DNS failures
+// or connection resets, are often temporary and are checked again.
+func (lc *linkCache) put(u string, r linkResult) {
+	if r.Err != nil || r.StatusCode == http.StatusTooManyRequests {
+		return
+	}
+	e := linkCacheEntry{StatusCode: r.StatusCode, Checked: time.Now()}
+	lc.mu.Lock()
+	lc.Entries[u] = e
+	lc.mu.Unlock()
+}
+
+func (l *Runner) loadLinkCache() error {
+	if l.noCache {
+		return nil
+	}
+	if l.linkCacheFile == "" {
+		if l.container {
+			// Container mode doesn't rely on $HOME.
+			return nil
+		}
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			if l.verbose {
+				log.Printf("\t\tdebug: link cache is disabled: %v", err)
+			}
+			return nil
+		}
+		l.linkCacheFile = filepath.Join(dir, "repolint", "links.json")
+	}
+	lc, err := loadLinkCache(l.linkCacheFile, l.linkCacheTTL)
+	if err != nil {
+		return err
+	}
+	for _, c := range l.checkers {
+		switch c := c.(type) {
+		case *brokenLinkChecker:
+			c.cache = lc
+		case *badgeChecker:
+			c.links.cache = lc
+		case *insecureLinkChecker:
+			c.links.cache = lc
+		}
+	}
+	l.linkCache = lc
+	return nil
+}
+
+func (l *Runner) saveLinkCache() error {
+	if l.linkCache == nil {
+		return nil
+	}
+	return l.linkCache.save(l.linkCacheFile)
+}
@@ -0,0 +1,525 @@
+package lint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// defaultLinkExclude matches web links that are not checked by default.
+const defaultLinkExclude = `/release|/download|localhost|127\.[01]\.[01]\.[01]|example\.com`
+
+// brokenLinkChecker finds documentation links that can't be followed.
+type brokenLinkChecker struct {
+	CheckerBase
+
+	client      *http.Client
+	timeout     time.Duration
+	concurrency int
+
+	// excludeRE matches web links that are not checked.
+	// Relative links are always checked.
+	// Nil means that all links are checked.
+	excludeRE *regexp.Regexp
+
+	// cache is an optional web link results cache.
+	cache *linkCache
+
+	// tree is the checked repository tree index, if available.
+	tree *repoTree
+}
+
+func newBrokenLinkChecker() *brokenLinkChecker {
+	return &brokenLinkChecker{
+		client:      &http.Client{},
+		timeout:     30 * time.Second,
+		concurrency: 8,
+		excludeRE:   regexp.MustCompile(defaultLinkExclude),
+	}
+}
+
+// Generated and vendored docs are maintained elsewhere.
+func (c *brokenLinkChecker) skipGenerated() {}
+
+func (c *brokenLinkChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.tree = nil
+}
+
+func (c *brokenLinkChecker) setTree(t *repoTree) {
+	c.tree = t
+}
+
+func (c *brokenLinkChecker) PushFile(f *File) {
+	if isDocumentationFile(f.baseName) {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+// Links may become broken without any changes to the file itself.
+func (c *brokenLinkChecker) uncachedResults() {}
+
+func (c *brokenLinkChecker) networkResults() {}
+
+// maxDocumentLinks limits the number of links checked per document,
+// so a generated or malicious document can't make thousands of requests.
+const maxDocumentLinks = 1000
+
+func (c *brokenLinkChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	links := make(map[*File][]string, len(c.files))
+	docs := make(map[string]*File, len(c.files))
+	var urls []string
+	for _, f := range c.files {
+		docs[f.origName] = f
+		badges := make(map[string]bool)
+		for _, b := range f.badges() {
+			badges[b.image] = true
+		}
+		fileLinks := f.links()
+		if len(fileLinks) > maxDocumentLinks {
+			log.Printf("\t%s: only the first %d of %d links are checked",
+				f.origName, maxDocumentLinks, len(fileLinks))
+			fileLinks = fileLinks[:maxDocumentLinks]
+		}
+		for _, link := range fileLinks {
+			if isWebLink(link) && c.excludeRE != nil && c.excludeRE.MatchString(link) {
+				continue
+			}
+			if badges[link] {
+				// Checked by the badge checker.
+				continue
+			}
+			links[f] = append(links[f], link)
+			if isWebLink(link) {
+				urls = append(urls, link)
+			}
+		}
+	}
+
+	results := c.checkURLs(ctx, urls)
+	for _, f := range c.files {
+		for _, link := range links[f] {
+			var problem string
+			switch {
+			case isWebLink(link):
+				problem = results[link].problem()
+			case !linkSchemeRE.MatchString(link):
+				problem = checkFileLink(docs, c.tree, f, link)
+			}
+			if problem != "" {
+				w := fmt.Sprintf("%s: %s: %s", f.origName, link, problem)
+				warnings = append(warnings, w)
+			}
+		}
+	}
+	return warnings
+}
+
+// linkResult is a web link check result.
+type linkResult struct {
+	// StatusCode is a HTTP response status code.
+	// Zero if request failed without a response.
+	StatusCode int
+
+	// Err is a request error, if any.
+	Err error
+}
+
+// problem returns a link problem description.
+// Returns empty string for good links.
+func (r linkResult) problem() string {
+	if r.Err != nil {
+		if isTimeout(r.Err) || errors.Is(r.Err, context.Canceled) {
+			// Reporting timeouts can lead to a lots of false positives.
+			// Better to skip them silently.
+			return ""
+		}
+		return r.Err.Error()
+	}
+	switch {
+	case r.StatusCode < 400:
+		return ""
+	case r.StatusCode == http.StatusTooManyRequests:
+		// Rate limited, the link itself can be fine.
+		return ""
+	default:
+		return fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode))
+	}
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// checkURLs checks all unique urls concurrently.
+func (c *brokenLinkChecker) checkURLs(ctx context.Context, urls []string) map[string]linkResult {
+	results := make(map[string]linkResult, len(urls))
+	queue := make(chan string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < c.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range queue {
+				r := c.cachedCheckURL(ctx, u)
+				mu.Lock()
+				results[u] = r
+				mu.Unlock()
+			}
+		}()
+	}
+	seen := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		if ctx.Err() != nil {
+			break
+		}
+		if !seen[u] {
+			seen[u] = true
+			queue <- u
+		}
+	}
+	close(queue)
+	wg.Wait()
+	return results
+}
+
+func (c *brokenLinkChecker) cachedCheckURL(ctx context.Context, u string) linkResult {
+	if c.cache == nil {
+		return c.checkURL(ctx, u)
+	}
+	if r, ok := c.cache.get(u); ok {
+		return r
+	}
+	r := c.checkURL(ctx, u)
+	if ctx.Err() == nil {
+		c.cache.put(u, r)
+	}
+	return r
+}
+
+func (c *brokenLinkChecker) checkURL(ctx context.Context, u string) linkResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	r := c.request(ctx, "HEAD", u)
+	if r.Err == nil && r.StatusCode >= 400 {
+		// Some servers don't handle HEAD requests properly.
+		r = c.request(ctx, "GET", u)
+	}
+	return r
+}
+
+func (c *brokenLinkChecker) request(ctx context.Context, method, u string) linkResult {
+	req, err := http.NewRequest(method, u, nil)
+	if err != nil {
+		return linkResult{Err: err}
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("User-Agent", "repolint")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			// Strip "Head <url>:" prefix, url is reported anyway.
+			err = urlErr.Err
+		}
+		return linkResult{Err: err}
+	}
+	resp.Body.Close()
+	return linkResult{StatusCode: resp.StatusCode}
+}
+
+// checkFileLink checks that a relative link points to an existing file
+// and that the link fragment matches a heading of a markdown document.
+//
+// docs are the checked files, indexed by their original names.
+// Other files are looked up in the repository checkout or, if f is not
+// a part of a checkout, in the tree index. Without both, they are not checked.
+func checkFileLink(docs map[string]*File, tree *repoTree, f *File, link string) string {
+	target, fragment := link, ""
+	if i := strings.IndexByte(target, '#'); i != -1 {
+		target, fragment = target[:i], target[i+1:]
+	}
+	if i := strings.IndexByte(target, '?'); i != -1 {
+		target = target[:i]
+	}
+	if target == "" {
+		if fragment == "" || !isMarkdownFile(f.baseName) {
+			return ""
+		}
+		return checkAnchor(f.anchors(), fragment)
+	}
+
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+	var p string
+	if strings.HasPrefix(target, "/") {
+		p = path.Clean(target)[1:]
+	} else {
+		p = path.Join(path.Dir(f.origName), target)
+	}
+	if p == ".." || strings.HasPrefix(p, "../") {
+		return ""
+	}
+
+	if doc, ok := docs[p]; ok {
+		if fragment == "" || !isMarkdownFile(doc.baseName) {
+			return ""
+		}
+		return checkAnchor(doc.anchors(), fragment)
+	}
+	if f.rootDir == "" {
+		if tree != nil && !tree.has(p) {
+			return missingFileProblem(tree, f, p)
+		}
+		return ""
+	}
+	filename := filepath.Join(f.rootDir, filepath.FromSlash(p))
+	info, err := os.Stat(filename)
+	if err != nil {
+		return missingFileProblem(tree, f, p)
+	}
+	if fragment == "" || info.IsDir() || !isMarkdownFile(p) {
+		return ""
+	}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return ""
+	}
+	return checkAnchor(documentAnchors(string(data)), fragment)
+}
+
+// missingFileProblem describes a link to a missing path p.
+// If a file with the same name exists elsewhere, it's suggested as
+// a new location, relative to the f directory when possible.
+func missingFileProblem(tree *repoTree, f *File, p string) string {
+	if tree == nil {
+		return "no such file"
+	}
+	moved := tree.findMoved(p)
+	if moved == "" {
+		return "no such file"
+	}
+	if dir := path.Dir(f.origName); dir == "." {
+		// Already relative to the root.
+	} else if strings.HasPrefix(moved, dir+"/") {
+		moved = strings.TrimPrefix(moved, dir+"/")
+	} else {
+		moved = "/" + moved
+	}
+	return fmt.Sprintf("no such file, maybe it was moved to %s", moved)
+}
+
+// checkAnchor checks that the markdown document anchors contain the fragment.
+func checkAnchor(anchors map[string]bool, fragment string) string {
+	if unescaped, err := url.PathUnescape(fragment); err == nil {
+		fragment = unescaped
+	}
+	fragment = strings.TrimPrefix(strings.ToLower(fragment), "user-content-")
+	if fragment == "" || fragment == "top" || lineAnchorRE.MatchString(fragment) {
+		return ""
+	}
+	if !anchors[fragment] {
+		return "no such anchor"
+	}
+	return ""
+}
+
+func isMarkdownFile(filename string) bool {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".md", ".markdown", ".mdown", ".mkd":
+		return true
+	default:
+		return false
+	}
+}
+
+// documentAnchors returns a set of anchors that GitHub generates
+// for the markdown document headings, plus explicit HTML anchors.
+// Anchors are lower-cased.
+func documentAnchors(doc string) map[string]bool {
+	prose := blankCodeBlocks(doc)
+	return proseAnchors(prose, proseHeadings(prose))
+}
+
+// proseAnchors is documentAnchors for a document with blanked code blocks and its headings.
+func proseAnchors(doc string, headings []string) map[string]bool {
+	anchors := make(map[string]bool)
+	counts := make(map[string]int)
+	for _, heading := range headings {
+		slug := anchorSlug(heading)
+		n := counts[slug]
+		counts[slug]++
+		if n != 0 {
+			slug = fmt.Sprintf("%s-%d", slug, n)
+		}
+		anchors[slug] = true
+	}
+	for _, m := range htmlAnchorRE.FindAllStringSubmatch(doc, -1) {
+		anchors[strings.ToLower(m[1])] = true
+	}
+	return anchors
+}
+
+// markdownHeadings returns the markdown document headings text.
+func markdownHeadings(doc string) []string {
+	return proseHeadings(blankCodeBlocks(doc))
+}
+
+// proseHeadings is markdownHeadings for a document with blanked code blocks.
+func proseHeadings(doc string) []string {
+	var headings []string
+	lines := strings.Split(doc, "\n")
+	for i, line := range lines {
+		if m := atxHeadingRE.FindStringSubmatch(line); m != nil {
+			headings = append(headings, m[1])
+			continue
+		}
+		if i+1 < len(lines) && strings.TrimSpace(line) != "" && setextUnderlineRE.MatchString(lines[i+1]) {
+			headings = append(headings, strings.TrimSpace(line))
+		}
+	}
+	return headings
+}
+
+// anchorSlug converts a heading text into an anchor name the same way GitHub does.
+func anchorSlug(heading string) string {
+	heading = inlineLinkRE.ReplaceAllString(heading, "$1")
+	heading = htmlTagRE.ReplaceAllString(heading, "")
+	var buf strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), unicode.IsMark(r), r == '-', r == '_':
+			buf.WriteRune(r)
+		case r == ' ':
+			buf.WriteByte('-')
+		}
+	}
+	return buf.String()
+}
+
+// blankCodeBlocks replaces fenced code blocks contents with spaces.
+// Line breaks and byte offsets are preserved,
+// so every byte, not rune, is replaced.
+func blankCodeBlocks(doc string) string {
+	return fencedCodeRE.ReplaceAllStringFunc(doc, func(block string) string {
+		b := []byte(block)
+		for i := range b {
+			if b[i] != '\n' {
+				b[i] = ' '
+			}
+		}
+		return string(b)
+	})
+}
+
+func isWebLink(link string) bool {
+	return strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://")
+}
+
+var (
+	// linkSchemeRE matches links with a scheme, like "ftp://" or "irc:".
+	linkSchemeRE = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+
+	// lineAnchorRE matches source code line anchors, like "L10" or "L10-L20".
+	lineAnchorRE = regexp.MustCompile(`^l\d+(?:-l\d+)?$`)
+
+	// atxHeadingRE matches "# heading" lines.
+	atxHeadingRE = regexp.MustCompile(`^ {0,3}#{1,6}\s+(.*?)(?:\s+#+)?\s*$`)
+
+	// setextUnderlineRE matches "===" and "---" heading underlines.
+	setextUnderlineRE = regexp.MustCompile(`^ {0,3}(?:=+|-+)\s*$`)
+
+	// htmlAnchorRE matches explicit HTML anchors, like <a name="anchor">.
+	htmlAnchorRE = regexp.MustCompile(`<[a-zA-Z][^>]*\s(?:name|id)\s*=\s*"([^"]+)"`)
+
+	// inlineLinkRE matches [text](target) links, first submatch is the text.
+	inlineLinkRE = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+
+	// htmlTagRE matches HTML tags.
+	htmlTagRE = regexp.MustCompile(`<[^>]+>`)
+
+	// fencedCodeRE matches markdown fenced code blocks.
+	fencedCodeRE = regexp.MustCompile("(?ms)^\\s*(```|~~~).*?^\\s*(```|~~~)")
+
+	// linkREs match different kinds of links.
+	// First submatch group is a link target.
+	linkREs = []*regexp.Regexp{
+		// -> [text](target "title") and ![alt](target)
+		regexp.MustCompile(`\]\(\s*<?([^)\s>]+)>?(?:\s+["'(][^)]*)?\)`),
+		// -> [id]: target
+		regexp.MustCompile(`(?m)^\s{0,3}\[[^\]]+\]:\s*<?([^\s>]+)`),
+		// -> <https://target>
+		regexp.MustCompile(`<(https?://[^>\s]+)>`),
+		// -> <a href="target"> and <img src="target">
+		regexp.MustCompile(`(?:href|src)\s*=\s*"([^"]+)"`),
+		// -> https://target
+		regexp.MustCompile("(https?://[^\\s<>()\\[\\]\"'`]+)"),
+	}
+)
+
+// extractLinks returns all unique links inside the document
+// in the order of their appearance.
+// Links inside fenced code blocks are ignored.
+func extractLinks(doc string) []string {
+	return proseLinks(blankCodeBlocks(doc))
+}
+
+// proseLinks is extractLinks for a document with blanked code blocks.
+func proseLinks(doc string) []string {
+	type match struct {
+		pos  int
+		link string
+	}
+	var matches []match
+	covered := make(map[int]bool)
+	for _, re := range linkREs {
+		for _, m := range re.FindAllStringSubmatchIndex(doc, -1) {
+			start, end := m[2], m[3]
+			if covered[start] {
+				// Already matched by a more specific pattern.
+				continue
+			}
+			covered[start] = true
+			link := strings.TrimRight(doc[start:end], ".,;:!?*_")
+			if link == "" || link == "#" || strings.HasPrefix(link, "mailto:") {
+				continue
+			}
+			matches = append(matches, match{pos: start, link: link})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].pos < matches[j].pos
+	})
+
+	var links []string
+	seen := make(map[string]bool)
+	for _, m := range matches {
+		if !seen[m.link] {
+			seen[m.link] = true
+			links = append(links, m.link)
+		}
+	}
+	return links
+}
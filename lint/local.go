@@ -0,0 +1,105 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// initLocalMode prepares a local directory check, see -dir.
+// Checkers that need github API are disabled.
+func (l *Runner) initLocalMode() error {
+	if l.dryRun && !l.fix {
+		return errors.New("-dry-run requires -fix argument")
+	}
+	if l.dir == "" {
+		if l.fix {
+			return errors.New("-fix requires -dir argument")
+		}
+		return nil
+	}
+
+	if l.container {
+		return errors.New("-dir is not supported in container mode")
+	}
+	if l.diff != "" || l.pr != 0 {
+		return errors.New("-dir can't be used with -diff and -pr")
+	}
+	if l.config.Template != nil {
+		return errors.New("-dir can't be used with the template config")
+	}
+	dir, err := filepath.Abs(l.dir)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", l.dir)
+	}
+	l.dir = dir
+	if l.repo == "" {
+		l.repo = filepath.Base(dir)
+	}
+
+	for name, c := range l.checkers {
+		_, metadata := c.(metadataChecker)
+		_, languages := c.(languagesChecker)
+		if metadata || languages {
+			if l.verbose {
+				log.Printf("\t\tdebug: disable %s checker: it requires github API", name)
+			}
+			delete(l.checkers, name)
+		}
+	}
+	return nil
+}
+
+// localFetcher reads files from the -dir directory.
+// Files are used in place, nothing is copied.
+type localFetcher struct {
+	l *Runner
+}
+
+func (lf *localFetcher) CollectFiles(repo string) ([]*File, error) {
+	// Git blob hashes are not collected: the working tree can have
+	// uncommitted changes, so the index hashes can be wrong.
+	// Modes are, since symlinks can be checked out as plain files.
+	var index map[string]indexEntry
+	if _, err := os.Stat(filepath.Join(lf.l.dir, ".git")); err == nil {
+		if entries, err := readGitIndex(lf.l.ctx, lf.l.dir); err == nil {
+			index = make(map[string]indexEntry, len(entries))
+			for p, e := range entries {
+				index[p] = indexEntry{mode: e.mode}
+			}
+		}
+	}
+	return walkRepoDir(lf.l.dir, index)
+}
+
+func (lf *localFetcher) ResolveRequirements(repo string, f *File) {
+	readLocalContents(repo, f)
+}
+
+func (lf *localFetcher) LinkTarget(repo string, f *File) (string, error) {
+	return readLinkTarget(filepath.Join(lf.l.dir, filepath.FromSlash(f.origName)))
+}
+
+func (lf *localFetcher) RequestsCost(f *File) int { return 0 }
+
+func (lf *localFetcher) CommitSHA(repo string) (string, error) {
+	if _, err := os.Stat(filepath.Join(lf.l.dir, ".git")); err != nil {
+		// Not a git repository.
+		return "", nil
+	}
+	out, err := exec.CommandContext(lf.l.ctx, "git", "-C", lf.l.dir, "rev-parse", "HEAD").Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+func (lf *localFetcher) Cleanup(repo string) {}
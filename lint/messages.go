@@ -0,0 +1,334 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultLanguage is the language of the messages in the source code.
+const defaultLanguage = "en"
+
+// messageCatalogs map English message formats to their translations
+// by language. Warnings and CLI help are translated only when printed,
+// so checker names, JSON reports and baseline files stay language-neutral.
+//
+// A translation uses the same verbs in the same order as the English format,
+// %[n]s selects the n-th argument when the order differs.
+// Messages without a translation are printed in English.
+var messageCatalogs = map[string]map[string]string{
+	"ru": ruMessages,
+}
+
+// supportedLanguages returns the -lang values, English first.
+func supportedLanguages() []string {
+	langs := []string{defaultLanguage}
+	for lang := range messageCatalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs[1:])
+	return langs
+}
+
+// localeVars are the locale environment variables in the POSIX precedence order.
+var localeVars = []string{"LC_ALL", "LC_MESSAGES", "LANG"}
+
+// detectLanguage returns the message language of the locale environment,
+// like ru for ru_RU.UTF-8. Unsupported locales fall back to English.
+func detectLanguage(getenv func(string) string) string {
+	for _, name := range localeVars {
+		locale := getenv(name)
+		if locale == "" {
+			continue
+		}
+		lang := strings.ToLower(locale)
+		if i := strings.IndexAny(lang, "_.@-"); i != -1 {
+			lang = lang[:i]
+		}
+		if _, ok := messageCatalogs[lang]; ok {
+			return lang
+		}
+		return defaultLanguage
+	}
+	return defaultLanguage
+}
+
+var (
+	// formatVerbRE matches fmt verbs, like %s, %[2]q or %04X.
+	formatVerbRE = regexp.MustCompile(`%(?:\[(\d+)\])?[-+# 0]*\d*(?:\.\d+)?([a-zA-Z%])`)
+
+	// formatVerbPatterns match the formatted verb values.
+	// Other verbs match any text.
+	formatVerbPatterns = map[string]string{
+		"d": `(-?\d+)`,
+		"q": `("(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*')`,
+		"x": `([0-9a-fA-F]+)`,
+		"X": `([0-9a-fA-F]+)`,
+		"f": `(-?[0-9.]+)`,
+	}
+)
+
+// messageTranslation is a compiled catalog entry.
+type messageTranslation struct {
+	// re matches the formatted English message, with an optional
+	// location prefix, like "README.md:12: ".
+	re *regexp.Regexp
+
+	// format is the translated format with the %[n]s verbs only,
+	// where n is the re submatch index.
+	format string
+}
+
+// messageCatalog translates messages to a single language.
+// Nil catalog leaves messages in English.
+type messageCatalog struct {
+	texts        map[string]string
+	translations []messageTranslation
+}
+
+// newMessageCatalog returns a catalog for the language,
+// nil for English.
+func newMessageCatalog(lang string) (*messageCatalog, error) {
+	if lang == defaultLanguage {
+		return nil, nil
+	}
+	texts, ok := messageCatalogs[lang]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language %q, expected one of: %s",
+			lang, strings.Join(supportedLanguages(), ", "))
+	}
+	c := &messageCatalog{texts: texts}
+	for english, translated := range texts {
+		if !formatVerbRE.MatchString(english) && !formatVerbRE.MatchString(translated) {
+			continue
+		}
+		c.translations = append(c.translations, messageTranslation{
+			re:     compileMessageFormat(english),
+			format: indexFormatVerbs(translated),
+		})
+	}
+	// Longer formats are more specific, like "%s license text is modified, %s"
+	// and "%s could be a misspelling of %s".
+	sort.Slice(c.translations, func(i, j int) bool {
+		a, b := c.translations[i].re.String(), c.translations[j].re.String()
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		return a < b
+	})
+	return c, nil
+}
+
+// compileMessageFormat returns a regexp that matches messages formatted
+// with the English format, prefixed by a location or not.
+func compileMessageFormat(format string) *regexp.Regexp {
+	var buf strings.Builder
+	buf.WriteString(`^(?:(.+?): )?`)
+	last := 0
+	for _, m := range formatVerbRE.FindAllStringSubmatchIndex(format, -1) {
+		buf.WriteString(regexp.QuoteMeta(format[last:m[0]]))
+		last = m[1]
+		verb := format[m[4]:m[5]]
+		switch p, ok := formatVerbPatterns[verb]; {
+		case verb == "%":
+			buf.WriteString("%")
+		case ok:
+			buf.WriteString(p)
+		default:
+			buf.WriteString(`(.+?)`)
+		}
+	}
+	buf.WriteString(regexp.QuoteMeta(format[last:]))
+	buf.WriteString(`$`)
+	return regexp.MustCompile(buf.String())
+}
+
+// indexFormatVerbs replaces the translated format verbs with %[n]s,
+// so already formatted values can be substituted in any order.
+// Index 1 is reserved for the location prefix.
+func indexFormatVerbs(format string) string {
+	next := 1
+	return formatVerbRE.ReplaceAllStringFunc(format, func(verb string) string {
+		m := formatVerbRE.FindStringSubmatch(verb)
+		if m[2] == "%" {
+			return "%%"
+		}
+		if m[1] != "" {
+			next, _ = strconv.Atoi(m[1])
+		}
+		s := fmt.Sprintf("%%[%d]s", next+1)
+		next++
+		return s
+	})
+}
+
+// Text returns the translated text, like a flag description.
+func (c *messageCatalog) Text(s string) string {
+	if c == nil {
+		return s
+	}
+	if t, ok := c.texts[s]; ok {
+		return t
+	}
+	return s
+}
+
+// Warning translates a formatted warning text.
+func (c *messageCatalog) Warning(text string) string {
+	if c == nil {
+		return text
+	}
+	if t, ok := c.texts[text]; ok {
+		return t
+	}
+	for _, tr := range c.translations {
+		m := tr.re.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+		args := make([]interface{}, len(m)-1)
+		for i := range args {
+			args[i] = m[i+1]
+		}
+		s := fmt.Sprintf(tr.format, args...)
+		if m[1] != "" {
+			s = m[1] + ": " + s
+		}
+		return s
+	}
+	if i := strings.LastIndex(text, ": "); i != -1 {
+		// Constant messages after a location, like "README.md:3: trailing whitespace".
+		if t, ok := c.texts[text[i+2:]]; ok {
+			return text[:i+2] + t
+		}
+	}
+	return text
+}
+
+// ruMessages are the Russian translations.
+var ruMessages = map[string]string{
+	// CLI.
+	"Usage of %s:\n":                "Использование %s:\n",
+	"%s: %s: fixed: %s":             "%s: %s: исправлено: %s",
+	"%s: score %d":                  "%s: оценка %d",
+	"github user/organization name": "имя пользователя или организации на github",
+	"check only this repository instead of all user/organization repositories":                                                            "проверить только этот репозиторий вместо всех репозиториев пользователя или организации",
+	"check a public -repo without a token; limited to 60 github API calls per hour":                                                       "проверить публичный -repo без токена; не более 60 вызовов github API в час",
+	"check a local directory instead of github repositories; github token is not needed":                                                  "проверить локальную директорию вместо репозиториев github; токен github не нужен",
+	"apply safe fixes, like acronyms capitalization and unwanted files removal; requires -dir":                                            "применить безопасные исправления, например регистр аббревиатур и удаление лишних файлов; требует -dir",
+	"with -fix, print a unified diff of the fixes to stdout instead of changing files":                                                    "вместе с -fix напечатать исправления в stdout в формате unified diff, не изменяя файлы",
+	"check only files changed in base..head commits range; requires -repo":                                                                "проверить только файлы, изменённые в диапазоне коммитов base..head; требует -repo",
+	"check only files changed in the specified pull request; requires -repo":                                                              "проверить только файлы, изменённые в указанном pull request; требует -repo",
+	"verbose mode that turns on additional debug output":                                                                                  "подробный режим с дополнительным отладочным выводом",
+	"whether to skip repositories that are forks":                                                                                         "пропускать ли репозитории-форки",
+	"whether to skip repositories with latest push dated more than 1 year ago":                                                            "пропускать ли репозитории, в которые не было push больше года",
+	"skip repositories with latest push dated before YYYY-MM-DD":                                                                          "пропускать репозитории, в которые не было push после даты YYYY-MM-DD",
+	"skip repositories with fewer stars":                                                                                                  "пропускать репозитории с меньшим числом звёзд",
+	"whether to skip vendor folders and their contents":                                                                                   "пропускать ли директории vendor и их содержимое",
+	"whether to check files marked as linguist-generated or linguist-vendored in .gitattributes with all checkers":                        "проверять ли всеми проверками файлы, отмеченные в .gitattributes как linguist-generated или linguist-vendored",
+	"run without external programs and print JSON results to stdout":                                                                      "работать без внешних программ и печатать результаты в JSON в stdout",
+	"exit with non-zero status if any warnings are reported":                                                                              "завершиться с ненулевым статусом, если есть предупреждения",
+	"report network-dependent checkers (like broken link and description) as info that doesn't affect the exit status":                    "сообщать о предупреждениях сетевых проверок (например broken link и description) как об info, не влияющих на статус завершения",
+	"exit with non-zero status if any repository health score is below this value, 0 disables the check":                                  "завершиться с ненулевым статусом, если оценка какого-либо репозитория ниже этого значения, 0 отключает проверку",
+	"exit with non-zero status if more than N warnings are reported (-1 means no limit)":                                                  "завершиться с ненулевым статусом, если предупреждений больше N (-1 — без ограничений)",
+	"whether to print a notice when a new repolint version is available":                                                                  "сообщать ли о выходе новой версии repolint",
+	"don't report warnings below this severity: info, warning or error":                                                                   "не сообщать о предупреждениях ниже этой важности: info, warning или error",
+	"broken link checker timeout for a single link":                                                                                       "таймаут проверки одной ссылки",
+	"how many links are checked concurrently":                                                                                             "сколько ссылок проверяется одновременно",
+	"regexp for web links that should not be checked (empty means check all links)":                                                       "регулярное выражение для веб-ссылок, которые не нужно проверять (пустое — проверять все ссылки)",
+	"how many times to retry file downloads and link checks after a network error or 502, 503 and 504 responses":                          "сколько раз повторять загрузку файлов и проверку ссылок после сетевой ошибки или ответов 502, 503 и 504",
+	"delay before the first retry; it doubles after every attempt":                                                                        "задержка перед первым повтором; она удваивается после каждой попытки",
+	"report README and CHANGELOG files not updated for N years while the code keeps changing; requires -fetch=clone (0 disables)":         "сообщать о файлах README и CHANGELOG, не обновлявшихся N лет, пока код меняется; требует -fetch=clone (0 отключает)",
+	"report deleted sensitive files, like .env or private keys, that remain in git history, and edited migrations; requires -fetch=clone": "сообщать об удалённых конфиденциальных файлах, например .env или приватных ключах, оставшихся в истории git, и об изменённых миграциях; требует -fetch=clone",
+	"YAML configuration file": "файл конфигурации YAML",
+	"comma-separated list of path patterns to skip, like 'vendor/**,third_party/**'": "список шаблонов пропускаемых путей через запятую, например 'vendor/**,third_party/**'",
+	"how many repositories to skip": "сколько репозиториев пропустить",
+	"how to fetch repository files: api (download files one by one), clone (shallow git clone) or tarball (download a repository archive)":   "как получать файлы репозитория: api (загружать файлы по одному), clone (неглубокий git clone) или tarball (загрузить архив репозитория)",
+	"overall run time limit; in-flight requests are canceled and partial results are reported (0 means no limit)":                            "общее ограничение времени работы; незавершённые запросы отменяются и выводятся частичные результаты (0 — без ограничений)",
+	"max time a single checker can spend on a repository (0 means no limit)":                                                                 "максимальное время работы одной проверки на репозитории (0 — без ограничений)",
+	"how many repository files are fetched concurrently":                                                                                     "сколько файлов репозитория загружается одновременно",
+	"max number of concurrent requests to a single host":                                                                                     "максимальное число одновременных запросов к одному хосту",
+	"max number of github API calls per run; repos that don't fit are skipped (0 means unlimited)":                                           "максимальное число вызовов API github за запуск; не уместившиеся репозитории пропускаются (0 — без ограничений)",
+	"github web URL, used to clone repositories":                                                                                             "веб-адрес github, используется для клонирования репозиториев",
+	"github API base URL override, like https://ghe.example.com/api/v3/; can point to a caching proxy":                                       "базовый URL API github, например https://ghe.example.com/api/v3/; может указывать на кэширующий прокси",
+	"raw file download URL template, like https://ghe.example.com/raw/{owner}/{repo}/{ref}/{path}; if set, files are not downloaded via API": "шаблон URL для загрузки файлов, например https://ghe.example.com/raw/{owner}/{repo}/{ref}/{path}; если задан, файлы не загружаются через API",
+	`extra "Name: value" header sent with every request; can be repeated`:                                                                    `дополнительный заголовок "Name: value", отправляемый с каждым запросом; можно повторять`,
+	"whether to send github token to the -github-api-url and -github-raw-url hosts":                                                          "отправлять ли токен github на хосты -github-api-url и -github-raw-url",
+	`write results as JSON to the specified file ("-" for stdout)`:                                                                           `записать результаты в JSON в указанный файл ("-" для stdout)`,
+	"write results as HTML to the specified file":                                                                                            "записать результаты в HTML в указанный файл",
+	"write a static HTML dashboard with per-repository pages into the specified directory":                                                   "записать статическую HTML-панель со страницами репозиториев в указанную директорию",
+	`write detected licenses and dependency manifests as CycloneDX JSON to the specified file ("-" for stdout)`:                              `записать найденные лицензии и манифесты зависимостей в CycloneDX JSON в указанный файл ("-" для stdout)`,
+	"upload JSON and HTML results to s3://bucket/prefix or gs://bucket/prefix":                                                               "выгрузить результаты в JSON и HTML в s3://bucket/prefix или gs://bucket/prefix",
+	"Ed25519 private key PEM file; JSON, HTML and SBOM reports get detached .sig signatures":                                                 "PEM-файл приватного ключа Ed25519; отчёты JSON, HTML и SBOM получают отдельные подписи .sig",
+	"baseline file with known warnings that should not be reported":                                                                          "файл baseline с известными предупреждениями, о которых не нужно сообщать",
+	"write all reported warnings into the specified baseline file":                                                                           "записать все найденные предупреждения в указанный файл baseline",
+	"file to cache web link check results (default is repolint/links.json in the user cache dir)":                                            "файл кэша результатов проверки веб-ссылок (по умолчанию repolint/links.json в директории кэша пользователя)",
+	"how long cached link check results are valid":                                                                                           "сколько действительны закэшированные результаты проверки ссылок",
+	"don't read or write any caches, including -result-cache and -link-cache":                                                                "не читать и не записывать кэши, включая -result-cache и -link-cache",
+	"file to cache per-file checker results, so unchanged files are not checked again":                                                       "файл кэша результатов проверок по файлам, чтобы неизменённые файлы не проверялись повторно",
+	"SQLite database with accepted false positives shared across repositories":                                                               "база данных SQLite с принятыми ложными срабатываниями, общая для репозиториев",
+	"record all reported warnings as accepted false positives into -suppress-db":                                                             "записать все найденные предупреждения в -suppress-db как принятые ложные срабатывания",
+	"language of warnings and help: en or ru (default is detected from LC_ALL, LC_MESSAGES and LANG)":                                        "язык предупреждений и справки: en или ru (по умолчанию определяется по LC_ALL, LC_MESSAGES и LANG)",
+
+	// Hygiene.
+	"file is empty":                                        "файл пуст",
+	"file only contains placeholder text %q":               "файл содержит только текст-заглушку %q",
+	"file only contains headings and comments, no content": "файл содержит только заголовки и комментарии, без содержимого",
+	"remove %s file: %s":                                   "удалите файл %s: %s",
+	"differs from %s only by case, they conflict on case-insensitive file systems, like macOS and Windows": "отличается от %s только регистром, они конфликтуют в файловых системах без учёта регистра, как в macOS и Windows",
+	"%s is a reserved device name on Windows, even with an extension":                                      "%s — зарезервированное имя устройства в Windows, даже с расширением",
+	"%q is not allowed in Windows file names":                                                              "%q недопустим в именах файлов Windows",
+	"control character %q is not allowed in Windows file names":                                            "управляющий символ %q недопустим в именах файлов Windows",
+	"Windows strips trailing spaces and dots from file names":                                              "Windows удаляет пробелы и точки в конце имён файлов",
+	"path is %d characters long, longer than %d, it may not fit into the Windows path length limit":        "длина пути %d символов, больше %d, он может не уместиться в ограничение длины пути Windows",
+	"name is %d bytes long, most file systems allow at most %d":                                            "длина имени %d байт, большинство файловых систем допускают не больше %d",
+	"symlink to %s points outside of the repository":                                                       "символическая ссылка на %s указывает за пределы репозитория",
+	"symlink to %s points to an absolute path outside of the repository":                                   "символическая ссылка на %s указывает на абсолютный путь за пределами репозитория",
+	"symlink to %s points to a path that doesn't exist":                                                    "символическая ссылка на %s указывает на несуществующий путь",
+	"symlink to %s points to a path that doesn't exist, maybe it was moved to %s":                          "символическая ссылка на %s указывает на несуществующий путь, возможно, он перемещён в %s",
+	"symlink to %s can't be resolved, it's a loop or a chain of more than %d links":                        "символическую ссылку на %s нельзя разрешить, это цикл или цепочка длиннее %d ссылок",
+	"committed archive, publish it as a release asset instead":                                             "закоммичен архив, опубликуйте его как файл релиза",
+	"committed binary, publish it as a release asset instead":                                              "закоммичен исполняемый файл, опубликуйте его как файл релиза",
+	"file size is %s, exceeds %s, consider moving it to Git LFS":                                           "размер файла %s, больше %s, стоит перенести его в Git LFS",
+	"committed sensitive file, it usually contains secrets":                                                "закоммичен конфиденциальный файл, обычно он содержит секреты",
+	"trailing whitespace":             "пробелы в конце строки",
+	"line contains only whitespace":   "строка состоит только из пробелов",
+	"no newline at end of file":       "нет перевода строки в конце файла",
+	"UTF-8 byte order mark":           "метка порядка байтов UTF-8",
+	"UTF-16 encoded file, use UTF-8":  "файл в кодировке UTF-16, используйте UTF-8",
+	"invalid UTF-8 at byte offset %d": "некорректный UTF-8 по смещению %d байт",
+	"missing community file":          "нет файла сообщества",
+
+	// Docs.
+	"README is empty":                          "README пуст",
+	"README consists of a single line":         "README состоит из одной строки",
+	"README only contains the repository name": "README содержит только имя репозитория",
+	"README is only %d words long, describe what the project does and how to use it": "README содержит всего %d слов, опишите, что делает проект и как им пользоваться",
+	"no title heading":                       "нет заголовка",
+	"no %q section":                          "нет раздела %q",
+	"%q is a misspelling of %q":              "%q — опечатка, правильно %q",
+	"%s could be a misspelling of %s":        "%s может быть опечаткой, правильно %s",
+	"replace %s with %s":                     "замените %s на %s",
+	"no such file":                           "нет такого файла",
+	"no such file, maybe it was moved to %s": "нет такого файла, возможно, он перемещён в %s",
+	"no such anchor":                         "нет такого якоря",
+	"reachable over HTTPS, use %s":           "доступна по HTTPS, используйте %s",
+	"link %s points to the master branch, but the default branch is %s": "ссылка %s указывает на ветку master, но ветка по умолчанию — %s",
+	"not updated since %s (%d years), while the code got %d commits":    "не обновлялся с %s (%d лет), хотя в код сделано коммитов: %d",
+
+	// Legal.
+	"license contains sloppy copyright":                             "лицензия содержит незаполненный копирайт",
+	"unrecognized license text, compliance tools can't classify it": "нераспознанный текст лицензии, инструменты проверки лицензий не смогут его классифицировать",
+	"%s license text is truncated, it should end with %q":           "текст лицензии %s обрезан, он должен заканчиваться на %q",
+	"%s license text is modified, %s":                               "текст лицензии %s изменён, %s",
+	"copyright year %d is %d years behind the last commit in %d":    "год копирайта %d отстаёт на %d лет от последнего коммита в %d",
+
+	// Go and CI.
+	"go.sum is missing, while the module has dependencies":         "нет go.sum, хотя у модуля есть зависимости",
+	"module path %s doesn't match the repository URL, expected %s": "путь модуля %s не соответствует URL репозитория, ожидается %s",
+	"go directive 1.%d is %d releases behind the latest Go 1.%d":   "директива go 1.%d отстаёт на %d релизов от последней Go 1.%d",
+	"%s@v%d is deprecated, use %s@v%d or later":                    "%s@v%d устарел, используйте %s@v%d или новее",
+	"::%s workflow command is deprecated, use %s":                  "команда ::%s устарела, используйте %s",
+}
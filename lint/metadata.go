@@ -0,0 +1,378 @@
+package lint
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/github"
+)
+
+// repoMetadata is a repository information that is not stored in files.
+type repoMetadata struct {
+	Description string
+	Topics      []string
+
+	// DefaultBranch is a repository default branch name, like "main".
+	DefaultBranch string
+
+	// Size is a repository size in kilobytes.
+	Size int
+
+	// HasIssues reports whether the issue tracker is enabled.
+	HasIssues bool
+
+	Private bool
+
+	// License is an SPDX ID of the license detected by github,
+	// "NOASSERTION" for unknown licenses and empty if there is no license.
+	License string
+
+	// Protected reports whether the default branch is protected,
+	// nil if no checker needs it.
+	Protected *bool
+
+	// Parent is an upstream repository full name, like "owner/repo".
+	// Empty for repositories that are not forks.
+	Parent string
+
+	// AheadBy is a number of fork commits that are not in the upstream.
+	AheadBy int
+
+	// ChangedFiles are the files changed in the fork
+	// comparing to the upstream.
+	ChangedFiles map[string]bool
+}
+
+// metadataChecker is implemented by the checkers that need
+// repository metadata from the github API.
+type metadataChecker interface {
+	setMetadata(m *repoMetadata)
+}
+
+// repoURLChecker is implemented by the checkers that need
+// the checked repository web URL, like "https://github.com/owner/repo".
+type repoURLChecker interface {
+	setRepoURL(u string)
+}
+
+// setRepoURL passes repo web URL to the checkers that need it.
+// The URL is empty in local mode.
+func (l *Runner) setRepoURL(repo string) {
+	u := ""
+	if l.dir == "" {
+		u = strings.TrimSuffix(l.webURL, "/") + "/" + l.user + "/" + repo
+	}
+	for _, c := range l.checkers {
+		if c, ok := c.(repoURLChecker); ok {
+			c.setRepoURL(u)
+		}
+	}
+}
+
+// commitDateChecker is implemented by the checkers that need
+// the date of the last checked commit.
+type commitDateChecker interface {
+	setCommitDate(t time.Time)
+}
+
+// setCommitDate passes the last commit date to the checkers that need it.
+// The date is only requested if any of them accepted files.
+//
+// In clone and local modes, it comes from the checkout git history,
+// otherwise it's requested from the github API.
+func (l *Runner) setCommitDate(repo string, files []*File) {
+	var checkers []commitDateChecker
+	for _, c := range l.checkers {
+		if dc, ok := c.(commitDateChecker); ok && len(c.AcceptedFiles()) != 0 {
+			checkers = append(checkers, dc)
+		}
+	}
+	if len(checkers) == 0 {
+		return
+	}
+
+	var date time.Time
+	rootDir := ""
+	for _, f := range files {
+		if f.rootDir != "" {
+			rootDir = f.rootDir
+			break
+		}
+	}
+	if rootDir != "" {
+		out, err := gitOutput(l.ctx, rootDir, "log", "-1", "--format=%ct", "HEAD")
+		if err != nil {
+			// Local directories can be outside of git repositories.
+			return
+		}
+		sec, err := strconv.ParseInt(out, 10, 64)
+		if err != nil {
+			return
+		}
+		date = time.Unix(sec, 0)
+	} else {
+		opts := &github.CommitsListOptions{SHA: l.ref, ListOptions: github.ListOptions{PerPage: 1}}
+		commits, _, err := l.client.Repositories.ListCommits(l.ctx, l.user, repo, opts)
+		l.countRequest()
+		if err != nil || len(commits) == 0 {
+			log.Printf("	error: get %s last commit: %v", repo, err)
+			return
+		}
+		date = commits[0].GetCommit().GetCommitter().GetDate()
+	}
+	for _, c := range checkers {
+		c.setCommitDate(date)
+	}
+}
+
+// tagsChecker is implemented by the checkers that need the repository git tags.
+type tagsChecker interface {
+	setTags(tags []string)
+}
+
+// maxTagPages limits the number of the github API tag list pages.
+// Repositories with more tags are checked without them.
+const maxTagPages = 10
+
+// setTags passes the repository tag names to the checkers that need them.
+// Tags are only requested if any of them accepted files.
+//
+// In local mode, they come from the checkout, otherwise they're requested
+// from the github API, because clones don't have all tags.
+func (l *Runner) setTags(repo string, files []*File) {
+	var checkers []tagsChecker
+	for _, c := range l.checkers {
+		if tc, ok := c.(tagsChecker); ok && len(c.AcceptedFiles()) != 0 {
+			checkers = append(checkers, tc)
+		}
+	}
+	if len(checkers) == 0 {
+		return
+	}
+
+	var tags []string
+	if l.dir != "" {
+		rootDir := ""
+		for _, f := range files {
+			if f.rootDir != "" {
+				rootDir = f.rootDir
+				break
+			}
+		}
+		out, err := gitOutput(l.ctx, rootDir, "tag", "--list")
+		if err != nil {
+			// Local directories can be outside of git repositories.
+			return
+		}
+		tags = strings.Fields(out)
+	} else {
+		opts := &github.ListOptions{PerPage: 100}
+		for page := 0; ; page++ {
+			if page == maxTagPages {
+				if l.verbose {
+					log.Printf("\t\tdebug: %s has more than %d tags, they're not checked", repo, maxTagPages*opts.PerPage)
+				}
+				return
+			}
+			list, resp, err := l.client.Repositories.ListTags(l.ctx, l.user, repo, opts)
+			l.countRequest()
+			if err != nil {
+				log.Printf("\terror: get %s tags: %v", repo, err)
+				return
+			}
+			for _, t := range list {
+				tags = append(tags, t.GetName())
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opts.Page = resp.NextPage
+		}
+	}
+	for _, c := range checkers {
+		c.setTags(tags)
+	}
+}
+
+// issuesChecker is implemented by the checkers that need
+// the state of the issues referenced in the repository files.
+type issuesChecker interface {
+	referencedIssues() []int
+	setClosedIssues(closed map[int]bool)
+}
+
+// maxIssueLookups limits the number of issues looked up per repository.
+const maxIssueLookups = 30
+
+// setIssueStates looks up the referenced issues and passes the closed ones
+// to the checkers. Local mode has no github repository to look them up in.
+func (l *Runner) setIssueStates(repo string) {
+	if l.dir != "" {
+		return
+	}
+	for _, c := range l.checkers {
+		ic, ok := c.(issuesChecker)
+		if !ok || len(c.AcceptedFiles()) == 0 {
+			continue
+		}
+		closed := make(map[int]bool)
+		for i, n := range ic.referencedIssues() {
+			if i == maxIssueLookups || (l.maxAPICalls != 0 && l.requestCount() >= l.maxAPICalls) {
+				if l.verbose {
+					log.Printf("\t\tdebug: %s: only %d referenced issues are looked up", repo, i)
+				}
+				break
+			}
+			issue, _, err := l.client.Issues.Get(l.ctx, l.user, repo, n)
+			l.countRequest()
+			if err != nil {
+				log.Printf("\terror: get %s issue #%d: %v", repo, n, err)
+				continue
+			}
+			if issue.GetState() == "closed" {
+				closed[n] = true
+			}
+		}
+		ic.setClosedIssues(closed)
+	}
+}
+
+// ownersChecker is implemented by the checkers that need to know
+// which of the referenced users and teams, like "@octocat", don't exist.
+type ownersChecker interface {
+	referencedOwners() []string
+	setUnknownOwners(unknown map[string]bool)
+}
+
+// maxOwnerLookups limits the number of owners looked up per repository.
+const maxOwnerLookups = 30
+
+// setOwnerStates looks up the referenced owners and passes the unknown ones
+// to the checkers. Owners are not looked up without a token,
+// it would spend the small anonymous rate limit.
+//
+// Teams are only visible with the read:org token scope,
+// otherwise they're reported as unknown.
+func (l *Runner) setOwnerStates(repo string) {
+	if l.dir != "" || l.tokens == nil {
+		return
+	}
+	for _, c := range l.checkers {
+		oc, ok := c.(ownersChecker)
+		if !ok || len(c.AcceptedFiles()) == 0 {
+			continue
+		}
+		unknown := make(map[string]bool)
+		for i, owner := range oc.referencedOwners() {
+			if i == maxOwnerLookups || (l.maxAPICalls != 0 && l.requestCount() >= l.maxAPICalls) {
+				if l.verbose {
+					log.Printf("\t\tdebug: %s: only %d code owners are looked up", repo, i)
+				}
+				break
+			}
+			var resp *github.Response
+			var err error
+			if m := codeownerTeamRE.FindStringSubmatch(owner); m != nil {
+				if !strings.EqualFold(m[1], l.user) {
+					// Teams of other organizations can't own the code.
+					continue
+				}
+				req, reqErr := l.client.NewRequest("GET", "orgs/"+m[1]+"/teams/"+m[2], nil)
+				if reqErr != nil {
+					log.Printf("\terror: get %s team %s: %v", repo, owner, reqErr)
+					continue
+				}
+				resp, err = l.client.Do(l.ctx, req, nil)
+			} else {
+				_, resp, err = l.client.Users.Get(l.ctx, strings.TrimPrefix(owner, "@"))
+			}
+			l.countRequest()
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				unknown[strings.ToLower(owner)] = true
+				continue
+			}
+			if err != nil {
+				log.Printf("\terror: get %s owner %s: %v", repo, owner, err)
+			}
+		}
+		oc.setUnknownOwners(unknown)
+	}
+}
+
+// fetchMetadata passes repo metadata to the checkers that need it.
+func (l *Runner) fetchMetadata(repo string) {
+	var checkers []metadataChecker
+	for _, c := range l.checkers {
+		if c, ok := c.(metadataChecker); ok {
+			checkers = append(checkers, c)
+		}
+	}
+	if len(checkers) == 0 {
+		return
+	}
+	r, _, err := l.client.Repositories.Get(l.ctx, l.user, repo)
+	l.countRequest()
+	if err != nil {
+		log.Printf("\terror: get %s metadata: %v", repo, err)
+		return
+	}
+	m := &repoMetadata{
+		Description: r.GetDescription(),
+		Topics:      r.Topics,
+		Size:        r.GetSize(),
+		HasIssues:   r.GetHasIssues(),
+		Private:     r.GetPrivate(),
+		License:     r.GetLicense().GetSPDXID(),
+
+		DefaultBranch: r.GetDefaultBranch(),
+	}
+	if r.GetFork() && r.Parent != nil {
+		l.compareWithParent(repo, r, m)
+	}
+	for _, c := range checkers {
+		if _, ok := c.(branchProtectionChecker); ok {
+			l.fetchBranchProtection(repo, m)
+			break
+		}
+	}
+	for _, c := range checkers {
+		c.setMetadata(m)
+	}
+}
+
+// fetchBranchProtection fills the default branch protection status.
+func (l *Runner) fetchBranchProtection(repo string, m *repoMetadata) {
+	b, _, err := l.client.Repositories.GetBranch(l.ctx, l.user, repo, m.DefaultBranch)
+	l.countRequest()
+	if err != nil {
+		log.Printf("\terror: get %s branch %s: %v", repo, m.DefaultBranch, err)
+		return
+	}
+	protected := b.GetProtected()
+	m.Protected = &protected
+}
+
+// compareWithParent fills fork divergence info.
+func (l *Runner) compareWithParent(repo string, r *github.Repository, m *repoMetadata) {
+	parent := r.Parent
+	head := l.ref
+	if head == "" {
+		head = r.GetDefaultBranch()
+	}
+	base := parent.GetOwner().GetLogin() + ":" + parent.GetDefaultBranch()
+	cmp, _, err := l.client.Repositories.CompareCommits(l.ctx, l.user, repo, base, head)
+	l.countRequest()
+	if err != nil {
+		log.Printf("\terror: compare %s with %s: %v", repo, parent.GetFullName(), err)
+		return
+	}
+	m.Parent = parent.GetFullName()
+	m.AheadBy = cmp.GetAheadBy()
+	m.ChangedFiles = make(map[string]bool, len(cmp.Files))
+	for _, f := range cmp.Files {
+		m.ChangedFiles[f.GetFilename()] = true
+	}
+}
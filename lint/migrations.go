@@ -0,0 +1,312 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migrationsConfig configures the migrations checker.
+type migrationsConfig struct {
+	// AllowMissingDown disables missing down migration reports.
+	AllowMissingDown bool `yaml:"allow_missing_down"`
+}
+
+var (
+	// flywayMigrationRE matches Flyway migrations, like "V1_2__init.sql".
+	// Submatch groups are a prefix (V for versioned, U for undo) and a version.
+	flywayMigrationRE = regexp.MustCompile(`^([VU])(\d+(?:[._]\d+)*)__.+$`)
+
+	// numberedMigrationRE matches numbered migrations, like "0001_init.up.sql",
+	// "20230102150405_create_users.rb" or Django "0002_auto.py".
+	numberedMigrationRE = regexp.MustCompile(`^(\d+)[_.-].+$`)
+
+	// timestampVersionRE matches timestamp versions, like "20230102150405".
+	timestampVersionRE = regexp.MustCompile(`^\d{10,}$`)
+)
+
+// migration is a versioned schema migration file.
+type migration struct {
+	file    *File
+	version string
+
+	// name is a file base name without the up/down suffix,
+	// so up and down migrations have the same name.
+	name string
+
+	down bool
+}
+
+// migrationCommits are the history dates of a migration file.
+type migrationCommits struct {
+	// added is a commit time the file was added.
+	added int64
+
+	// modified are commit times the file was modified.
+	modified []int64
+}
+
+// parseMigration returns a migration described by f.
+// Reports false for files that are not versioned migrations.
+func parseMigration(f *File) (migration, bool) {
+	if m := flywayMigrationRE.FindStringSubmatch(f.baseName); m != nil {
+		version := strings.Replace(m[2], "_", ".", -1)
+		name := strings.TrimPrefix(f.baseName, m[1])
+		return migration{file: f, version: version, name: name, down: m[1] == "U"}, true
+	}
+	m := numberedMigrationRE.FindStringSubmatch(f.baseName)
+	if m == nil {
+		return migration{}, false
+	}
+	mig := migration{file: f, version: strings.TrimLeft(m[1], "0"), name: f.baseName}
+	if mig.version == "" {
+		mig.version = "0"
+	}
+	switch {
+	case strings.Contains(f.baseName, ".up."):
+		mig.name = strings.Replace(f.baseName, ".up.", ".", 1)
+	case strings.Contains(f.baseName, ".down."):
+		mig.name = strings.Replace(f.baseName, ".down.", ".", 1)
+		mig.down = true
+	}
+	return mig, true
+}
+
+// compareVersions compares dot-separated numeric versions, like "1.10" and "1.9".
+func compareVersions(a, b string) int {
+	as := strings.Split(a, ".")
+	bs := strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		x, y := "0", "0"
+		if i < len(as) {
+			x = strings.TrimLeft(as[i], "0")
+		}
+		if i < len(bs) {
+			y = strings.TrimLeft(bs[i], "0")
+		}
+		if len(x) != len(y) {
+			if len(x) < len(y) {
+				return -1
+			}
+			return 1
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// isMigrationsDir reports whether dir contains schema migrations,
+// like "migrations", "db/migrate" or "migrations/postgres".
+func isMigrationsDir(dir string) bool {
+	for _, part := range strings.Split(dir, "/") {
+		if part == "migrations" || part == "migrate" {
+			return true
+		}
+	}
+	return false
+}
+
+// migrationsChecker finds duplicate migration versions, directories that mix
+// sequential and timestamp versions, and up migrations without down migrations.
+//
+// With -history, it also finds migrations that were added out of order
+// or edited after later migrations were added: such changes are never
+// applied to the existing databases.
+type migrationsChecker struct {
+	CheckerBase
+
+	allowMissingDown bool
+
+	// history is set by the -history flag.
+	history bool
+
+	// rootDir is a repository checkout directory.
+	rootDir string
+
+	// dirs maps migration directories to their migrations.
+	dirs map[string][]migration
+}
+
+func newMigrationsChecker() *migrationsChecker {
+	return &migrationsChecker{}
+}
+
+func (c *migrationsChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.rootDir = ""
+	c.dirs = make(map[string][]migration)
+}
+
+// Vendored migrations are maintained elsewhere.
+func (c *migrationsChecker) skipGenerated() {}
+
+// Duplicate versions are found using all migration files.
+func (c *migrationsChecker) fullTree() {}
+
+// Results depend on the repository history.
+func (c *migrationsChecker) uncachedResults() {}
+
+func (c *migrationsChecker) PushFile(f *File) {
+	dir := path.Dir(f.origName)
+	if f.dir || !isMigrationsDir(dir) || strings.Contains("/"+dir+"/", "/testdata/") {
+		return
+	}
+	m, ok := parseMigration(f)
+	if !ok {
+		return
+	}
+	if c.history && f.rootDir != "" {
+		c.rootDir = f.rootDir
+	}
+	c.dirs[dir] = append(c.dirs[dir], m)
+	c.AcceptFile(f)
+}
+
+func (c *migrationsChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	dirs := make([]string, 0, len(c.dirs))
+	for dir, migrations := range c.dirs {
+		sort.SliceStable(migrations, func(i, j int) bool {
+			if cmp := compareVersions(migrations[i].version, migrations[j].version); cmp != 0 {
+				return cmp < 0
+			}
+			return migrations[i].file.baseName < migrations[j].file.baseName
+		})
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	var commits map[string]*migrationCommits
+	if c.rootDir != "" {
+		commits = c.migrationCommits(ctx, dirs)
+	}
+	for _, dir := range dirs {
+		warnings = append(warnings, c.checkDir(dir, c.dirs[dir], commits)...)
+	}
+	return warnings
+}
+
+func (c *migrationsChecker) checkDir(dir string, migrations []migration, commits map[string]*migrationCommits) (warnings []string) {
+	timestamps, sequential := 0, 0
+	byVersion := make(map[string]migration)
+	names := make(map[string]bool)
+	for _, m := range migrations {
+		names[m.file.baseName] = true
+		if timestampVersionRE.MatchString(m.version) {
+			timestamps++
+		} else {
+			sequential++
+		}
+		if m.down {
+			// Down migrations share versions with their up migrations.
+			continue
+		}
+		if prev, ok := byVersion[m.version]; ok && prev.name != m.name {
+			w := fmt.Sprintf("%s: duplicate migration version %s, also used by %s",
+				m.file.origName, m.version, prev.file.baseName)
+			warnings = append(warnings, w)
+			continue
+		}
+		byVersion[m.version] = m
+	}
+	if timestamps != 0 && sequential != 0 {
+		w := fmt.Sprintf("%s/: migrations mix sequential and timestamp versions, so they're applied out of order", dir)
+		warnings = append(warnings, w)
+	}
+	if !c.allowMissingDown {
+		for _, m := range migrations {
+			if down := strings.Replace(m.file.baseName, ".up.", ".down.", 1); down != m.file.baseName && !names[down] {
+				w := fmt.Sprintf("%s: missing down migration %s", m.file.origName, down)
+				warnings = append(warnings, w)
+			}
+		}
+	}
+
+	if commits == nil {
+		return warnings
+	}
+	for i, m := range migrations {
+		mc := commits[m.file.origName]
+		if mc == nil || m.down {
+			continue
+		}
+		// later is the latest migration that was added before m.
+		var later *migration
+		for j := len(migrations) - 1; j > i; j-- {
+			lc := commits[migrations[j].file.origName]
+			if lc != nil && !migrations[j].down && lc.added < mc.added && compareVersions(migrations[j].version, m.version) > 0 {
+				later = &migrations[j]
+				break
+			}
+		}
+		if later != nil {
+			w := fmt.Sprintf("%s: added after a later migration %s, it may be skipped by the existing databases",
+				m.file.origName, later.file.baseName)
+			warnings = append(warnings, w)
+			continue
+		}
+		for j := len(migrations) - 1; j > i; j-- {
+			lc := commits[migrations[j].file.origName]
+			if lc == nil || compareVersions(migrations[j].version, m.version) <= 0 || !modifiedAfter(mc, lc.added) {
+				continue
+			}
+			w := fmt.Sprintf("%s: edited after a later migration %s was added, add a new migration instead",
+				m.file.origName, migrations[j].file.baseName)
+			warnings = append(warnings, w)
+			break
+		}
+	}
+	return warnings
+}
+
+func modifiedAfter(mc *migrationCommits, t int64) bool {
+	for _, modified := range mc.modified {
+		if modified > t {
+			return true
+		}
+	}
+	return false
+}
+
+// migrationCommits returns the history dates of the files inside dirs.
+func (c *migrationsChecker) migrationCommits(ctx context.Context, dirs []string) map[string]*migrationCommits {
+	args := append([]string{"log", "--no-renames", "--format=commit %ct", "--name-status", "HEAD", "--"}, dirs...)
+	out, err := gitOutput(ctx, c.rootDir, args...)
+	if err != nil {
+		return nil
+	}
+
+	commits := make(map[string]*migrationCommits)
+	var t int64
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "commit ") {
+			t, _ = strconv.ParseInt(strings.TrimPrefix(line, "commit "), 10, 64)
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 2 {
+			continue
+		}
+		mc := commits[fields[1]]
+		if mc == nil {
+			mc = &migrationCommits{}
+			commits[fields[1]] = mc
+		}
+		switch fields[0] {
+		case "A":
+			// Commits are listed from the newest to the oldest.
+			mc.added = t
+		case "M":
+			mc.modified = append(mc.modified, t)
+		}
+	}
+	return commits
+}
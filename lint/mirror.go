@@ -0,0 +1,98 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// mirrorChecker finds repositories which README says that
+// the project is developed elsewhere, so the repository is a mirror.
+type mirrorChecker struct {
+	CheckerBase
+
+	// host is the scanned host, like github.com.
+	// Links to this host are not canonical homes.
+	host string
+
+	// canonical is a canonical home URL found during the last check.
+	canonical string
+
+	noticeRE *regexp.Regexp
+	urlRE    *regexp.Regexp
+}
+
+func newMirrorChecker() *mirrorChecker {
+	return &mirrorChecker{
+		host: "github.com",
+		noticeRE: regexp.MustCompile(`(?i)\bmirror(?:ed)? (?:of|from)\b|\bis (?:a|an|just a) (?:read-only |official |automatic )?mirror\b|` +
+			`\bdevelopment (?:happens|takes place|is done|has moved|continues)\b|` +
+			`\b(?:project|repository|repo|development) (?:has )?moved to\b|` +
+			`\bcanonical (?:repository|home|location|source)\b|` +
+			`\b(?:send|submit) (?:your )?(?:patches|pull requests|merge requests|contributions) (?:to|at|on)\b`),
+		urlRE: regexp.MustCompile(`https?://[^\s<>()\[\]"'` + "`" + `]+`),
+	}
+}
+
+func (c *mirrorChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.canonical = ""
+}
+
+func (c *mirrorChecker) PushFile(f *File) {
+	// Only the root README describes the project.
+	if strings.HasPrefix(f.origName, "README") && !strings.Contains(f.origName, "/") {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+// Results also set the canonical URL, which is not cached.
+func (c *mirrorChecker) uncachedResults() {}
+
+func (c *mirrorChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	for _, f := range c.files {
+		for i, line := range strings.Split(f.contents, "\n") {
+			if !c.noticeRE.MatchString(line) {
+				continue
+			}
+			home := c.canonicalURL(line)
+			if home == "" {
+				continue
+			}
+			c.canonical = home
+			w := fmt.Sprintf("%s:%d: repository is a mirror, development happens at %s", f.origName, i+1, home)
+			return append(warnings, w)
+		}
+	}
+	return nil
+}
+
+// canonicalURL returns the first line URL that points to another host.
+func (c *mirrorChecker) canonicalURL(line string) string {
+	for _, s := range c.urlRE.FindAllString(line, -1) {
+		s = strings.TrimRight(s, ".,;:!?*_")
+		u, err := url.Parse(s)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+		if host == c.host || strings.HasSuffix(host, "."+c.host) || isBadgeHost(host) {
+			continue
+		}
+		return s
+	}
+	return ""
+}
+
+// isBadgeHost reports whether host serves images, rather than projects.
+func isBadgeHost(host string) bool {
+	switch host {
+	case "img.shields.io", "shields.io", "badge.fury.io", "badgen.net", "travis-ci.org", "travis-ci.com", "codecov.io", "goreportcard.com":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,163 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// npmScriptsConfig enables the npm scripts checker.
+type npmScriptsConfig struct {
+	// Significant are the scripts that must be documented if they exist.
+	// Defaults to defaultSignificantScripts.
+	Significant []string `yaml:"significant"`
+}
+
+// defaultSignificantScripts are the scripts users usually need to know about.
+var defaultSignificantScripts = []string{"build", "dev", "lint", "serve", "start", "test"}
+
+// npmCommandRE matches package manager commands that run scripts,
+// like "npm run build", "npm test" or "yarn build".
+var npmCommandRE = regexp.MustCompile(`\b(npm|yarn|pnpm)\s+(run(?:-script)?\s+)?([\w:-]+(?:\.[\w:-]+)*)`)
+
+// npmLifecycleScripts can be run without "npm run".
+var npmLifecycleScripts = map[string]bool{
+	"start":   true,
+	"stop":    true,
+	"restart": true,
+	"test":    true,
+}
+
+// packageManagerCommands are yarn and pnpm built-in commands,
+// so "yarn add" is not a script run.
+var packageManagerCommands = map[string]bool{
+	"add": true, "audit": true, "autoclean": true, "bin": true, "cache": true,
+	"check": true, "config": true, "create": true, "dedupe": true, "deploy": true,
+	"dlx": true, "env": true, "exec": true, "explain": true, "fetch": true,
+	"global": true, "help": true, "i": true, "import": true, "info": true,
+	"init": true, "install": true, "licenses": true, "link": true, "list": true,
+	"login": true, "logout": true, "ls": true, "node": true, "outdated": true,
+	"owner": true, "pack": true, "patch": true, "plugin": true, "policies": true,
+	"prune": true, "publish": true, "rebuild": true, "remove": true, "rm": true,
+	"root": true, "search": true, "set": true, "setup": true, "store": true,
+	"tag": true, "team": true, "unlink": true, "unplug": true, "up": true,
+	"update": true, "upgrade": true, "version": true, "versions": true, "why": true,
+	"workspace": true, "workspaces": true,
+}
+
+// npmScriptsChecker compares scripts documented in README and CONTRIBUTING
+// with the package.json scripts in the same directory.
+// It's disabled until the npm_scripts config section is specified.
+type npmScriptsChecker struct {
+	CheckerBase
+
+	// significant is nil if the checker is disabled.
+	significant []string
+}
+
+func newNpmScriptsChecker() *npmScriptsChecker {
+	return &npmScriptsChecker{}
+}
+
+func (c *npmScriptsChecker) PushFile(f *File) {
+	if c.significant == nil {
+		return
+	}
+	switch {
+	case f.baseName == "package.json" || isScriptsDocFile(f.baseName):
+		f.require.contents = true
+		c.AcceptFile(f)
+	case f.baseName == "server.js":
+		c.AcceptFile(f)
+	}
+}
+
+// Docs are compared with the package.json and server.js in the same directory,
+// so the results of a single file can't be cached.
+func (c *npmScriptsChecker) fullTree() {}
+
+// isScriptsDocFile reports whether filename can document npm scripts.
+func isScriptsDocFile(filename string) bool {
+	return strings.HasPrefix(filename, "README") || strings.HasPrefix(filename, "CONTRIBUTING")
+}
+
+func (c *npmScriptsChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	docs := make(map[string][]*File)
+	for _, f := range c.files {
+		if isScriptsDocFile(f.baseName) {
+			dir := path.Dir(f.origName)
+			docs[dir] = append(docs[dir], f)
+		}
+	}
+	for _, f := range c.files {
+		if f.baseName != "package.json" {
+			continue
+		}
+		dir := path.Dir(f.origName)
+		if len(docs[dir]) == 0 {
+			continue
+		}
+		warnings = append(warnings, c.checkPackage(f, docs[dir])...)
+	}
+	return warnings
+}
+
+func (c *npmScriptsChecker) checkPackage(pkg *File, docs []*File) (warnings []string) {
+	var manifest struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal([]byte(pkg.contents), &manifest); err != nil {
+		return nil
+	}
+
+	dir := path.Dir(pkg.origName)
+	hasServerJS := false
+	for _, f := range c.files {
+		if f.origName == path.Join(dir, "server.js") {
+			hasServerJS = true
+		}
+	}
+
+	documented := make(map[string]bool)
+	for _, f := range docs {
+		for i, line := range strings.Split(f.contents, "\n") {
+			for _, m := range npmCommandRE.FindAllStringSubmatch(line, -1) {
+				tool, run, name := m[1], m[2] != "", m[3]
+				switch {
+				case run:
+				case tool == "npm" && npmLifecycleScripts[name]:
+				case tool != "npm" && !packageManagerCommands[name]:
+				default:
+					continue
+				}
+				documented[name] = true
+				if _, ok := manifest.Scripts[name]; ok {
+					continue
+				}
+				if name == "start" && hasServerJS {
+					// npm start runs "node server.js" by default.
+					continue
+				}
+				w := fmt.Sprintf("%s:%d: script %q is not defined in %s", f.origName, i+1, name, pkg.origName)
+				warnings = append(warnings, w)
+			}
+		}
+	}
+
+	var undocumented []string
+	for _, name := range c.significant {
+		if _, ok := manifest.Scripts[name]; ok && !documented[name] {
+			undocumented = append(undocumented, name)
+		}
+	}
+	sort.Strings(undocumented)
+	for _, name := range undocumented {
+		w := fmt.Sprintf("%s: script %q is not documented", pkg.origName, name)
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
@@ -0,0 +1,277 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// apiDocsConfig enables the API docs checker.
+type apiDocsConfig struct {
+	// Specs are additional spec file paths, besides openapi.yaml
+	// and swagger.json-like files found by their names.
+	Specs []string `yaml:"specs"`
+}
+
+var (
+	// apiSpecRE matches OpenAPI and Swagger spec file base names.
+	apiSpecRE = regexp.MustCompile(`(?i)^(?:openapi|swagger)\.(?:ya?ml|json)$`)
+
+	// endpointRefRE matches endpoint references in documentation, like "GET /users/{id}".
+	// First submatch group is a method, the second one is a path.
+	endpointRefRE = regexp.MustCompile("\\b(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS)\\s+(/[^\\s`'\"()<>\\[\\]]*)")
+
+	// pathParamRE matches path template parameters, like "{id}".
+	pathParamRE = regexp.MustCompile(`\\\{[^/]*?\\\}`)
+
+	// changelogRE matches changelogs, which mention removed endpoints on purpose.
+	changelogRE = regexp.MustCompile(`(?i)^(?:CHANGELOG|CHANGES|HISTORY|NEWS|RELEASES?)\b`)
+)
+
+// apiHTTPMethods are the path item keys that describe operations.
+var apiHTTPMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+// openAPISpec is a part of the OpenAPI (or Swagger 2.0) spec that is checked.
+type openAPISpec struct {
+	OpenAPI string `yaml:"openapi"`
+	Swagger string `yaml:"swagger"`
+	Info    *struct {
+		Title   string `yaml:"title"`
+		Version string `yaml:"version"`
+	} `yaml:"info"`
+	BasePath string `yaml:"basePath"`
+	Servers  []struct {
+		URL string `yaml:"url"`
+	} `yaml:"servers"`
+	Paths map[string]map[string]interface{} `yaml:"paths"`
+}
+
+// apiEndpoint is a spec path with its operations.
+type apiEndpoint struct {
+	re      *regexp.Regexp
+	methods map[string]bool
+}
+
+// apiDocsChecker validates OpenAPI and Swagger specs and finds documented
+// endpoints, like "GET /users/{id}", that the specs don't define.
+// It's disabled until the api_docs config section is specified.
+type apiDocsChecker struct {
+	CheckerBase
+
+	enabled bool
+
+	// specs is a set of additional spec file paths.
+	specs map[string]bool
+
+	specFiles []*File
+	docFiles  []*File
+}
+
+func newAPIDocsChecker() *apiDocsChecker {
+	return &apiDocsChecker{}
+}
+
+func (c *apiDocsChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.specFiles = nil
+	c.docFiles = nil
+}
+
+// Vendored specs are not maintained in the repository.
+func (c *apiDocsChecker) skipGenerated() {}
+
+// Documentation is checked against all specs.
+func (c *apiDocsChecker) fullTree() {}
+
+func (c *apiDocsChecker) PushFile(f *File) {
+	if !c.enabled || strings.Contains("/"+f.origName, "/testdata/") {
+		return
+	}
+	switch {
+	case apiSpecRE.MatchString(f.baseName) || c.specs[f.origName]:
+		f.require.contents = true
+		c.specFiles = append(c.specFiles, f)
+		c.AcceptFile(f)
+	case strings.EqualFold(path.Ext(f.baseName), ".md") && !changelogRE.MatchString(f.baseName):
+		f.require.contents = true
+		c.docFiles = append(c.docFiles, f)
+		c.AcceptFile(f)
+	}
+}
+
+func (c *apiDocsChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	var endpoints []apiEndpoint
+	var bases []string
+	var specNames []string
+	for _, f := range c.specFiles {
+		var spec openAPISpec
+		if err := yaml.Unmarshal([]byte(f.contents), &spec); err != nil {
+			w := fmt.Sprintf("%s: %v", f.origName, err)
+			warnings = append(warnings, w)
+			continue
+		}
+		for _, problem := range validateAPISpec(&spec) {
+			warnings = append(warnings, f.origName+": "+problem)
+		}
+		specNames = append(specNames, f.origName)
+		endpoints = append(endpoints, spec.endpoints()...)
+		bases = append(bases, spec.basePaths()...)
+	}
+	if len(specNames) == 0 {
+		return warnings
+	}
+
+	for _, f := range c.docFiles {
+		if ctx.Err() != nil {
+			break
+		}
+		for i, line := range strings.Split(f.contents, "\n") {
+			for _, m := range endpointRefRE.FindAllStringSubmatch(line, -1) {
+				method, p := m[1], normalizeEndpointPath(m[2])
+				if p == "" || matchesEndpoint(endpoints, bases, strings.ToLower(method), p) {
+					continue
+				}
+				w := fmt.Sprintf("%s:%d: endpoint %s %s is not defined in %s",
+					f.origName, i+1, method, p, strings.Join(specNames, ", "))
+				warnings = append(warnings, w)
+			}
+		}
+	}
+	return warnings
+}
+
+// validateAPISpec returns the spec problems, like missing required fields.
+// Only the basic structure is validated, not the whole spec schema.
+func validateAPISpec(spec *openAPISpec) (problems []string) {
+	// OpenAPI 3.1 made paths and responses optional.
+	strict := true
+	switch {
+	case spec.OpenAPI != "":
+		if !strings.HasPrefix(spec.OpenAPI, "3.") {
+			return []string{fmt.Sprintf("unsupported openapi version %q", spec.OpenAPI)}
+		}
+		strict = !strings.HasPrefix(spec.OpenAPI, "3.1")
+	case spec.Swagger != "":
+		if spec.Swagger != "2.0" {
+			return []string{fmt.Sprintf("unsupported swagger version %q", spec.Swagger)}
+		}
+	default:
+		return []string{`missing "openapi" or "swagger" version field`}
+	}
+
+	if spec.Info == nil || spec.Info.Title == "" {
+		problems = append(problems, `missing "info.title"`)
+	}
+	if spec.Info == nil || spec.Info.Version == "" {
+		problems = append(problems, `missing "info.version"`)
+	}
+	if spec.Paths == nil && strict {
+		problems = append(problems, `missing "paths"`)
+	}
+
+	paths := make([]string, 0, len(spec.Paths))
+	for p := range spec.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	operationIDs := make(map[string]int)
+	for _, p := range paths {
+		if !strings.HasPrefix(p, "/") {
+			problems = append(problems, fmt.Sprintf("path %q should start with \"/\"", p))
+		}
+		var methods []string
+		for method := range spec.Paths[p] {
+			if apiHTTPMethods[method] {
+				methods = append(methods, method)
+			}
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			op, ok := spec.Paths[p][method].(map[interface{}]interface{})
+			if !ok {
+				continue
+			}
+			if _, ok := op["responses"]; !ok && strict {
+				problems = append(problems, fmt.Sprintf("%s %s: missing \"responses\"", strings.ToUpper(method), p))
+			}
+			if id, ok := op["operationId"].(string); ok {
+				operationIDs[id]++
+				if operationIDs[id] == 2 {
+					problems = append(problems, fmt.Sprintf("operationId %q is used by several operations", id))
+				}
+			}
+		}
+	}
+	return problems
+}
+
+func (spec *openAPISpec) endpoints() []apiEndpoint {
+	endpoints := make([]apiEndpoint, 0, len(spec.Paths))
+	for p, item := range spec.Paths {
+		pattern := pathParamRE.ReplaceAllString(regexp.QuoteMeta(strings.TrimSuffix(p, "/")), `[^/]+`)
+		e := apiEndpoint{
+			re:      regexp.MustCompile(`^` + pattern + `$`),
+			methods: make(map[string]bool),
+		}
+		for method := range item {
+			if apiHTTPMethods[method] {
+				e.methods[method] = true
+			}
+		}
+		endpoints = append(endpoints, e)
+	}
+	return endpoints
+}
+
+// basePaths returns the path prefixes of the API server URLs, like "/v1".
+func (spec *openAPISpec) basePaths() []string {
+	var bases []string
+	if p := strings.TrimSuffix(spec.BasePath, "/"); p != "" {
+		bases = append(bases, p)
+	}
+	for _, s := range spec.Servers {
+		u, err := url.Parse(s.URL)
+		if err != nil {
+			continue
+		}
+		if p := strings.TrimSuffix(u.Path, "/"); p != "" {
+			bases = append(bases, p)
+		}
+	}
+	return bases
+}
+
+// normalizeEndpointPath strips the query, fragment and trailing
+// punctuation from a documented endpoint path.
+func normalizeEndpointPath(p string) string {
+	if i := strings.IndexAny(p, "?#"); i != -1 {
+		p = p[:i]
+	}
+	return strings.TrimRight(p, ".,;:!/*")
+}
+
+func matchesEndpoint(endpoints []apiEndpoint, bases []string, method, p string) bool {
+	candidates := []string{p}
+	for _, base := range bases {
+		if strings.HasPrefix(p, base+"/") {
+			candidates = append(candidates, strings.TrimPrefix(p, base))
+		}
+	}
+	for _, e := range endpoints {
+		for _, candidate := range candidates {
+			if e.re.MatchString(candidate) && e.methods[method] {
+				return true
+			}
+		}
+	}
+	return false
+}
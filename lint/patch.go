@@ -0,0 +1,159 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// patchContext is a number of unchanged lines around every diff hunk.
+const patchContext = 3
+
+// diffOp is a single line (or word) of a diff.
+type diffOp struct {
+	// kind is ' ' for unchanged lines, '-' for removed and '+' for added ones.
+	kind byte
+	line string
+}
+
+// unifiedDiff returns a unified diff between old and new file contents.
+// oldName and newName are file header names, like "a/README.md" or "/dev/null".
+// Empty result means the contents are equal.
+func unifiedDiff(oldName, newName, old, new string) string {
+	ops := diffLines(splitLines(old), splitLines(new))
+
+	// oldLines[k] and newLines[k] are line counts before ops[k].
+	oldLines := make([]int, len(ops)+1)
+	newLines := make([]int, len(ops)+1)
+	for k, op := range ops {
+		oldLines[k+1], newLines[k+1] = oldLines[k], newLines[k]
+		if op.kind != '+' {
+			oldLines[k+1]++
+		}
+		if op.kind != '-' {
+			newLines[k+1]++
+		}
+	}
+
+	var buf strings.Builder
+	for i := 0; i < len(ops); i++ {
+		if ops[i].kind == ' ' {
+			continue
+		}
+		// Merge changes separated by less than 2*patchContext unchanged lines.
+		last := i
+		for j := i + 1; j < len(ops); j++ {
+			if ops[j].kind == ' ' {
+				continue
+			}
+			if j-last-1 > 2*patchContext {
+				break
+			}
+			last = j
+		}
+		start := i - patchContext
+		if start < 0 {
+			start = 0
+		}
+		end := last + patchContext + 1
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		if buf.Len() == 0 {
+			fmt.Fprintf(&buf, "--- %s\n+++ %s\n", oldName, newName)
+		}
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n",
+			hunkRange(oldLines[start], oldLines[end]-oldLines[start]),
+			hunkRange(newLines[start], newLines[end]-newLines[start]))
+		for _, op := range ops[start:end] {
+			buf.WriteByte(op.kind)
+			buf.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				buf.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end - 1
+	}
+	return buf.String()
+}
+
+// hunkRange formats a hunk header range that starts after the first n lines.
+func hunkRange(n, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", n)
+	}
+	return fmt.Sprintf("%d,%d", n+1, count)
+}
+
+// splitLines splits s into lines, keeping the line terminators.
+func splitLines(s string) []string {
+	var lines []string
+	for s != "" {
+		i := strings.IndexByte(s, '\n') + 1
+		if i == 0 {
+			i = len(s)
+		}
+		lines = append(lines, s[:i])
+		s = s[i:]
+	}
+	return lines
+}
+
+// diffLines returns a minimal list of operations that turn a into b.
+// Besides file lines, it's used to diff license words.
+func diffLines(a, b []string) []diffOp {
+	var ops []diffOp
+
+	// Fixes are local, so trimming the common prefix and suffix
+	// makes the quadratic part small.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{kind: ' ', line: line})
+	}
+
+	x, y := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	// lcs[i][j] is the longest common subsequence length of x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			switch {
+			case x[i] == y[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			ops = append(ops, diffOp{kind: ' ', line: x[i]})
+			i++
+			j++
+		case j == len(y) || (i < len(x) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{kind: '-', line: x[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', line: y[j]})
+			j++
+		}
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{kind: ' ', line: line})
+	}
+	return ops
+}
@@ -0,0 +1,263 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// pinningConfig enables the dependency pinning checker.
+type pinningConfig struct {
+	// Policy is a pinning strictness: loose (default) or strict.
+	//
+	// Loose policy only reports floating versions that can change with any release,
+	// like "*" in package.json or pip install without a version.
+	// Strict policy requires exact versions everywhere, so version ranges
+	// and go.mod pseudo-versions of indirect dependencies are reported too.
+	Policy string `yaml:"policy"`
+}
+
+var (
+	npmExactRE     = regexp.MustCompile(`^=?v?\d+\.\d+\.\d+(?:[-+][0-9A-Za-z.+-]*)?$`)
+	npmShorthandRE = regexp.MustCompile(`^[\w.-]+/[\w.-]+(?:#.*)?$`)
+	gitCommitRefRE = regexp.MustCompile(`#[0-9a-f]{7,40}$`)
+	pipInstallRE   = regexp.MustCompile(`\bpip[0-9.]*\s+install\b`)
+	shellSepRE     = regexp.MustCompile(`&&|\|\||[;|]`)
+	goPseudoRE     = regexp.MustCompile(`-(?:\w+\.)*\d{14}-[0-9a-f]{12}(?:\+incompatible)?$`)
+)
+
+// pipArgOptions are pip install options that take a separate argument.
+var pipArgOptions = map[string]bool{
+	"-r": true, "--requirement": true,
+	"-c": true, "--constraint": true,
+	"-e": true, "--editable": true,
+	"-i": true, "--index-url": true, "--extra-index-url": true,
+	"-f": true, "--find-links": true,
+	"-t": true, "--target": true, "--prefix": true, "--root": true, "--src": true,
+	"--trusted-host": true, "--platform": true, "--python-version": true,
+	"--implementation": true, "--abi": true, "--cache-dir": true, "--log": true,
+	"--progress-bar": true,
+}
+
+// pinningChecker finds dependencies which versions are not pinned:
+// package.json versions, pip install commands in Dockerfiles and go.mod requirements.
+// It's disabled until the pinning config section is specified.
+type pinningChecker struct {
+	CheckerBase
+
+	// policy is empty if the checker is disabled.
+	policy string
+}
+
+func newPinningChecker() *pinningChecker {
+	return &pinningChecker{}
+}
+
+// Results depend on the pinning policy config option.
+func (c *pinningChecker) uncachedResults() {}
+
+func (c *pinningChecker) PushFile(f *File) {
+	if c.policy == "" {
+		return
+	}
+	if f.baseName == "package.json" || f.baseName == "go.mod" || isDockerfile(f.baseName) {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+// isDockerfile reports whether filename is a Dockerfile, like Dockerfile.dev or app.Dockerfile.
+func isDockerfile(filename string) bool {
+	return filename == "Dockerfile" ||
+		strings.HasPrefix(filename, "Dockerfile.") ||
+		strings.HasSuffix(filename, ".Dockerfile") ||
+		strings.HasSuffix(filename, ".dockerfile")
+}
+
+func (c *pinningChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	strict := c.policy == "strict"
+	for _, f := range c.files {
+		switch {
+		case f.baseName == "package.json":
+			warnings = append(warnings, checkNpmPinning(f, strict)...)
+		case f.baseName == "go.mod":
+			warnings = append(warnings, checkGoPinning(f, strict)...)
+		default:
+			warnings = append(warnings, checkPipPinning(f, strict)...)
+		}
+	}
+	return warnings
+}
+
+// lineAt returns a 1-based line number of the offset inside s.
+func lineAt(s string, offset int) int {
+	return strings.Count(s[:offset], "\n") + 1
+}
+
+func checkNpmPinning(f *File, strict bool) (warnings []string) {
+	var manifest struct {
+		Dependencies         map[string]string `json:"dependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+	}
+	if err := json.Unmarshal([]byte(f.contents), &manifest); err != nil {
+		return nil
+	}
+	for _, deps := range []map[string]string{manifest.Dependencies, manifest.DevDependencies, manifest.OptionalDependencies} {
+		names := make([]string, 0, len(deps))
+		for name := range deps {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			version := deps[name]
+			if !npmUnpinned(version, strict) {
+				continue
+			}
+			line := 1
+			re := regexp.MustCompile(regexp.QuoteMeta(`"`+name+`"`) + `\s*:\s*` + regexp.QuoteMeta(`"`+version+`"`))
+			if loc := re.FindStringIndex(f.contents); loc != nil {
+				line = lineAt(f.contents, loc[0])
+			}
+			w := fmt.Sprintf("%s:%d: %s version %q is not pinned", f.origName, line, name, version)
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// npmUnpinned reports whether package.json dependency version can float.
+func npmUnpinned(version string, strict bool) bool {
+	v := strings.TrimSpace(version)
+	if strings.HasPrefix(v, "npm:") {
+		// Aliases, like "npm:lodash@^4.0.0".
+		v = v[strings.LastIndex(v, "@")+1:]
+	}
+	switch {
+	case v == "" || v == "*" || v == "x" || v == "X" || v == "latest" || v == "next":
+		return true
+	case strings.HasPrefix(v, "file:") || strings.HasPrefix(v, "link:") || strings.HasPrefix(v, "workspace:"):
+		// Local packages.
+		return false
+	case strings.HasPrefix(v, "http://") || strings.HasPrefix(v, "https://"):
+		// Tarball URLs.
+		return false
+	case strings.HasPrefix(v, "git") || strings.HasPrefix(v, "bitbucket:") || npmShorthandRE.MatchString(v):
+		if strict {
+			return !gitCommitRefRE.MatchString(v)
+		}
+		return !strings.Contains(v, "#")
+	}
+	if strict {
+		return !npmExactRE.MatchString(v)
+	}
+	// Open-ended ranges, like ">=1.0.0".
+	return strings.HasPrefix(v, ">") && !strings.Contains(v, "<")
+}
+
+func checkPipPinning(f *File, strict bool) (warnings []string) {
+	lines := strings.Split(f.contents, "\n")
+	for i := 0; i < len(lines); i++ {
+		// Join continuation lines, the warning refers to the first one.
+		start := i
+		cmd := strings.TrimSpace(lines[i])
+		for strings.HasSuffix(cmd, `\`) && i+1 < len(lines) {
+			i++
+			cmd = strings.TrimSuffix(cmd, `\`) + " " + strings.TrimSpace(lines[i])
+		}
+		if !strings.HasPrefix(strings.ToUpper(cmd), "RUN ") {
+			continue
+		}
+		for _, pkg := range pipInstallPackages(cmd) {
+			if pipUnpinned(pkg, strict) {
+				w := fmt.Sprintf("%s:%d: pip package %q is not pinned", f.origName, start+1, pkg)
+				warnings = append(warnings, w)
+			}
+		}
+	}
+	return warnings
+}
+
+// pipInstallPackages returns package arguments of all pip install commands in a shell command line.
+func pipInstallPackages(cmd string) []string {
+	var packages []string
+	for _, part := range shellSepRE.Split(cmd, -1) {
+		loc := pipInstallRE.FindStringIndex(part)
+		if loc == nil {
+			continue
+		}
+		args := strings.Fields(part[loc[1]:])
+		for i := 0; i < len(args); i++ {
+			arg := strings.Trim(args[i], `"'`)
+			if strings.HasPrefix(arg, "-") {
+				if pipArgOptions[arg] {
+					i++
+				}
+				continue
+			}
+			packages = append(packages, arg)
+		}
+	}
+	return packages
+}
+
+// pipUnpinned reports whether a pip install argument can install different versions.
+func pipUnpinned(pkg string, strict bool) bool {
+	switch {
+	case strings.ContainsAny(pkg, "/$@") || strings.HasPrefix(pkg, "."):
+		// Paths, URLs, variables and direct references.
+		return false
+	case strings.HasSuffix(pkg, ".whl") || strings.HasSuffix(pkg, ".tar.gz") || strings.HasSuffix(pkg, ".zip"):
+		return false
+	}
+	if strict {
+		return !strings.Contains(pkg, "==")
+	}
+	switch pkg {
+	case "pip", "setuptools", "wheel":
+		// Usually upgraded before installing the pinned packages.
+		return false
+	}
+	return !strings.ContainsAny(pkg, "=<>~!")
+}
+
+func checkGoPinning(f *File, strict bool) (warnings []string) {
+	inRequire := false
+	for i, line := range strings.Split(f.contents, "\n") {
+		fields := strings.Fields(line)
+		indirect := strings.Contains(line, "// indirect")
+		if j := strings.Index(line, "//"); j != -1 {
+			fields = strings.Fields(line[:j])
+		}
+		switch {
+		case len(fields) == 0:
+			continue
+		case inRequire && fields[0] == ")":
+			inRequire = false
+			continue
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inRequire = true
+			continue
+		case fields[0] == "require":
+			fields = fields[1:]
+		case !inRequire:
+			continue
+		}
+		if len(fields) != 2 {
+			continue
+		}
+		path, version := fields[0], fields[1]
+		switch {
+		case !strings.HasPrefix(version, "v"):
+			w := fmt.Sprintf("%s:%d: %s version %q is not pinned", f.origName, i+1, path, version)
+			warnings = append(warnings, w)
+		case goPseudoRE.MatchString(version) && (strict || !indirect):
+			w := fmt.Sprintf("%s:%d: %s %s is a pseudo-version of an untagged commit, like @master", f.origName, i+1, path, version)
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
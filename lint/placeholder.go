@@ -0,0 +1,128 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// placeholderMaxSize limits the size of the files checked for the
+// placeholder text, larger files are never placeholders.
+const placeholderMaxSize = 1024
+
+// emptyMarkerFiles are the files that are empty on purpose.
+var emptyMarkerFiles = map[string]bool{
+	".gitkeep": true, ".keep": true, ".hgkeep": true,
+	".nojekyll": true,
+	"py.typed":  true, "__init__.py": true, "__init__.pyi": true,
+	// An empty .npmignore makes npm publish files ignored by .gitignore.
+	".npmignore": true,
+}
+
+var (
+	// placeholderDocRE matches the documents that are often added as stubs.
+	placeholderDocRE = regexp.MustCompile(`(?i)^(?:README|CONTRIBUTING|CHANGELOG|CHANGES|HISTORY|SECURITY|CODE[-_]OF[-_]CONDUCT|SUPPORT)(?:\.(?:md|markdown|rst|txt|adoc))?$`)
+
+	// placeholderLineRE matches a whole line of placeholder text,
+	// markup and punctuation stripped.
+	placeholderLineRE = regexp.MustCompile(`(?i)^(?:todo|tbd|tba|fixme|wip|work in progress|coming soon|under construction|placeholder|` +
+		`to be (?:done|added|written|determined|announced)|lorem ipsum.*|` +
+		`(?:add|write|describe|put) .* here|\{\{.*\}\}|<(?:your|project|insert) [^>]*>)$`)
+
+	// htmlCommentRE matches HTML comments, like template instructions.
+	htmlCommentRE = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+	// underlineRE matches setext and reStructuredText heading underlines.
+	underlineRE = regexp.MustCompile(`^(?:=+|-+|~+|\^+|\*+)$`)
+)
+
+// placeholderChecker finds empty files and documents that only contain
+// placeholder text, like "TODO" or "Coming soon", or template boilerplate
+// without any content. The root README is checked by the readme structure checker.
+type placeholderChecker struct {
+	CheckerBase
+}
+
+func newPlaceholderChecker() *placeholderChecker {
+	return &placeholderChecker{}
+}
+
+// Vendored and generated files are not maintained here.
+func (c *placeholderChecker) skipGenerated() {}
+
+func (c *placeholderChecker) PushFile(f *File) {
+	if f.dir || f.symlink || emptyMarkerFiles[f.baseName] || isTestdataPath(f.origName) {
+		return
+	}
+	if f.origName == f.baseName && isReadmeFile(f.baseName) {
+		return
+	}
+	switch {
+	case f.size == 0:
+		c.AcceptFile(f)
+	case f.size <= placeholderMaxSize && placeholderDocRE.MatchString(f.baseName):
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *placeholderChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	for _, f := range c.files {
+		if f.size == 0 || strings.TrimSpace(f.contents) == "" {
+			warnings = append(warnings, fmt.Sprintf("%s: file is empty", f.origName))
+			continue
+		}
+		if w := placeholderProblem(f.contents); w != "" {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", f.origName, w))
+		}
+	}
+	return warnings
+}
+
+// placeholderProblem describes why the document has no real content.
+// Returns empty string for documents with content.
+func placeholderProblem(contents string) string {
+	lines := strings.Split(htmlCommentRE.ReplaceAllString(contents, ""), "\n")
+	placeholder := ""
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || underlineRE.MatchString(line) || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if i+1 < len(lines) && underlineRE.MatchString(strings.TrimSpace(lines[i+1])) {
+			// A setext or reStructuredText heading.
+			continue
+		}
+		text := strings.TrimSpace(strings.Trim(line, "*_>-.!:[]() \t"))
+		if !placeholderLineRE.MatchString(text) {
+			return ""
+		}
+		if placeholder == "" {
+			placeholder = text
+		}
+	}
+	if placeholder == "" {
+		return "file only contains headings and comments, no content"
+	}
+	return fmt.Sprintf("file only contains placeholder text %q", placeholder)
+}
+
+// isTestdataPath reports whether the file is a test fixture,
+// which is often empty on purpose.
+func isTestdataPath(filename string) bool {
+	for _, elem := range strings.Split(path.Dir(filename), "/") {
+		switch strings.ToLower(elem) {
+		case "testdata", "fixtures", "__fixtures__", "test-fixtures":
+			return true
+		}
+	}
+	return false
+}
+
+// isReadmeFile reports whether the file name is a README.
+func isReadmeFile(filename string) bool {
+	readme, _ := lookupCommunityFile("README")
+	return readme.re.MatchString(filename)
+}
@@ -0,0 +1,120 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// This file hides the differences between the operating systems
+// from the fetchers, so local and clone modes work the same way
+// on Linux, macOS and Windows.
+
+// gitSymlinkMode is a git index mode of the symbolic links.
+const gitSymlinkMode = "120000"
+
+// indexEntry is a file entry of the git index.
+type indexEntry struct {
+	// sha is a git blob hash.
+	sha string
+
+	// mode is a git file mode, like "100644" or gitSymlinkMode.
+	mode string
+}
+
+// readGitIndex returns the git index entries of a repository checkout by their paths.
+func readGitIndex(ctx context.Context, dir string) (map[string]indexEntry, error) {
+	out, err := exec.CommandContext(ctx, "git", "-C", dir, "ls-files", "-s", "-z").Output()
+	if err != nil {
+		return nil, fmt.Errorf("list %s files: %v", dir, err)
+	}
+	index := make(map[string]indexEntry)
+	for _, line := range strings.Split(string(out), "\x00") {
+		// Line format is "<mode> <sha> <stage>\t<path>".
+		tab := strings.IndexByte(line, '\t')
+		if tab == -1 {
+			continue
+		}
+		fields := strings.Fields(line[:tab])
+		if len(fields) != 3 {
+			continue
+		}
+		index[line[tab+1:]] = indexEntry{sha: fields[1], mode: fields[0]}
+	}
+	return index, nil
+}
+
+// readLinkTarget returns a symlink target path with forward slashes.
+//
+// With core.symlinks=false, which is the default on Windows,
+// git checks symlinks out as plain files that contain the target path.
+func readLinkTarget(filename string) (string, error) {
+	target, err := os.Readlink(filename)
+	if err == nil {
+		return filepath.ToSlash(target), nil
+	}
+	info, statErr := os.Lstat(filename)
+	if statErr != nil || !info.Mode().IsRegular() {
+		return "", err
+	}
+	data, readErr := ioutil.ReadFile(filename)
+	if readErr != nil {
+		return "", readErr
+	}
+	return filepath.ToSlash(string(data)), nil
+}
+
+// removeAll is like os.RemoveAll, but it also removes read-only files,
+// like git objects, which can't be removed on Windows otherwise.
+func removeAll(dir string) error {
+	err := os.RemoveAll(dir)
+	if err == nil {
+		return nil
+	}
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode()&0200 == 0 && info.Mode()&os.ModeSymlink == 0 {
+			os.Chmod(path, info.Mode().Perm()|0200)
+		}
+		return nil
+	})
+	return os.RemoveAll(dir)
+}
+
+// realPath returns dir with the symlinks resolved, like /private/var/folders
+// instead of /var/folders on macOS. It returns dir on errors.
+func realPath(dir string) string {
+	if p, err := filepath.EvalSymlinks(dir); err == nil {
+		return p
+	}
+	return dir
+}
+
+// localPathPrefixes returns the prefixes that external programs can use
+// to refer to files inside dir: native and slash-separated paths,
+// with and without the symlinks resolved.
+func localPathPrefixes(dir string) []string {
+	var prefixes []string
+	seen := make(map[string]bool)
+	for _, d := range []string{dir, realPath(dir)} {
+		for _, p := range []string{d + string(filepath.Separator), filepath.ToSlash(d) + "/"} {
+			if !seen[p] {
+				seen[p] = true
+				prefixes = append(prefixes, p)
+			}
+		}
+	}
+	return prefixes
+}
+
+// lineBreak returns the line break used by the file contents:
+// "\r\n" for files with Windows line endings, "\n" otherwise.
+func lineBreak(contents string) string {
+	if i := strings.IndexByte(contents, '\n'); i > 0 && contents[i-1] == '\r' {
+		return "\r\n"
+	}
+	return "\n"
+}
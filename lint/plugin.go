@@ -0,0 +1,216 @@
+package lint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// pluginConfig describes an external checker executable.
+//
+// A plugin receives pluginInput as JSON on stdin and
+// writes pluginOutput as JSON to stdout.
+type pluginConfig struct {
+	// Name is a checker name. Defaults to the executable base name.
+	Name string `yaml:"name"`
+
+	// Command is an executable path followed by its arguments.
+	// Relative paths are resolved against the config file directory.
+	Command []string `yaml:"command"`
+
+	// Contents are path patterns of the files which contents
+	// are sent to the plugin. Other files are sent without contents.
+	// See compileGlob for the patterns syntax.
+	Contents []string `yaml:"contents"`
+
+	// Severity is a plugin warnings severity, warning by default.
+	Severity *Severity `yaml:"severity"`
+}
+
+// UnmarshalYAML makes it possible to specify a plugin with just its path.
+func (p *pluginConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var path string
+	if err := unmarshal(&path); err == nil {
+		*p = pluginConfig{Command: []string{path}}
+		return nil
+	}
+	type plain pluginConfig
+	return unmarshal((*plain)(p))
+}
+
+// pluginInput is sent to the plugin stdin.
+type pluginInput struct {
+	Files []pluginFile `json:"files"`
+}
+
+type pluginFile struct {
+	// Path is a repo-relative file path with forward slashes.
+	Path string `json:"path"`
+
+	// Size is a file size in bytes, if known.
+	Size int64 `json:"size,omitempty"`
+
+	// Contents is only set for the files matched by the plugin contents patterns.
+	Contents *string `json:"contents,omitempty"`
+
+	// Local is a file name on a local filesystem, if the file is available locally.
+	Local string `json:"local,omitempty"`
+}
+
+// pluginOutput is read from the plugin stdout.
+type pluginOutput struct {
+	Warnings []pluginWarning `json:"warnings"`
+}
+
+type pluginWarning struct {
+	// File is a repo-relative file path. Optional.
+	File string `json:"file"`
+
+	// Line is a 1-based line number inside File. Optional.
+	Line int `json:"line"`
+
+	Message string `json:"message"`
+}
+
+// pluginMaxOutputSize limits the plugin stdout size.
+const pluginMaxOutputSize = 16 << 20
+
+// pluginLineBreaks replaces line breaks in the plugin warnings,
+// so every warning stays on its own line in the reports.
+var pluginLineBreaks = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+func (w pluginWarning) String() string {
+	file := pluginLineBreaks.Replace(w.File)
+	msg := pluginLineBreaks.Replace(w.Message)
+	switch {
+	case file != "" && w.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", file, w.Line, msg)
+	case file != "":
+		return fmt.Sprintf("%s: %s", file, msg)
+	default:
+		return msg
+	}
+}
+
+// limitedBuffer is a bytes.Buffer that fails the writes exceeding max bytes.
+type limitedBuffer struct {
+	bytes.Buffer
+	max      int
+	overflow bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.max {
+		b.overflow = true
+		return 0, errors.New("buffer size limit exceeded")
+	}
+	return b.Buffer.Write(p)
+}
+
+// loadPlugins adds checkers for the plugins declared in the config.
+func (l *Runner) loadPlugins() error {
+	for _, p := range l.config.Plugins {
+		if len(p.Command) == 0 || p.Command[0] == "" {
+			return errors.New("config: plugins: empty command")
+		}
+		command := append([]string(nil), p.Command...)
+		// Both "./policy" and ".\policy" on Windows are relative to the config.
+		if strings.ContainsRune(filepath.ToSlash(command[0]), '/') && !filepath.IsAbs(command[0]) && l.configFile != "" {
+			command[0] = filepath.Join(filepath.Dir(l.configFile), command[0])
+		}
+		name := p.Name
+		if name == "" {
+			name = filepath.Base(command[0])
+		}
+		if _, ok := l.checkers[name]; ok {
+			return fmt.Errorf("config: plugins: %s checker is already defined", name)
+		}
+		contents, err := newPathMatcher(p.Contents)
+		if err != nil {
+			return fmt.Errorf("config: plugins: %s: %v", name, err)
+		}
+		severity := SeverityWarning
+		if p.Severity != nil {
+			severity = *p.Severity
+		}
+		l.AddChecker(name, &pluginChecker{name: name, command: command, contents: contents}, severity)
+	}
+	return nil
+}
+
+// pluginChecker runs an external executable that implements the plugin protocol.
+// It receives all repository files.
+type pluginChecker struct {
+	CheckerBase
+	name     string
+	command  []string
+	contents *pathMatcher
+}
+
+func (c *pluginChecker) PushFile(f *File) {
+	if c.contents.Match(f.origName) {
+		f.require.contents = true
+	}
+	c.AcceptFile(f)
+}
+
+func (c *pluginChecker) externalTool() string { return c.command[0] }
+
+// Results depend on the plugin logic that can't be tracked.
+func (c *pluginChecker) uncachedResults() {}
+
+func (c *pluginChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	var in pluginInput
+	for _, f := range c.files {
+		pf := pluginFile{Path: f.origName, Size: f.size, Local: f.tempName}
+		if f.require.contents {
+			contents := f.contents
+			pf.Contents = &contents
+		}
+		in.Files = append(in.Files, pf)
+	}
+	out, err := c.run(ctx, &in)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("\terror: %s plugin: %v", c.name, err)
+		}
+		return nil
+	}
+	for _, w := range out.Warnings {
+		if strings.TrimSpace(w.Message) == "" {
+			continue
+		}
+		warnings = append(warnings, w.String())
+	}
+	return warnings
+}
+
+func (c *pluginChecker) run(ctx context.Context, in *pluginInput) (*pluginOutput, error) {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	stdout := limitedBuffer{max: pluginMaxOutputSize}
+	stderr := limitedBuffer{max: pluginMaxOutputSize}
+	cmd := exec.CommandContext(ctx, c.command[0], c.command[1:]...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if stdout.overflow {
+			return nil, fmt.Errorf("output exceeds %s", formatSize(pluginMaxOutputSize))
+		}
+		return nil, fmt.Errorf("%v: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	var out pluginOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return nil, fmt.Errorf("decode output: %v", err)
+	}
+	return &out, nil
+}
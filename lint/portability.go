@@ -0,0 +1,100 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxPortablePathLength is a repo path length that still fits into the
+// Windows MAX_PATH limit of 260 characters, leaving the rest for the checkout directory.
+const maxPortablePathLength = 200
+
+// maxPortableNameLength is a file name length limit in bytes of most file systems.
+const maxPortableNameLength = 255
+
+// windowsInvalidChars are the characters that can't be used in Windows file names.
+const windowsInvalidChars = `<>:"|?*\`
+
+// windowsReservedNames are the device names that can't be used as Windows
+// file names, with any extension, so aux.go is not allowed either.
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// nonPortableNameChecker finds paths that can't be checked out on Windows,
+// like "aux.go" or "notes: todo.md", and paths that are too long.
+// Unlike the unwanted file checker, it checks every path element,
+// so a bad directory name is reported once, not for every file inside it.
+type nonPortableNameChecker struct {
+	CheckerBase
+}
+
+func newNonPortableNameChecker() *nonPortableNameChecker {
+	return &nonPortableNameChecker{}
+}
+
+func (c *nonPortableNameChecker) PushFile(f *File) {
+	c.AcceptFile(f)
+}
+
+// Results depend on the full path and directories are reported once for all files,
+// while the result cache only knows the base name.
+func (c *nonPortableNameChecker) uncachedResults() {}
+
+func (c *nonPortableNameChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	reported := make(map[string]bool)
+	for _, f := range c.files {
+		// Check directories first, so the topmost bad element is reported.
+		elems := strings.Split(f.origName, "/")
+		for i, elem := range elems {
+			p := strings.Join(elems[:i+1], "/")
+			if reported[p] {
+				break
+			}
+			if problem := nonPortableNameProblem(elem); problem != "" {
+				reported[p] = true
+				warnings = append(warnings, fmt.Sprintf("%s: %s", p, problem))
+				break
+			}
+		}
+		if n := utf8.RuneCountInString(f.origName); n > maxPortablePathLength {
+			w := fmt.Sprintf("%s: path is %d characters long, longer than %d, it may not fit into the Windows path length limit",
+				f.origName, n, maxPortablePathLength)
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// nonPortableNameProblem describes why the path element name
+// is not portable. Returns empty string for portable names.
+func nonPortableNameProblem(name string) string {
+	if i := strings.IndexAny(name, windowsInvalidChars); i >= 0 {
+		return fmt.Sprintf("%q is not allowed in Windows file names", name[i])
+	}
+	for _, r := range name {
+		if r < 0x20 {
+			return fmt.Sprintf("control character %q is not allowed in Windows file names", r)
+		}
+	}
+	if strings.HasSuffix(name, " ") || strings.HasSuffix(name, ".") {
+		return "Windows strips trailing spaces and dots from file names"
+	}
+	base := strings.TrimRight(strings.ToUpper(name), " ")
+	if i := strings.IndexByte(base, '.'); i >= 0 {
+		base = strings.TrimRight(base[:i], " ")
+	}
+	if windowsReservedNames[base] {
+		return fmt.Sprintf("%s is a reserved device name on Windows, even with an extension", base)
+	}
+	if len(name) > maxPortableNameLength {
+		return fmt.Sprintf("name is %d bytes long, most file systems allow at most %d", len(name), maxPortableNameLength)
+	}
+	return ""
+}
@@ -0,0 +1,139 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+var (
+	protoCommentRE   = regexp.MustCompile(`(?s)/\*.*?\*/|//[^\n]*`)
+	protoSyntaxRE    = regexp.MustCompile(`(?m)^\s*(?:syntax|edition)\s*=`)
+	protoPackageRE   = regexp.MustCompile(`(?m)^\s*package\s+[\w.]+\s*;`)
+	protoGoPackageRE = regexp.MustCompile(`(?m)^\s*option\s+go_package\s*=`)
+
+	// bufLintRE matches buf lint text output lines, like "a.proto:3:1:message".
+	bufLintRE = regexp.MustCompile(`^([^:\s]+\.proto):(\d+):(\d+):(.+)$`)
+
+	// protolintRE matches protolint output lines, like "[a.proto:3:1] message".
+	protolintRE = regexp.MustCompile(`^\[([^:\s]+\.proto):(\d+):(\d+)\] (.+)$`)
+)
+
+// protoChecker finds problems in protobuf definitions.
+//
+// In clone and local modes, it runs buf lint for repositories with buf.yaml
+// or protolint, when they're installed. Otherwise, basic built-in checks are used:
+// missing syntax and package statements, and missing go_package options
+// in repositories with Go modules.
+type protoChecker struct {
+	CheckerBase
+
+	// bufConfig reports whether the repository root has a buf configuration.
+	bufConfig bool
+
+	// goModule reports whether the repository has a go.mod file.
+	goModule bool
+}
+
+func newProtoChecker() *protoChecker {
+	return &protoChecker{}
+}
+
+func (c *protoChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.bufConfig = false
+	c.goModule = false
+}
+
+// Generated protos are not maintained in the repository.
+func (c *protoChecker) skipGenerated() {}
+
+// Results depend on the installed linters and other repository files.
+func (c *protoChecker) uncachedResults() {}
+
+// go.mod and buf.yaml are found using all repository files.
+func (c *protoChecker) fullTree() {}
+
+func (c *protoChecker) PushFile(f *File) {
+	switch {
+	case f.origName == "buf.yaml" || f.origName == "buf.work.yaml":
+		c.bufConfig = true
+	case f.baseName == "go.mod":
+		c.goModule = true
+	case strings.HasSuffix(f.baseName, ".proto"):
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *protoChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	if len(c.files) == 0 {
+		return nil
+	}
+	if rootDir := c.files[0].rootDir; rootDir != "" {
+		if warnings, ok := c.runLinter(ctx, rootDir); ok {
+			return warnings
+		}
+	}
+
+	for _, f := range c.files {
+		code := protoCommentRE.ReplaceAllString(f.contents, "")
+		if !protoSyntaxRE.MatchString(code) {
+			w := fmt.Sprintf("%s: missing syntax statement, defaulting to proto2", f.origName)
+			warnings = append(warnings, w)
+		}
+		if !protoPackageRE.MatchString(code) {
+			w := fmt.Sprintf("%s: missing package statement, definitions may conflict with other protos", f.origName)
+			warnings = append(warnings, w)
+		}
+		if c.goModule && !protoGoPackageRE.MatchString(code) {
+			w := fmt.Sprintf("%s: missing go_package option, Go code can't be generated", f.origName)
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// runLinter runs buf lint or protolint inside rootDir.
+// Reports false if no linter is installed or it failed to run.
+func (c *protoChecker) runLinter(ctx context.Context, rootDir string) ([]string, bool) {
+	var cmd *exec.Cmd
+	var lineRE *regexp.Regexp
+	if _, err := exec.LookPath("buf"); err == nil && c.bufConfig {
+		cmd = exec.CommandContext(ctx, "buf", "lint", "--error-format=text")
+		lineRE = bufLintRE
+	} else if _, err := exec.LookPath("protolint"); err == nil {
+		args := []string{"lint"}
+		for _, f := range c.files {
+			args = append(args, f.origName)
+		}
+		cmd = exec.CommandContext(ctx, "protolint", args...)
+		lineRE = protolintRE
+	} else {
+		return nil, false
+	}
+	cmd.Dir = rootDir
+
+	// Both linters exit with a non-zero code when they find problems,
+	// so the output is checked first.
+	out, err := cmd.CombinedOutput()
+	var warnings []string
+	for _, line := range strings.Split(string(out), "\n") {
+		m := lineRE.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		w := fmt.Sprintf("%s:%s:%s: %s", m[1], m[2], m[3], strings.TrimSpace(m[4]))
+		warnings = append(warnings, w)
+	}
+	if err != nil && len(warnings) == 0 {
+		if ctx.Err() == nil {
+			log.Printf("\terror: %s: %v: %s", cmd.Args[0], err, strings.TrimSpace(string(out)))
+		}
+		return nil, false
+	}
+	return warnings, true
+}
@@ -0,0 +1,187 @@
+package lint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// stringList is a flag.Value that collects all flag occurrences.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ", ") }
+
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+// parseHeaders converts "Name: value" strings into http.Header.
+func parseHeaders(list []string) (http.Header, error) {
+	h := make(http.Header)
+	for _, s := range list {
+		colon := strings.IndexByte(s, ':')
+		if colon <= 0 {
+			return nil, fmt.Errorf("bad header %q: expected Name: value", s)
+		}
+		h.Add(strings.TrimSpace(s[:colon]), strings.TrimSpace(s[colon+1:]))
+	}
+	return h, nil
+}
+
+// headerTransport adds extra headers to every outgoing request.
+// Used to pass caching proxy specific auth to the proxy.
+type headerTransport struct {
+	headers http.Header
+	base    http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTripper should not modify the original request.
+	req = req.Clone(req.Context())
+	for name, values := range t.headers {
+		req.Header[name] = values
+	}
+	return t.base.RoundTrip(req)
+}
+
+// hostLimitTransport limits the number of concurrent requests per host.
+type hostLimitTransport struct {
+	limit int
+	base  http.RoundTripper
+
+	mu    sync.Mutex
+	hosts map[string]chan struct{}
+}
+
+func newHostLimitTransport(limit int, base http.RoundTripper) *hostLimitTransport {
+	return &hostLimitTransport{
+		limit: limit,
+		base:  base,
+		hosts: make(map[string]chan struct{}),
+	}
+}
+
+func (t *hostLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	sem, ok := t.hosts[req.URL.Host]
+	if !ok {
+		sem = make(chan struct{}, t.limit)
+		t.hosts[req.URL.Host] = sem
+	}
+	t.mu.Unlock()
+
+	select {
+	case sem <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		<-sem
+		return nil, err
+	}
+	// Response body is still being downloaded,
+	// so the slot is released when it's closed.
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: func() { <-sem }}
+	return resp, nil
+}
+
+// retryTransport retries idempotent requests that failed with
+// a network error or a temporary server error, like 503.
+// Delay between attempts doubles after every retry.
+type retryTransport struct {
+	retries int
+	delay   time.Duration
+	base    http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != "GET" && req.Method != "HEAD" {
+		return t.base.RoundTrip(req)
+	}
+	delay := t.delay
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt == t.retries || !isTemporaryFailure(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		delay *= 2
+	}
+}
+
+// isTemporaryFailure reports whether a request may succeed if repeated.
+func isTemporaryFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		// Timeouts are not retried: a slow host is going to be slow again.
+		return !isTimeout(err) && !errors.Is(err, context.Canceled)
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// releaseBody calls release once the body is closed.
+type releaseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// expandRawURL fills raw URL template placeholders for the given file.
+//
+// Supported placeholders: {owner}, {repo}, {ref} and {path}.
+func expandRawURL(template, owner, repo, ref, path string) string {
+	escaped := strings.Split(path, "/")
+	for i := range escaped {
+		escaped[i] = url.PathEscape(escaped[i])
+	}
+	return strings.NewReplacer(
+		"{owner}", url.PathEscape(owner),
+		"{repo}", url.PathEscape(repo),
+		"{ref}", url.PathEscape(ref),
+		"{path}", strings.Join(escaped, "/"),
+	).Replace(template)
+}
+
+// getRawContents downloads file contents by a plain HTTP GET request.
+func getRawContents(ctx context.Context, client *http.Client, rawURL string) (string, error) {
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s: %s", rawURL, resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	return string(data), err
+}
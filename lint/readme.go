@@ -0,0 +1,175 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// readmeStructureConfig overrides the README structure checker settings.
+type readmeStructureConfig struct {
+	// RequiredSections are the README sections that must be present.
+	// Every entry is a list of alternative heading prefixes separated by "|",
+	// like "Installation|Getting started". Nil means defaultReadmeSections,
+	// empty list disables the sections check.
+	RequiredSections []string `yaml:"required_sections"`
+
+	// MinWords is a minimal README length in words, 30 by default.
+	MinWords int `yaml:"min_words"`
+}
+
+// defaultReadmeSections are the sections required by default.
+var defaultReadmeSections = []string{
+	"Installation|Install|Getting started|Quick start|Quickstart|Setup|Build",
+	"Usage|Example|How to use|Getting started|Quick start|Quickstart|Documentation|Docs",
+}
+
+const defaultReadmeMinWords = 30
+
+var (
+	// readmeMarkdownRE matches the root markdown README names.
+	readmeMarkdownRE = regexp.MustCompile(`(?i)^README\.(?:md|markdown)$`)
+
+	// readmeTitleRE matches the markdown and HTML level 1 headings.
+	readmeTitleRE = regexp.MustCompile(`(?im)^ {0,3}#\s+\S|^ {0,3}=+\s*$|<h1\b`)
+
+	// readmeHeadingRE matches the HTML headings, submatch group is the heading text.
+	readmeHeadingRE = regexp.MustCompile(`(?is)<h[1-6]\b[^>]*>(.*?)</h[1-6]\s*>`)
+)
+
+// readmeSection is a required README section.
+type readmeSection struct {
+	// name is the first alternative, it's used in warnings.
+	name string
+	re   *regexp.Regexp
+}
+
+func newReadmeSection(alternatives string) readmeSection {
+	var parts []string
+	for _, alt := range strings.Split(alternatives, "|") {
+		if alt = strings.TrimSpace(alt); alt != "" {
+			parts = append(parts, regexp.QuoteMeta(alt))
+		}
+	}
+	return readmeSection{
+		name: strings.TrimSpace(strings.Split(alternatives, "|")[0]),
+		re:   regexp.MustCompile(`(?i)\b(?:` + strings.Join(parts, "|") + `)`),
+	}
+}
+
+// readmeStructureChecker finds empty and one-line READMEs.
+// When the readme_structure config section is specified, it also finds
+// too short READMEs and markdown READMEs without a title or the required
+// sections, like Installation and Usage.
+type readmeStructureChecker struct {
+	CheckerBase
+
+	// sections are the required sections, minWords is a minimal length.
+	// Both are zero unless the readme_structure config section is specified.
+	sections []readmeSection
+	minWords int
+
+	// repoName is the checked repository name, empty in local mode.
+	repoName string
+}
+
+func newReadmeStructureChecker() *readmeStructureChecker {
+	return &readmeStructureChecker{}
+}
+
+func (c *readmeStructureChecker) setSections(sections []string) {
+	c.sections = nil
+	for _, s := range sections {
+		if strings.TrimSpace(strings.Replace(s, "|", "", -1)) != "" {
+			c.sections = append(c.sections, newReadmeSection(s))
+		}
+	}
+}
+
+func (c *readmeStructureChecker) PushFile(f *File) {
+	// Only the root README is a repository front page.
+	readme, _ := lookupCommunityFile("README")
+	if f.origName == f.baseName && readme.re.MatchString(f.baseName) {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *readmeStructureChecker) setRepoURL(u string) {
+	c.repoName = ""
+	if u != "" {
+		c.repoName = path.Base(u)
+	}
+}
+
+// Results depend on the repository name.
+func (c *readmeStructureChecker) uncachedResults() {}
+
+func (c *readmeStructureChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	for _, f := range c.files {
+		if w := c.checkLength(f); w != "" {
+			// There is nothing to structure yet.
+			warnings = append(warnings, fmt.Sprintf("%s: %s", f.origName, w))
+			continue
+		}
+		if c.minWords == 0 || !readmeMarkdownRE.MatchString(f.baseName) {
+			continue
+		}
+		doc := f.prose()
+		if !readmeTitleRE.MatchString(doc) {
+			warnings = append(warnings, fmt.Sprintf("%s: no title heading", f.origName))
+		}
+		headings := append([]string{}, f.headings()...)
+		for _, m := range readmeHeadingRE.FindAllStringSubmatch(doc, -1) {
+			headings = append(headings, htmlTagRE.ReplaceAllString(m[1], ""))
+		}
+		for _, s := range c.sections {
+			if !matchAnyHeading(s.re, headings) {
+				w := fmt.Sprintf("%s: no %q section", f.origName, s.name)
+				warnings = append(warnings, w)
+			}
+		}
+	}
+	return warnings
+}
+
+// checkLength returns a problem description for empty, one-line
+// and too short READMEs.
+func (c *readmeStructureChecker) checkLength(f *File) string {
+	text := strings.TrimSpace(f.contents)
+	if text == "" {
+		return "README is empty"
+	}
+	if c.repoName != "" && strings.EqualFold(strings.Trim(text, "#=- \t\r\n"), c.repoName) {
+		return "README only contains the repository name"
+	}
+	if !strings.Contains(text, "\n") {
+		return "README consists of a single line"
+	}
+	// Badges, link targets and markup are not the description.
+	// Badges are images inside links, so links are replaced twice.
+	text = inlineLinkRE.ReplaceAllString(inlineLinkRE.ReplaceAllString(text, "$1"), "$1")
+	text = htmlTagRE.ReplaceAllString(text, " ")
+	n := 0
+	for _, word := range strings.Fields(text) {
+		if strings.IndexFunc(word, unicode.IsLetter) != -1 {
+			n++
+		}
+	}
+	if n < c.minWords {
+		return fmt.Sprintf("README is only %d words long, describe what the project does and how to use it", n)
+	}
+	return ""
+}
+
+func matchAnyHeading(re *regexp.Regexp, headings []string) bool {
+	for _, h := range headings {
+		if re.MatchString(h) {
+			return true
+		}
+	}
+	return false
+}
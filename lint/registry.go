@@ -0,0 +1,443 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+)
+
+// checkersDocURL is a base URL of the checkers documentation.
+const checkersDocURL = "https://github.com/Quasilyte/repolint/blob/master/docs/checkers.md"
+
+// CheckerInfo describes a registered checker.
+type CheckerInfo struct {
+	// Name is a checker name used in warnings and config files.
+	Name string
+
+	// Description is a short summary of issues the checker finds.
+	Description string
+
+	// Category groups related checkers in reports, like "docs" or "security".
+	Category string
+
+	// Severity is a default severity of the checker warnings.
+	Severity Severity
+
+	// DocURL is a link to the checker documentation.
+	DocURL string
+
+	// New creates a checker instance.
+	New func() Checker
+}
+
+// checkerRegistry lists all built-in checkers.
+var checkerRegistry = []CheckerInfo{
+	{
+		Name:        "broken link",
+		Description: "documentation links that can't be followed, including #anchor links",
+		Category:    "docs",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newBrokenLinkChecker() },
+	},
+	{
+		Name:        "insecure link",
+		Description: "http:// documentation links which targets are reachable over HTTPS",
+		Category:    "security",
+		Severity:    SeverityInfo,
+		New:         func() Checker { return newInsecureLinkChecker() },
+	},
+	{
+		Name:        "misspell",
+		Description: "commonly misspelled English words in documentation files",
+		Category:    "docs",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newMisspellChecker() },
+	},
+	{
+		Name:        "var name typo",
+		Description: "misspelled environment variables, like $GOPAHT",
+		Category:    "docs",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newVarTypoChecker() },
+	},
+	{
+		Name:        "unwanted file",
+		Description: "committed editor backups, IDE project files and OS system files, like .DS_STORE or .idea/",
+		Category:    "hygiene",
+		Severity:    SeverityError,
+		New:         func() Checker { return newUnwantedFileChecker() },
+	},
+	{
+		Name:        "non-portable name",
+		Description: "paths that are invalid or too long on Windows, like aux.go or names with ':'",
+		Category:    "hygiene",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newNonPortableNameChecker() },
+	},
+	{
+		Name:        "placeholder file",
+		Description: "empty files and CONTRIBUTING or CHANGELOG files that only contain placeholder text, like TODO",
+		Category:    "hygiene",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newPlaceholderChecker() },
+	},
+	{
+		Name:        "stale copyright",
+		Description: "license and notice files which copyright year is years behind the last commit",
+		Category:    "legal",
+		Severity:    SeverityInfo,
+		New:         func() Checker { return newStaleCopyrightChecker() },
+	},
+	{
+		Name:        "sloppy copyright",
+		Description: "license files with unfilled copyright placeholders",
+		Category:    "legal",
+		Severity:    SeverityError,
+		New:         func() Checker { return newSloppyCopyrightChecker() },
+	},
+	{
+		Name:        "license detection",
+		Description: "license files with unrecognized or truncated texts, detected licenses are recorded in the JSON report",
+		Category:    "legal",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newLicenseDetectionChecker() },
+	},
+	{
+		Name:        "license tampering",
+		Description: "license texts that differ from the canonical license text",
+		Category:    "legal",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newLicenseTamperingChecker() },
+	},
+	{
+		Name:        "acronym",
+		Description: "acronyms written in lowercase, like sql",
+		Category:    "docs",
+		Severity:    SeverityInfo,
+		New:         func() Checker { return newAcronymChecker() },
+	},
+	{
+		Name:        "language stats",
+		Description: "displayed language skewed by generated code, missing build entrypoints",
+		Category:    "metadata",
+		Severity:    SeverityInfo,
+		New:         func() Checker { return newLanguageStatsChecker() },
+	},
+	{
+		Name:        "description",
+		Description: "typos in the repository description and topics, missing topics",
+		Category:    "metadata",
+		Severity:    SeverityInfo,
+		New:         func() Checker { return newDescriptionChecker() },
+	},
+	{
+		Name:        "repo metadata",
+		Description: "missing description or topics, no license detected by github, unprotected default branch, disabled issues that README asks for",
+		Category:    "metadata",
+		Severity:    SeverityInfo,
+		New:         func() Checker { return newRepoMetadataChecker() },
+	},
+	{
+		Name:        "fork drift",
+		Description: "diverged forks with README still pointing to the upstream",
+		Category:    "metadata",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newForkDriftChecker() },
+	},
+	{
+		Name:        "default branch link",
+		Description: "links to the master branch files when the default branch is different",
+		Category:    "docs",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newDefaultBranchLinkChecker() },
+	},
+	{
+		Name:        "readme structure",
+		Description: "empty and one-line READMEs, short READMEs without a title or Installation and Usage sections (opt-in)",
+		Category:    "docs",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newReadmeStructureChecker() },
+	},
+	{
+		Name:        "changelog",
+		Description: "Keep a Changelog files without an Unreleased section, with misordered versions, non-ISO dates or untagged versions",
+		Category:    "docs",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newChangelogChecker() },
+	},
+	{
+		Name:        "todo comments",
+		Description: "TODO, FIXME and XXX comments count and TODOs that reference closed issues (opt-in)",
+		Category:    "hygiene",
+		Severity:    SeverityInfo,
+		New:         func() Checker { return newTodoCommentChecker() },
+	},
+	{
+		Name:        "template",
+		Description: "files and README sections removed from the template repository",
+		Category:    "hygiene",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newTemplateChecker() },
+	},
+	{
+		Name:        "stale docs",
+		Description: "README and CHANGELOG files not updated for years while the code changes",
+		Category:    "docs",
+		Severity:    SeverityInfo,
+		New:         func() Checker { return newStaleDocsChecker() },
+	},
+	{
+		Name:        "mirror",
+		Description: "mirror repositories which README points to a canonical home elsewhere",
+		Category:    "metadata",
+		Severity:    SeverityInfo,
+		New:         func() Checker { return newMirrorChecker() },
+	},
+	{
+		Name:        "trailing whitespace",
+		Description: "trailing whitespace, whitespace-only lines and missing final newlines in documentation and config files",
+		Category:    "hygiene",
+		Severity:    SeverityInfo,
+		New:         func() Checker { return newTrailingWhitespaceChecker() },
+	},
+	{
+		Name:        "bidi",
+		Description: "Unicode BiDi control characters in source and documentation files (Trojan Source)",
+		Category:    "security",
+		Severity:    SeverityError,
+		New:         func() Checker { return newBidiChecker() },
+	},
+	{
+		Name:        "invisible characters",
+		Description: "invalid UTF-8, byte order marks and zero-width characters in source and documentation files",
+		Category:    "security",
+		Severity:    SeverityError,
+		New:         func() Checker { return newInvisibleCharsChecker() },
+	},
+	{
+		Name:        "dependency pinning",
+		Description: "unpinned dependencies in package.json, Dockerfile pip installs and go.mod (opt-in)",
+		Category:    "dependencies",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newPinningChecker() },
+	},
+	{
+		Name:        "container config",
+		Description: "invalid Docker Compose and devcontainer.json files, references to missing Dockerfiles",
+		Category:    "build",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newContainerConfigChecker() },
+	},
+	{
+		Name:        "community files",
+		Description: "missing LICENSE, README, CONTRIBUTING, CODE_OF_CONDUCT.md and SECURITY.md files",
+		Category:    "community",
+		Severity:    SeverityInfo,
+		New:         func() Checker { return newCommunityFilesChecker() },
+	},
+	{
+		Name:        "issue templates",
+		Description: "missing issue and pull request templates, issue templates without required front matter fields",
+		Category:    "community",
+		Severity:    SeverityInfo,
+		New:         func() Checker { return newIssueTemplatesChecker() },
+	},
+	{
+		Name:        "codeowners",
+		Description: "CODEOWNERS syntax errors, patterns that match no files and owners that don't exist",
+		Category:    "community",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newCodeownersChecker() },
+	},
+	{
+		Name:        "gitignore",
+		Description: "missing .gitignore, .gitignore entries for committed unwanted files",
+		Category:    "hygiene",
+		Severity:    SeverityInfo,
+		New:         func() Checker { return newGitignoreChecker() },
+	},
+	{
+		Name:        "gitattributes",
+		Description: "missing .gitattributes with binary assets or mixed line endings, unknown attributes and unmatched patterns",
+		Category:    "hygiene",
+		Severity:    SeverityInfo,
+		New:         func() Checker { return newGitattributesChecker() },
+	},
+	{
+		Name:        "badge",
+		Description: "README badges of dead or deprecated services, like travis-ci.org, and missing badge images",
+		Category:    "docs",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newBadgeChecker() },
+	},
+	{
+		Name:        "travis yml",
+		Description: ".travis.yml with unknown keys or without active Travis builds",
+		Category:    "ci",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newTravisYmlChecker() },
+	},
+	{
+		Name:        "dependency dir",
+		Description: "committed dependency and cache directories, like node_modules or __pycache__",
+		Category:    "dependencies",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newDependencyDirChecker() },
+	},
+	{
+		Name:        "deprecated actions",
+		Description: "GitHub workflows with deprecated action versions and workflow commands, like set-output",
+		Category:    "ci",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newWorkflowChecker() },
+	},
+	{
+		Name:        "contribution friction",
+		Description: "repositories that are hard to contribute to: no build instructions, CI, tests or CONTRIBUTING (opt-in)",
+		Category:    "community",
+		Severity:    SeverityInfo,
+		New:         func() Checker { return newFrictionChecker() },
+	},
+	{
+		Name:        "api docs",
+		Description: "invalid OpenAPI and Swagger specs and documented endpoints missing from them (opt-in)",
+		Category:    "docs",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newAPIDocsChecker() },
+	},
+	{
+		Name:        "html accessibility",
+		Description: "HTML files without a lang attribute, images without alt text and skipped heading levels (opt-in)",
+		Category:    "docs",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newHTMLA11yChecker() },
+	},
+	{
+		Name:        "go.mod",
+		Description: "go.mod module paths that don't match the repository URL, outdated go directives, missing go.sum",
+		Category:    "dependencies",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newGoModChecker() },
+	},
+	{
+		Name:        "case conflict",
+		Description: "paths that differ only by case and break checkouts on macOS and Windows",
+		Category:    "hygiene",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newCaseConflictChecker() },
+	},
+	{
+		Name:        "symlink",
+		Description: "symlinks that point outside of the repository or to missing paths",
+		Category:    "hygiene",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newSymlinkChecker() },
+	},
+	{
+		Name:        "large file",
+		Description: "committed archives, binaries and files larger than 5 MB",
+		Category:    "hygiene",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newLargeFileChecker() },
+	},
+	{
+		Name:        "proto",
+		Description: "protobuf files without syntax, package or go_package statements, or buf and protolint findings",
+		Category:    "build",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newProtoChecker() },
+	},
+	{
+		Name:        "dockerfile",
+		Description: "Dockerfiles with untagged or latest base images, unpinned packages and ADD instead of COPY, or hadolint findings (opt-in)",
+		Category:    "build",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newDockerfileChecker() },
+	},
+	{
+		Name:        "shellcheck",
+		Description: "shellcheck findings in *.sh files and scripts with a shell shebang (opt-in)",
+		Category:    "build",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newShellcheckChecker() },
+	},
+	{
+		Name:        "repo size",
+		Description: "repositories larger than 1 GB and the largest history blobs worth moving to Git LFS",
+		Category:    "hygiene",
+		Severity:    SeverityInfo,
+		New:         func() Checker { return newRepoSizeChecker() },
+	},
+	{
+		Name:        "secrets",
+		Description: "committed secrets, like AWS keys, private keys, GitHub tokens and passwords in URLs, and .env files",
+		Category:    "security",
+		Severity:    SeverityError,
+		New:         func() Checker { return newSecretsChecker() },
+	},
+	{
+		Name:        "secret history",
+		Description: "deleted sensitive files, like .env or private keys, that remain in git history (-history)",
+		Category:    "security",
+		Severity:    SeverityError,
+		New:         func() Checker { return newSecretHistoryChecker() },
+	},
+	{
+		Name:        "i18n",
+		Description: "translations with missing or extra keys and mismatched placeholders, in JSON locales and .po files",
+		Category:    "docs",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newI18nChecker() },
+	},
+	{
+		Name:        "migrations",
+		Description: "duplicate or out-of-order migration versions, missing down migrations and edited migrations (-history)",
+		Category:    "build",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newMigrationsChecker() },
+	},
+	{
+		Name:        "npm scripts",
+		Description: "README npm scripts missing from package.json and undocumented scripts (opt-in)",
+		Category:    "docs",
+		Severity:    SeverityWarning,
+		New:         func() Checker { return newNpmScriptsChecker() },
+	},
+}
+
+func init() {
+	sort.Slice(checkerRegistry, func(i, j int) bool {
+		return checkerRegistry[i].Name < checkerRegistry[j].Name
+	})
+	for i := range checkerRegistry {
+		info := &checkerRegistry[i]
+		info.DocURL = checkersDocURL + "#" + anchorSlug(info.Name)
+	}
+}
+
+// Checkers returns all built-in checkers sorted by name.
+func Checkers() []CheckerInfo {
+	return append([]CheckerInfo(nil), checkerRegistry...)
+}
+
+// lookupChecker returns a built-in checker info by its name.
+func lookupChecker(name string) (CheckerInfo, bool) {
+	for _, info := range checkerRegistry {
+		if info.Name == name {
+			return info, true
+		}
+	}
+	return CheckerInfo{}, false
+}
+
+// ListCheckers prints all built-in checkers.
+func ListCheckers(args []string) error {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSEVERITY\tCATEGORY\tDESCRIPTION")
+	for _, info := range checkerRegistry {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.Name, info.Severity, info.Category, info.Description)
+	}
+	fmt.Fprintf(tw, "\nSee %s for details.\n", checkersDocURL)
+	return tw.Flush()
+}
@@ -0,0 +1,97 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// bugReportsRE matches README requests to report bugs in the issue tracker.
+var bugReportsRE = regexp.MustCompile(`(?i)\b(?:report|file|open|submit|create|raise)\s+(?:an?\s+|the\s+)?(?:bug|issue)s?\b|` +
+	`(?:bug|issue)\s+(?:reports?|tracker)\b|/issues(?:/new)?\b`)
+
+// branchProtectionChecker is implemented by the checkers
+// that need the default branch protection status.
+// It costs an extra API call per repository.
+type branchProtectionChecker interface {
+	needsBranchProtection()
+}
+
+// repoMetadataChecker validates the repository settings that are not stored
+// in files: description, topics, license detected by github,
+// default branch protection and the issue tracker.
+type repoMetadataChecker struct {
+	CheckerBase
+	metadata *repoMetadata
+}
+
+func newRepoMetadataChecker() *repoMetadataChecker {
+	return &repoMetadataChecker{}
+}
+
+func (c *repoMetadataChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.metadata = nil
+}
+
+func (c *repoMetadataChecker) PushFile(f *File) {
+	// Manifests are used for the missing topics suggestions.
+	if (f.origName == f.baseName && isReadmeFile(f.baseName)) || isTopicHintFile(f) {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *repoMetadataChecker) setMetadata(m *repoMetadata) {
+	c.metadata = m
+}
+
+func (c *repoMetadataChecker) needsBranchProtection() {}
+
+// Results depend on the repository metadata.
+func (c *repoMetadataChecker) uncachedResults() {}
+
+func (c *repoMetadataChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	m := c.metadata
+	if m == nil {
+		return nil
+	}
+
+	if m.Description == "" {
+		warnings = append(warnings, "no description")
+	}
+	if len(m.Topics) == 0 {
+		if topics := suggestTopics(c.files); len(topics) != 0 {
+			warnings = append(warnings, fmt.Sprintf("no topics, consider adding: %s", strings.Join(topics, ", ")))
+		} else {
+			warnings = append(warnings, "no topics")
+		}
+	}
+	// Private repositories are often unlicensed on purpose.
+	if !m.Private {
+		switch m.License {
+		case "":
+			warnings = append(warnings, "github detects no license, add a LICENSE file")
+		case "NOASSERTION":
+			warnings = append(warnings, "github can't identify the license, use an unmodified standard license text")
+		}
+	}
+	if m.Protected != nil && !*m.Protected {
+		warnings = append(warnings, fmt.Sprintf("default branch %s is not protected", m.DefaultBranch))
+	}
+	if !m.HasIssues {
+		for _, f := range c.files {
+			if !isReadmeFile(f.baseName) {
+				continue
+			}
+			text := f.prose()
+			if loc := bugReportsRE.FindStringIndex(text); loc != nil {
+				w := fmt.Sprintf("%s:%d: issues are disabled, but README asks to report bugs",
+					f.origName, lineAt(text, loc[0]))
+				warnings = append(warnings, w)
+			}
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,218 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// runReport holds the results of a whole repolint run.
+type runReport struct {
+	User     string        `json:"user"`
+	Version  string        `json:"version"`
+	Started  time.Time     `json:"started"`
+	Finished time.Time     `json:"finished"`
+	Repos    []*repoReport `json:"repos"`
+
+	// Skipped is a list of repos that were not checked.
+	Skipped []string `json:"skipped,omitempty"`
+
+	// Filtered are the repos excluded by the repository list filters,
+	// like -skipForks or -min-stars.
+	Filtered []filteredRepo `json:"filtered,omitempty"`
+}
+
+// filteredRepo is a repository excluded from the run.
+type filteredRepo struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// repoReport holds the results of a single repository check.
+type repoReport struct {
+	Name string `json:"name"`
+
+	// Commit is a hash of the checked commit.
+	Commit string `json:"commit,omitempty"`
+
+	// Mirror is a canonical home URL declared by the README of a mirror repository.
+	Mirror string `json:"mirror,omitempty"`
+
+	// License is an SPDX identifier of the repository license detected by the license detection checker.
+	License string `json:"license,omitempty"`
+
+	// ContributionFriction is set when the contribution friction checker reports the repository.
+	ContributionFriction *frictionReport `json:"contribution_friction,omitempty"`
+
+	// Score is a 0-100 repository health score, see scoreModel.
+	Score int `json:"score"`
+
+	// Warnings use repo-relative paths with forward slashes.
+	Warnings []Warning `json:"warnings"`
+}
+
+// Warning is a single checker report.
+type Warning struct {
+	Checker  string   `json:"checker"`
+	Severity Severity `json:"severity"`
+	Text     string   `json:"text"`
+
+	// Fix is an automatic fix for the warning, if any.
+	Fix *Fix `json:"fix,omitempty"`
+}
+
+// runID returns a unique (per user) run identifier.
+func (r *runReport) runID() string {
+	return r.Started.UTC().Format("20060102T150405Z")
+}
+
+// addRepo appends a new repo entry to the report and returns it.
+func (r *runReport) addRepo(name string) *repoReport {
+	rr := &repoReport{Name: name}
+	r.Repos = append(r.Repos, rr)
+	return rr
+}
+
+func (r *runReport) writeJSON(filename string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if filename == "-" {
+		_, err := os.Stdout.Write(append(data, '\n'))
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>repolint: {{.User}}</title>
+<style>
+body { font-family: sans-serif; }
+td, th { padding: 2px 8px; text-align: left; vertical-align: top; }
+</style>
+</head>
+<body>
+<h1>{{.User}}</h1>
+<p>Started {{.Started.Format "2006-01-02 15:04:05 MST"}}, checked {{len .Repos}} repositories.</p>
+{{range .Repos}}{{if .Warnings}}
+<h2>{{.Name}}{{if .Commit}} <small>{{.Commit}}</small>{{end}}</h2>
+<p>Score {{.Score}}</p>
+<table>
+<tr><th>Severity</th><th>Checker</th><th>Warning</th></tr>
+{{range .Warnings}}<tr><td>{{.Severity}}</td><td>{{.Checker}}</td><td>{{.Text}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}
+{{if .Skipped}}<h2>Skipped</h2>
+<ul>{{range .Skipped}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{if .Filtered}}<h2>Filtered</h2>
+<ul>{{range .Filtered}}<li>{{.Name}}: {{.Reason}}</li>{{end}}</ul>{{end}}
+</body>
+</html>
+`))
+
+func (r *runReport) writeHTML(filename string) error {
+	return writeTemplate(filename, reportTemplate, r)
+}
+
+// writeReport saves run results in all requested formats.
+func (l *Runner) writeReport() error {
+	l.results.Finished = time.Now()
+	l.results.Skipped = l.overBudget
+	if l.jsonReport != "" {
+		if err := l.results.writeJSON(l.jsonReport); err != nil {
+			return err
+		}
+		if err := l.signReport(l.jsonReport); err != nil {
+			return err
+		}
+	}
+	if l.htmlReport != "" {
+		if err := l.results.writeHTML(l.htmlReport); err != nil {
+			return err
+		}
+		if err := l.signReport(l.htmlReport); err != nil {
+			return err
+		}
+	}
+	if l.dashboardDir != "" {
+		if err := l.results.writeDashboard(l.dashboardDir); err != nil {
+			return err
+		}
+	}
+	if err := l.writeSBOM(); err != nil {
+		return err
+	}
+	if l.sbomFile != "" {
+		if err := l.signReport(l.sbomFile); err != nil {
+			return err
+		}
+	}
+	if l.publishURL != "" {
+		return l.publishReport(l.publishURL)
+	}
+	return nil
+}
+
+// publishReport uploads JSON and HTML reports to an object storage.
+//
+// dst is a bucket URL, like s3://bucket/prefix or gs://bucket/prefix.
+// Reports are stored under <prefix>/<user>/<run id>/ key.
+func (l *Runner) publishReport(dst string) error {
+	var tool []string
+	switch {
+	case strings.HasPrefix(dst, "s3://"):
+		tool = []string{"aws", "s3", "cp"}
+	case strings.HasPrefix(dst, "gs://"):
+		tool = []string{"gsutil", "-q", "cp"}
+	default:
+		return fmt.Errorf("unsupported publish destination %q: expected s3:// or gs:// URL", dst)
+	}
+	if _, err := exec.LookPath(tool[0]); err != nil {
+		return fmt.Errorf("publish to %s requires %s: %v", dst, tool[0], err)
+	}
+
+	dir := filepath.Join(l.tempDir, "report")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	files := map[string]func(string) error{
+		"results.json": l.results.writeJSON,
+		"results.html": l.results.writeHTML,
+	}
+	for name, write := range files {
+		filename := filepath.Join(dir, name)
+		if err := write(filename); err != nil {
+			return fmt.Errorf("write %s: %v", name, err)
+		}
+		uploads := []string{name}
+		if l.signKey != nil {
+			if err := l.signReport(filename); err != nil {
+				return fmt.Errorf("sign %s: %v", name, err)
+			}
+			uploads = append(uploads, name+".sig")
+		}
+		for _, upload := range uploads {
+			key := path.Join(l.results.User, l.results.runID(), upload)
+			url := strings.TrimSuffix(dst, "/") + "/" + key
+			args := append(append([]string{}, tool[1:]...), filepath.Join(dir, upload), url)
+			out, err := exec.CommandContext(l.ctx, tool[0], args...).CombinedOutput()
+			if err != nil {
+				return fmt.Errorf("upload %s: %v: %s", url, err, out)
+			}
+			log.Printf("\tpublished %s", url)
+		}
+	}
+	return nil
+}
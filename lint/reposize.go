@@ -0,0 +1,188 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// repoSizeConfig overrides the repo size checker thresholds.
+type repoSizeConfig struct {
+	// MaxSizeMB is a repository size that is reported.
+	MaxSizeMB int64 `yaml:"max_size_mb"`
+
+	// MaxBlobMB is a history blob size that is reported.
+	MaxBlobMB int64 `yaml:"max_blob_mb"`
+
+	// Top is a number of the largest blobs that are reported.
+	Top int `yaml:"top"`
+
+	// History enables the history blobs scan in clone mode.
+	// History clones include the old file contents then, so they're much bigger.
+	History bool `yaml:"history"`
+}
+
+const (
+	defaultMaxRepoSizeMB = 1024
+	defaultMaxBlobMB     = 50
+	defaultTopBlobs      = 5
+)
+
+// historyBlob is a file version stored in the repository history.
+type historyBlob struct {
+	path string
+	size int64
+}
+
+// repoSizeChecker finds repositories that exceed the size thresholds
+// and the largest blobs in their history, which are worth moving to Git LFS.
+//
+// The repository size comes from the github API. History blobs are only
+// scanned in clone mode with the repo_size.history config option.
+type repoSizeChecker struct {
+	CheckerBase
+
+	maxSize int64
+	maxBlob int64
+	top     int
+	history bool
+
+	// size is a repository size in bytes, zero if unknown.
+	size int64
+
+	// rootDir is a repository checkout directory.
+	rootDir string
+}
+
+func newRepoSizeChecker() *repoSizeChecker {
+	return &repoSizeChecker{
+		maxSize: defaultMaxRepoSizeMB << 20,
+		maxBlob: defaultMaxBlobMB << 20,
+		top:     defaultTopBlobs,
+	}
+}
+
+func (c *repoSizeChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.size = 0
+	c.rootDir = ""
+}
+
+func (c *repoSizeChecker) PushFile(f *File) {
+	if c.history && f.rootDir != "" {
+		c.rootDir = f.rootDir
+	}
+}
+
+func (c *repoSizeChecker) setMetadata(m *repoMetadata) {
+	c.size = int64(m.Size) << 10
+}
+
+// Results depend on the repository metadata and history.
+func (c *repoSizeChecker) uncachedResults() {}
+
+func (c *repoSizeChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	var blobs []historyBlob
+	if c.rootDir != "" {
+		blobs = c.largestBlobs(ctx)
+	}
+
+	if c.size > c.maxSize {
+		w := fmt.Sprintf("repository size is %s, exceeds %s", formatSize(c.size), formatSize(c.maxSize))
+		if len(blobs) != 0 {
+			var offenders []string
+			for _, b := range blobs {
+				offenders = append(offenders, fmt.Sprintf("%s (%s)", b.path, formatSize(b.size)))
+			}
+			w += ", largest blobs: " + strings.Join(offenders, ", ")
+		}
+		warnings = append(warnings, w)
+	}
+	for _, b := range blobs {
+		if b.size > c.maxBlob {
+			w := fmt.Sprintf("%s: %s blob in history exceeds %s, consider moving it to Git LFS",
+				b.path, formatSize(b.size), formatSize(c.maxBlob))
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// largestBlobs returns c.top largest history blobs, one per path,
+// sorted by their size in descending order.
+//
+// History is only scanned in clone mode, which needs git anyway, so the
+// checker is not an external one: container mode keeps the API size check.
+func (c *repoSizeChecker) largestBlobs(ctx context.Context) []historyBlob {
+	objects, err := gitOutput(ctx, c.rootDir, "rev-list", "--objects", "HEAD")
+	if err != nil {
+		return nil
+	}
+	sizes, err := gitOutput(ctx, c.rootDir, "cat-file", "--batch-all-objects",
+		"--batch-check=%(objecttype) %(objectname) %(objectsize)")
+	if err != nil {
+		return nil
+	}
+
+	blobSizes := make(map[string]int64)
+	for _, line := range strings.Split(sizes, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || fields[0] != "blob" {
+			continue
+		}
+		if size, err := strconv.ParseInt(fields[2], 10, 64); err == nil {
+			blobSizes[fields[1]] = size
+		}
+	}
+	largest := make(map[string]int64)
+	for _, line := range strings.Split(objects, "\n") {
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		if size, ok := blobSizes[fields[0]]; ok && size > largest[fields[1]] {
+			largest[fields[1]] = size
+		}
+	}
+
+	blobs := make([]historyBlob, 0, len(largest))
+	for p, size := range largest {
+		blobs = append(blobs, historyBlob{path: p, size: size})
+	}
+	sort.Slice(blobs, func(i, j int) bool {
+		if blobs[i].size != blobs[j].size {
+			return blobs[i].size > blobs[j].size
+		}
+		return blobs[i].path < blobs[j].path
+	})
+	if len(blobs) > c.top {
+		blobs = blobs[:c.top]
+	}
+	return blobs
+}
+
+// megabytes converts a size in megabytes into bytes.
+// Sizes that don't fit into int64 are clamped, instead of overflowing into negative values.
+func megabytes(mb int64) int64 {
+	if mb > math.MaxInt64>>20 {
+		return math.MaxInt64
+	}
+	return mb << 20
+}
+
+// formatSize formats a size in bytes, like "1.5 GB" or "120.0 MB".
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(size)/(1<<30))
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%d KB", size>>10)
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
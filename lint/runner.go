@@ -0,0 +1,1529 @@
+// Package lint finds common issues in github repositories,
+// like broken links, typos and unwanted files.
+//
+// Runner fetches repository files and runs Checker implementations over them.
+// It can also check files that are already available locally, see Runner.CheckFiles.
+package lint
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/go-github/github"
+)
+
+// NewRunner returns a Runner with all built-in checkers.
+func NewRunner() *Runner {
+	l := &Runner{
+		checkers: make(map[string]Checker, len(checkerRegistry)),
+		scoring:  defaultScoreModel,
+	}
+	for _, info := range checkerRegistry {
+		l.checkers[info.Name] = info.New()
+	}
+	return l
+}
+
+// AddChecker registers a custom checker under the given name.
+// Config file severity overrides apply to it like to the built-in checkers.
+func (l *Runner) AddChecker(name string, c Checker, s Severity) {
+	if l.customSeverities == nil {
+		l.customSeverities = make(map[string]Severity)
+	}
+	l.checkers[name] = c
+	l.customSeverities[name] = s
+}
+
+// CheckFiles runs all checkers over files of a single repository.
+//
+// Nothing is downloaded, so files are usually created with NewFile.
+// Checkers that need the github API, like description, report nothing.
+func (l *Runner) CheckFiles(ctx context.Context, files []*File) []Warning {
+	l.ctx = ctx
+	markGenerated(files)
+	for _, c := range l.checkers {
+		c.Reset()
+		l.pushFiles(c, files)
+	}
+	var warnings []Warning
+	names := l.checkerNames()
+	results := l.runCheckers(names)
+	for i, name := range names {
+		warnings = append(warnings, l.checkerWarnings(name, results[i])...)
+	}
+	return warnings
+}
+
+// StreamFiles is like CheckFiles, but passes the warnings to fn as soon as
+// their checker finishes, so the checkers order is not defined.
+// fn is never called concurrently.
+//
+// If fn returns an error, the remaining checkers are canceled
+// and StreamFiles returns that error. Otherwise, it returns ctx.Err().
+func (l *Runner) StreamFiles(ctx context.Context, files []*File, fn func(Warning) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	l.ctx = ctx
+	markGenerated(files)
+	for _, c := range l.checkers {
+		c.Reset()
+		l.pushFiles(c, files)
+	}
+	names := l.checkerNames()
+	var fnErr error
+	for r := range l.startCheckers(names) {
+		if fnErr != nil {
+			// Wait for the canceled checkers.
+			continue
+		}
+		for _, w := range l.checkerWarnings(names[r.index], r.checkerResult) {
+			if fnErr = fn(w); fnErr != nil {
+				cancel()
+				break
+			}
+		}
+	}
+	if fnErr != nil {
+		return fnErr
+	}
+	return ctx.Err()
+}
+
+// checkerWarnings converts the checker results of CheckFiles into warnings.
+func (l *Runner) checkerWarnings(name string, r checkerResult) []Warning {
+	s, ok := l.severities[name]
+	if !ok {
+		s = l.defaultSeverity(name)
+	}
+	warnings := make([]Warning, 0, len(r.warnings))
+	for j, text := range r.warnings {
+		w := Warning{Checker: name, Severity: s, Text: text, Fix: fixAt(l.checkers[name], j)}
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
+
+// Finding is a warning of a checked repository, see RunStream.
+type Finding struct {
+	Repo string
+	Warning
+}
+
+// RunStream is like Run, but also passes the findings to fn as soon as
+// their repository is checked, so embedding services can process and persist
+// the results of huge scans incrementally. fn is never called concurrently.
+//
+// Canceling ctx stops the run like an interrupt signal does.
+// If fn returns an error, the run stops and RunStream returns that error.
+// The reports requested with the flags, like -json, are still written.
+func (l *Runner) RunStream(ctx context.Context, args []string, fn func(Finding) error) error {
+	l.parentCtx = ctx
+	l.onFinding = fn
+	err := l.Run(args)
+	if l.findingErr != nil {
+		return l.findingErr
+	}
+	return err
+}
+
+// emitFinding passes a finding to the RunStream callback.
+// The first callback error cancels the run.
+func (l *Runner) emitFinding(repo string, w Warning) {
+	if l.onFinding == nil || l.findingErr != nil {
+		return
+	}
+	if err := l.onFinding(Finding{Repo: repo, Warning: w}); err != nil {
+		l.findingErr = err
+		l.cancel()
+	}
+}
+
+// Run lints repositories according to the command-line args.
+func (l *Runner) Run(args []string) error {
+	l.args = args
+
+	defer l.cleanup()
+	steps := []struct {
+		name string
+		fn   func() error
+	}{
+		{"init temp dir", l.initTempDir},
+		{"parse flags", l.parseFlags},
+		{"init context", l.initContext},
+		{"load config", l.loadConfig},
+		{"load signing key", l.loadSignKey},
+		{"load plugins", l.loadPlugins},
+		{"init container mode", l.initContainerMode},
+		{"init local mode", l.initLocalMode},
+		{"init anonymous mode", l.initAnonymousMode},
+		{"configure checkers", l.configureCheckers},
+		{"init severities", l.initSeverities},
+		{"init scoring", l.initScoring},
+		{"read token", l.readToken},
+		{"init client", l.initClient},
+		{"check anonymous rate limit", l.checkAnonymousRateLimit},
+		{"init fetcher", l.initFetcher},
+		{"load template", l.loadTemplate},
+		{"load baseline", l.loadBaseline},
+		{"open suppressions db", l.openSuppressionDB},
+		{"load result cache", l.loadResultCache},
+		{"load link cache", l.loadLinkCache},
+		{"get repos list", l.getReposList},
+		{"collect changed files", l.initDiff},
+		{"lint repos", l.lintRepos},
+		{"write report", l.writeReport},
+		{"send email digest", l.sendDigest},
+		{"write baseline", l.writeBaseline},
+		{"save result cache", l.saveResultCache},
+		{"save link cache", l.saveLinkCache},
+		{"write action outputs", l.writeActionOutputs},
+		{"check for updates", l.checkForUpdates},
+		{"check interrupted", l.checkInterrupted},
+		{"check warnings limit", l.checkWarningsLimit},
+		{"check min score", l.checkMinScore},
+	}
+	for _, step := range steps {
+		if err := step.fn(); err != nil {
+			return fmt.Errorf("%s: %v", step.name, err)
+		}
+	}
+	return nil
+}
+
+// Runner checks repositories with a set of checkers.
+type Runner struct {
+	// args are command-line arguments without the program name.
+	args  []string
+	flags *flag.FlagSet
+
+	user   string
+	repo   string
+	tokens *tokenPool
+	repos  []string
+
+	// diff and pr restrict checks to the files changed
+	// in commits range or a pull request.
+	diff string
+	pr   int
+
+	// ref is a git ref to be checked.
+	// Empty ref means the default one.
+	ref string
+
+	// dir is a local directory that is checked instead of github repositories.
+	dir string
+
+	// fix enables writing checker fixes to the dir files.
+	// With dryRun, fixes are printed as a unified diff instead.
+	fix    bool
+	dryRun bool
+
+	// onlyFiles is a set of file names that should be checked.
+	// If nil, all files are checked.
+	onlyFiles map[string]bool
+
+	// tree indexes all files of the checked repository.
+	// It's nil if the file listing is incomplete, see truncatedTree.
+	tree *repoTree
+
+	// truncatedTree is set by the fetcher when github
+	// returns only a part of the repository tree.
+	truncatedTree bool
+
+	// includeGenerated disables skipping linguist-generated and
+	// linguist-vendored files in the generatedSkipper checkers.
+	includeGenerated bool
+
+	// ctx is canceled on interrupt or when -timeout expires.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// parentCtx is the RunStream caller context, nil for Run.
+	parentCtx context.Context
+
+	// onFinding is the RunStream callback, findingErr is its first error.
+	onFinding  func(Finding) error
+	findingErr error
+	client     *github.Client
+
+	timeout        time.Duration
+	checkerTimeout time.Duration
+
+	// rawClient is used for non-API downloads, see rawURL.
+	rawClient *http.Client
+
+	// archiveClient downloads repository tarballs, it never sends tokens,
+	// since the archive links are already signed.
+	archiveClient *http.Client
+
+	// webURL, apiURL and rawURL override github.com endpoints.
+	// Used for GitHub Enterprise installs and caching proxies.
+	webURL         string
+	apiURL         string
+	rawURL         string
+	proxyHeaders   stringList
+	proxyPassToken bool
+
+	verbose      bool
+	skipForks    bool
+	skipInactive bool
+	skipVendor   bool
+	offset       int
+	fetchMode    string
+	maxAPICalls  int
+	container    bool
+	anonymous    bool
+
+	// pushedSince and minStars filter out the repositories
+	// with older latest pushes and fewer stars.
+	pushedSince     string
+	pushedSinceTime time.Time
+	minStars        int
+
+	// concurrency limits parallel file downloads,
+	// hostConcurrency limits parallel requests to a single host.
+	concurrency     int
+	hostConcurrency int
+
+	// action is set when repolint runs as a GitHub Action.
+	action bool
+
+	setExitStatus bool
+	maxWarnings   int
+	updateCheck   bool
+
+	// scoring computes the repository health scores,
+	// minScore is a minimal score required to pass, 0 disables the check.
+	scoring  *scoreModel
+	minScore int
+
+	// softFailNetwork makes network checker warnings informational,
+	// so they don't affect the exit status.
+	softFailNetwork bool
+
+	// requests is a number of API calls made so far,
+	// it's updated by concurrent downloads and checkers, so requestsMu guards it.
+	requestsMu sync.Mutex
+	requests   int
+
+	// overBudget is a list of repos that were skipped
+	// because of the maxAPICalls limit.
+	overBudget []string
+
+	fetcher repoFetcher
+
+	checkers map[string]Checker
+
+	// customSeverities are default severities of the checkers added with AddChecker.
+	customSeverities map[string]Severity
+
+	linkTimeout     time.Duration
+	linkConcurrency int
+	linkExclude     string
+
+	// staleDocsYears enables the stale docs checker.
+	staleDocsYears int
+
+	// history enables the history checks of the secret history
+	// and migrations checkers.
+	history bool
+
+	// retries is how many times a failed download or link check is repeated.
+	retries    int
+	retryDelay time.Duration
+
+	severities      map[string]Severity
+	minSeverity     Severity
+	minSeverityName string
+
+	configFile string
+	config     config
+
+	// lang is a language of the printed warnings and help,
+	// messages translate them.
+	lang     string
+	messages *messageCatalog
+	exclude  string
+	excluder *pathMatcher
+
+	results    runReport
+	jsonReport string
+	htmlReport string
+	publishURL string
+
+	// dashboardDir is a static HTML dashboard output directory.
+	dashboardDir string
+
+	// signKeyFile is an Ed25519 private key that signs the reports.
+	signKeyFile string
+	signKey     ed25519.PrivateKey
+
+	// sbomFile is a CycloneDX output file name.
+	sbomFile string
+	sbom     []sbomComponent
+
+	baselineFile   string
+	baselineCreate string
+	baseline       *baseline
+	suppressed     int
+
+	resultCacheFile string
+	resultCache     *resultCache
+
+	linkCacheFile string
+	linkCacheTTL  time.Duration
+	linkCache     *linkCache
+
+	// noCache disables all caches.
+	noCache bool
+
+	suppressDBFile string
+	suppressAdd    bool
+	suppressions   *suppressionDB
+
+	tempDir string
+}
+
+func (l *Runner) cleanup() {
+	if l.cancel != nil {
+		l.cancel()
+	}
+	if l.suppressions != nil {
+		if err := l.suppressions.Close(); err != nil {
+			log.Printf("cleanup before exit: %v", err)
+		}
+	}
+	err := removeAll(l.tempDir)
+	if err != nil {
+		log.Printf("cleanup before exit: %v", err)
+	}
+}
+
+// initContext makes l.ctx that is canceled on the first interrupt signal.
+// Second signal terminates the program immediately.
+func (l *Runner) initContext() error {
+	parent := l.parentCtx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+	cancel := stop
+	if l.timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, l.timeout)
+		cancel = func() {
+			cancelTimeout()
+			stop()
+		}
+	}
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	l.ctx = ctx
+	l.cancel = cancel
+	return nil
+}
+
+// checkInterrupted reports whether the run was interrupted,
+// so not all repositories were checked.
+func (l *Runner) checkInterrupted() error {
+	return l.ctx.Err()
+}
+
+func (l *Runner) initTempDir() error {
+	tempDir, err := ioutil.TempDir("", "repolint")
+	if err != nil {
+		return err
+	}
+	// External programs report resolved paths, like /private/var
+	// instead of /var on macOS, see localPathsReplacer.
+	l.tempDir = realPath(tempDir)
+	return nil
+}
+
+func (l *Runner) parseFlags() error {
+	fs := flag.NewFlagSet("repolint", flag.ExitOnError)
+	l.flags = fs
+	fs.StringVar(&l.user, "user", "",
+		`github user/organization name`)
+	fs.StringVar(&l.repo, "repo", "",
+		`check only this repository instead of all user/organization repositories`)
+	fs.StringVar(&l.dir, "dir", "",
+		`check a local directory instead of github repositories; github token is not needed`)
+	fs.BoolVar(&l.anonymous, "anonymous", false,
+		`check a public -repo without a token; limited to 60 github API calls per hour`)
+	fs.BoolVar(&l.fix, "fix", false,
+		`apply safe fixes, like acronyms capitalization and unwanted files removal; requires -dir`)
+	fs.BoolVar(&l.dryRun, "dry-run", false,
+		`with -fix, print a unified diff of the fixes to stdout instead of changing files`)
+	fs.StringVar(&l.diff, "diff", "",
+		`check only files changed in base..head commits range; requires -repo`)
+	fs.IntVar(&l.pr, "pr", 0,
+		`check only files changed in the specified pull request; requires -repo`)
+	fs.BoolVar(&l.verbose, "v", false,
+		`verbose mode that turns on additional debug output`)
+	fs.BoolVar(&l.skipForks, "skipForks", true,
+		`whether to skip repositories that are forks`)
+	fs.BoolVar(&l.skipInactive, "skipInactive", true,
+		`whether to skip repositories with latest push dated more than 1 year ago`)
+	fs.StringVar(&l.pushedSince, "pushed-since", "",
+		`skip repositories with latest push dated before YYYY-MM-DD`)
+	fs.IntVar(&l.minStars, "min-stars", 0,
+		`skip repositories with fewer stars`)
+	fs.BoolVar(&l.skipVendor, "skipVendor", true,
+		`whether to skip vendor folders and their contents`)
+	fs.BoolVar(&l.includeGenerated, "include-generated", false,
+		`whether to check files marked as linguist-generated or linguist-vendored in .gitattributes with all checkers`)
+	fs.BoolVar(&l.container, "container", false,
+		`run without external programs and print JSON results to stdout`)
+	fs.BoolVar(&l.setExitStatus, "set-exit-status", false,
+		`exit with non-zero status if any warnings are reported`)
+	fs.BoolVar(&l.softFailNetwork, "soft-fail-network", false,
+		`report network-dependent checkers (like broken link and description) as info that doesn't affect the exit status`)
+	fs.IntVar(&l.minScore, "min-score", 0,
+		`exit with non-zero status if any repository health score is below this value, 0 disables the check`)
+	fs.IntVar(&l.maxWarnings, "max-warnings", -1,
+		`exit with non-zero status if more than N warnings are reported (-1 means no limit)`)
+	fs.BoolVar(&l.updateCheck, "update-check", true,
+		`whether to print a notice when a new repolint version is available`)
+	fs.StringVar(&l.minSeverityName, "min-severity", "info",
+		`don't report warnings below this severity: info, warning or error`)
+	fs.DurationVar(&l.linkTimeout, "link-timeout", 30*time.Second,
+		`broken link checker timeout for a single link`)
+	fs.IntVar(&l.linkConcurrency, "link-concurrency", 8,
+		`how many links are checked concurrently`)
+	fs.StringVar(&l.linkExclude, "link-exclude", defaultLinkExclude,
+		`regexp for web links that should not be checked (empty means check all links)`)
+	fs.IntVar(&l.retries, "retries", 2,
+		`how many times to retry file downloads and link checks after a network error or 502, 503 and 504 responses`)
+	fs.DurationVar(&l.retryDelay, "retry-delay", time.Second,
+		`delay before the first retry; it doubles after every attempt`)
+	fs.IntVar(&l.staleDocsYears, "stale-docs-years", 0,
+		`report README and CHANGELOG files not updated for N years while the code keeps changing; requires -fetch=clone (0 disables)`)
+	fs.BoolVar(&l.history, "history", false,
+		`report deleted sensitive files, like .env or private keys, that remain in git history, and edited migrations; requires -fetch=clone`)
+	fs.StringVar(&l.configFile, "config", "",
+		`YAML configuration file`)
+	fs.StringVar(&l.exclude, "exclude", "",
+		`comma-separated list of path patterns to skip, like 'vendor/**,third_party/**'`)
+	fs.IntVar(&l.offset, "offset", 0,
+		`how many repositories to skip`)
+	fs.StringVar(&l.fetchMode, "fetch", "api",
+		`how to fetch repository files: api (download files one by one), clone (shallow git clone) or tarball (download a repository archive)`)
+	fs.DurationVar(&l.timeout, "timeout", 0,
+		`overall run time limit; in-flight requests are canceled and partial results are reported (0 means no limit)`)
+	fs.DurationVar(&l.checkerTimeout, "checker-timeout", 0,
+		`max time a single checker can spend on a repository (0 means no limit)`)
+	fs.IntVar(&l.concurrency, "concurrency", 8,
+		`how many repository files are fetched concurrently`)
+	fs.IntVar(&l.hostConcurrency, "host-concurrency", 4,
+		`max number of concurrent requests to a single host`)
+	fs.IntVar(&l.maxAPICalls, "max-api-calls", 0,
+		`max number of github API calls per run; repos that don't fit are skipped (0 means unlimited)`)
+	fs.StringVar(&l.webURL, "github-url", "https://github.com",
+		`github web URL, used to clone repositories`)
+	fs.StringVar(&l.apiURL, "github-api-url", "",
+		`github API base URL override, like https://ghe.example.com/api/v3/; can point to a caching proxy`)
+	fs.StringVar(&l.rawURL, "github-raw-url", "",
+		`raw file download URL template, like https://ghe.example.com/raw/{owner}/{repo}/{ref}/{path}; if set, files are not downloaded via API`)
+	fs.Var(&l.proxyHeaders, "proxy-header",
+		`extra "Name: value" header sent with every request; can be repeated`)
+	fs.BoolVar(&l.proxyPassToken, "proxy-pass-token", true,
+		`whether to send github token to the -github-api-url and -github-raw-url hosts`)
+	fs.StringVar(&l.jsonReport, "json", "",
+		`write results as JSON to the specified file ("-" for stdout)`)
+	fs.StringVar(&l.htmlReport, "html", "",
+		`write results as HTML to the specified file`)
+	fs.StringVar(&l.dashboardDir, "dashboard", "",
+		`write a static HTML dashboard with per-repository pages into the specified directory`)
+	fs.StringVar(&l.sbomFile, "sbom", "",
+		`write detected licenses and dependency manifests as CycloneDX JSON to the specified file ("-" for stdout)`)
+	fs.StringVar(&l.publishURL, "publish", "",
+		`upload JSON and HTML results to s3://bucket/prefix or gs://bucket/prefix`)
+	fs.StringVar(&l.signKeyFile, "sign-key", "",
+		`Ed25519 private key PEM file; JSON, HTML and SBOM reports get detached .sig signatures`)
+	fs.StringVar(&l.baselineFile, "baseline", "",
+		`baseline file with known warnings that should not be reported`)
+	fs.StringVar(&l.baselineCreate, "baseline-create", "",
+		`write all reported warnings into the specified baseline file`)
+	fs.StringVar(&l.linkCacheFile, "link-cache", "",
+		`file to cache web link check results (default is repolint/links.json in the user cache dir)`)
+	fs.DurationVar(&l.linkCacheTTL, "link-cache-ttl", 24*time.Hour,
+		`how long cached link check results are valid`)
+	fs.BoolVar(&l.noCache, "no-cache", false,
+		`don't read or write any caches, including -result-cache and -link-cache`)
+	fs.StringVar(&l.resultCacheFile, "result-cache", "",
+		`file to cache per-file checker results, so unchanged files are not checked again`)
+	fs.StringVar(&l.suppressDBFile, "suppress-db", "",
+		`SQLite database with accepted false positives shared across repositories`)
+	fs.BoolVar(&l.suppressAdd, "suppress-db-add", false,
+		`record all reported warnings as accepted false positives into -suppress-db`)
+	fs.StringVar(&l.lang, "lang", "",
+		`language of warnings and help: en or ru (default is detected from LC_ALL, LC_MESSAGES and LANG)`)
+	fs.Usage = func() { l.printUsage(fs) }
+
+	if err := applyEnvFlags(fs); err != nil {
+		return err
+	}
+	if l.action {
+		if err := applyActionInputs(fs); err != nil {
+			return err
+		}
+	}
+	if err := fs.Parse(l.args); err != nil {
+		return err
+	}
+
+	if l.lang == "" {
+		l.lang = detectLanguage(os.Getenv)
+	}
+	messages, err := newMessageCatalog(l.lang)
+	if err != nil {
+		return fmt.Errorf("-lang: %v", err)
+	}
+	l.messages = messages
+
+	if l.user == "" && l.dir == "" {
+		return errors.New("-user argument can't be empty")
+	}
+	if l.pushedSince != "" {
+		t, err := time.Parse("2006-01-02", l.pushedSince)
+		if err != nil {
+			return fmt.Errorf("-pushed-since: expected YYYY-MM-DD date: %v", err)
+		}
+		l.pushedSinceTime = t
+	}
+	l.results.User = l.user
+	l.results.Version = Version
+	l.results.Started = time.Now()
+
+	return nil
+}
+
+// printUsage prints the flags help in the -lang language,
+// if it's already parsed, or in the locale language.
+func (l *Runner) printUsage(fs *flag.FlagSet) {
+	lang := l.lang
+	if lang == "" {
+		lang = detectLanguage(os.Getenv)
+	}
+	// Unsupported languages are reported after the flags are parsed.
+	messages, _ := newMessageCatalog(lang)
+	fmt.Fprintf(fs.Output(), messages.Text("Usage of %s:\n"), fs.Name())
+	fs.VisitAll(func(f *flag.Flag) {
+		f.Usage = messages.Text(f.Usage)
+	})
+	fs.PrintDefaults()
+}
+
+func (l *Runner) checkWarningsLimit() error {
+	n := 0
+	for _, rr := range l.results.Repos {
+		for _, w := range rr.Warnings {
+			if l.softFailNetwork && isNetworkChecker(l.checkers[w.Checker]) {
+				continue
+			}
+			n++
+		}
+	}
+	if l.setExitStatus && n != 0 {
+		return fmt.Errorf("found %d warnings", n)
+	}
+	if l.maxWarnings >= 0 && n > l.maxWarnings {
+		return fmt.Errorf("found %d warnings, while at most %d are allowed", n, l.maxWarnings)
+	}
+	return nil
+}
+
+// configureCheckers applies command-line options to the checkers.
+func (l *Runner) configureCheckers() error {
+	if l.linkConcurrency < 1 {
+		return errors.New("-link-concurrency should be positive")
+	}
+	if l.retries < 0 {
+		return errors.New("-retries should not be negative")
+	}
+	if l.staleDocsYears != 0 && l.fetchMode != "clone" && l.dir == "" {
+		return errors.New("-stale-docs-years requires -fetch=clone or -dir")
+	}
+	if l.history && l.fetchMode != "clone" && l.dir == "" {
+		return errors.New("-history requires -fetch=clone or -dir")
+	}
+	if cfg := l.config.RepoSize; cfg != nil && cfg.History && l.fetchMode != "clone" {
+		return errors.New("config: repo_size: history requires -fetch=clone")
+	}
+	if l.config.LinkTimeout != 0 && !l.isFlagSet("link-timeout") {
+		l.linkTimeout = l.config.LinkTimeout
+	}
+	if len(l.config.LinkExclude) != 0 && !l.isFlagSet("link-exclude") {
+		l.linkExclude = strings.Join(l.config.LinkExclude, "|")
+	}
+	var linkExcludeRE *regexp.Regexp
+	if l.linkExclude != "" {
+		re, err := regexp.Compile(l.linkExclude)
+		if err != nil {
+			return fmt.Errorf("bad link exclude pattern: %v", err)
+		}
+		linkExcludeRE = re
+	}
+
+	var communityFiles []communityFile
+	for _, name := range l.config.CommunityFiles {
+		cf, ok := lookupCommunityFile(name)
+		if !ok {
+			return fmt.Errorf("config: community_files: unknown file %q", name)
+		}
+		communityFiles = append(communityFiles, cf)
+	}
+	pinningPolicy := ""
+	if cfg := l.config.Pinning; cfg != nil {
+		switch cfg.Policy {
+		case "", "loose":
+			pinningPolicy = "loose"
+		case "strict":
+			pinningPolicy = "strict"
+		default:
+			return fmt.Errorf("config: pinning: unknown policy %q, expected loose or strict", cfg.Policy)
+		}
+	}
+	var templateKinds []string
+	if cfg := l.config.IssueTemplates; cfg != nil && cfg.Required != nil {
+		templateKinds = []string{}
+		for _, kind := range cfg.Required {
+			if !containsString(issueTemplateKinds, kind) {
+				return fmt.Errorf("config: issue_templates: unknown template kind %q, expected issue or pull_request", kind)
+			}
+			templateKinds = append(templateKinds, kind)
+		}
+	}
+	var shellcheckExclude []string
+	if cfg := l.config.Shellcheck; cfg != nil {
+		if cfg.Severity != "" && !containsString(shellcheckSeverities, cfg.Severity) {
+			return fmt.Errorf("config: shellcheck: unknown severity %q, expected one of: %s",
+				cfg.Severity, strings.Join(shellcheckSeverities, ", "))
+		}
+		codes, err := shellcheckCodes(cfg.Exclude)
+		if err != nil {
+			return fmt.Errorf("config: shellcheck: exclude: %v", err)
+		}
+		shellcheckExclude = codes
+	}
+	var a11yRules map[string]bool
+	if cfg := l.config.HTMLAccessibility; cfg != nil {
+		rules := cfg.Rules
+		if rules == nil {
+			rules = htmlA11yRules
+		}
+		a11yRules = make(map[string]bool)
+		for _, rule := range rules {
+			if !isKnownA11yRule(rule) {
+				return fmt.Errorf("config: html_accessibility: unknown rule %q", rule)
+			}
+			a11yRules[rule] = true
+		}
+	}
+
+	for _, c := range l.checkers {
+		switch c := c.(type) {
+		case *brokenLinkChecker:
+			c.timeout = l.linkTimeout
+			c.concurrency = l.linkConcurrency
+			c.excludeRE = linkExcludeRE
+			c.client = &http.Client{Transport: l.retryTransport(http.DefaultTransport)}
+		case *badgeChecker:
+			c.links.timeout = l.linkTimeout
+			c.links.concurrency = l.linkConcurrency
+			c.links.client = &http.Client{Transport: l.retryTransport(http.DefaultTransport)}
+		case *insecureLinkChecker:
+			c.links.timeout = l.linkTimeout
+			c.links.concurrency = l.linkConcurrency
+			c.links.excludeRE = linkExcludeRE
+			c.links.client = &http.Client{Transport: l.retryTransport(http.DefaultTransport)}
+		case *pinningChecker:
+			c.policy = pinningPolicy
+		case *npmScriptsChecker:
+			if cfg := l.config.NpmScripts; cfg != nil {
+				c.significant = append([]string{}, defaultSignificantScripts...)
+				if cfg.Significant != nil {
+					c.significant = append([]string{}, cfg.Significant...)
+				}
+			}
+		case *issueTemplatesChecker:
+			if templateKinds != nil {
+				c.required = templateKinds
+			}
+		case *todoCommentChecker:
+			if cfg := l.config.TODO; cfg != nil {
+				c.enabled = true
+				c.source = cfg.Source
+			}
+		case *shellcheckChecker:
+			if cfg := l.config.Shellcheck; cfg != nil {
+				if _, err := exec.LookPath("shellcheck"); err != nil {
+					return fmt.Errorf("config: shellcheck: %v", err)
+				}
+				c.enabled = true
+				c.exclude = shellcheckExclude
+				if cfg.Severity != "" {
+					c.severity = cfg.Severity
+				}
+			}
+		case *dockerfileChecker:
+			if cfg := l.config.Dockerfile; cfg != nil {
+				c.enabled = true
+				c.ignore = make(map[string]bool)
+				for _, code := range cfg.Ignore {
+					c.ignore[strings.ToUpper(code)] = true
+				}
+			}
+		case *changelogChecker:
+			if cfg := l.config.Changelog; cfg != nil {
+				c.strict = cfg.Strict
+			}
+		case *misspellChecker:
+			if cfg := l.config.Misspell; cfg != nil {
+				c.sourceComments = cfg.SourceComments
+			}
+		case *readmeStructureChecker:
+			if cfg := l.config.ReadmeStructure; cfg != nil {
+				c.minWords = defaultReadmeMinWords
+				if cfg.MinWords > 0 {
+					c.minWords = cfg.MinWords
+				}
+				c.setSections(defaultReadmeSections)
+				if cfg.RequiredSections != nil {
+					c.setSections(cfg.RequiredSections)
+				}
+			}
+		case *unwantedFileChecker:
+			c.allowIDE = l.config.AllowIDEFiles
+		case *gitignoreChecker:
+			c.allowIDE = l.config.AllowIDEFiles
+		case *staleCopyrightChecker:
+			if cfg := l.config.Copyright; cfg != nil && cfg.MaxLagYears > 0 {
+				c.maxLag = cfg.MaxLagYears
+			}
+		case *dependencyDirChecker:
+			if l.config.DependencyDirs != nil {
+				c.setNames(l.config.DependencyDirs)
+			}
+		case *communityFilesChecker:
+			if l.config.CommunityFiles != nil {
+				c.required = communityFiles
+			}
+		case *apiDocsChecker:
+			if cfg := l.config.APIDocs; cfg != nil {
+				c.enabled = true
+				c.specs = make(map[string]bool)
+				for _, p := range cfg.Specs {
+					c.specs[normalizeRepoPath(p)] = true
+				}
+			}
+		case *htmlA11yChecker:
+			c.rules = a11yRules
+		case *frictionChecker:
+			if cfg := l.config.ContributionFriction; cfg != nil {
+				c.minScore = 1
+				if cfg.MinScore > 0 {
+					c.minScore = cfg.MinScore
+				}
+			}
+		case *largeFileChecker:
+			if cfg := l.config.LargeFiles; cfg != nil && cfg.MaxSizeMB > 0 {
+				c.maxSize = megabytes(cfg.MaxSizeMB)
+			}
+		case *repoSizeChecker:
+			if cfg := l.config.RepoSize; cfg != nil {
+				if cfg.MaxSizeMB > 0 {
+					c.maxSize = megabytes(cfg.MaxSizeMB)
+				}
+				if cfg.MaxBlobMB > 0 {
+					c.maxBlob = megabytes(cfg.MaxBlobMB)
+				}
+				if cfg.Top > 0 {
+					c.top = cfg.Top
+				}
+				c.history = cfg.History
+			}
+		case *secretHistoryChecker:
+			c.enabled = l.history
+		case *i18nChecker:
+			if cfg := l.config.I18n; cfg != nil && cfg.BaseLocale != "" {
+				c.baseLocale = cfg.BaseLocale
+			}
+		case *migrationsChecker:
+			c.history = l.history
+			if cfg := l.config.Migrations; cfg != nil {
+				c.allowMissingDown = cfg.AllowMissingDown
+			}
+		case *staleDocsChecker:
+			c.maxAge = time.Duration(l.staleDocsYears) * 365 * 24 * time.Hour
+		case *mirrorChecker:
+			if u, err := url.Parse(l.webURL); err == nil && u.Hostname() != "" {
+				c.host = strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+			}
+		}
+	}
+	l.dedupeMissingTopics()
+	return nil
+}
+
+// dedupeMissingTopics makes sure missing topics are reported once:
+// by the repo metadata checker if it's enabled, by the description checker otherwise.
+func (l *Runner) dedupeMissingTopics() {
+	metadata := false
+	for _, c := range l.checkers {
+		if _, ok := c.(*repoMetadataChecker); ok {
+			metadata = true
+		}
+	}
+	for _, c := range l.checkers {
+		if c, ok := c.(*descriptionChecker); ok {
+			c.skipMissingTopics = metadata
+		}
+	}
+}
+
+// isFlagSet reports whether the flag was set explicitly,
+// either by a command-line argument or by an environment variable.
+func (l *Runner) isFlagSet(name string) bool {
+	set := false
+	l.flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func (l *Runner) loadConfig() error {
+	if l.configFile != "" {
+		cfg, err := loadConfig(l.configFile)
+		if err != nil {
+			return err
+		}
+		l.config = *cfg
+	}
+	if l.config.Email != nil {
+		if err := checkEmailConfig(l.config.Email); err != nil {
+			return fmt.Errorf("config: email: %v", err)
+		}
+	}
+	if l.exclude != "" {
+		l.config.Exclude = append(l.config.Exclude, strings.Split(l.exclude, ",")...)
+	}
+
+	excluder, err := newPathMatcher(l.config.Exclude)
+	l.excluder = excluder
+	return err
+}
+
+// readToken reads github tokens from the TOKEN env var or ./token file.
+// Several comma or newline separated tokens can be specified.
+func (l *Runner) readToken() error {
+	if l.dir != "" {
+		// Local mode doesn't use github API.
+		return nil
+	}
+	if l.anonymous {
+		return nil
+	}
+	tokens := os.Getenv("TOKEN")
+	if tokens == "" {
+		data, err := ioutil.ReadFile("./token")
+		if err != nil {
+			return fmt.Errorf("no TOKEN env var and can't read token file: %v", err)
+		}
+		tokens = string(data)
+	}
+	list := parseTokens(tokens)
+	if len(list) == 0 {
+		return errors.New("empty token")
+	}
+	l.tokens = newTokenPool(list)
+	return nil
+}
+
+func (l *Runner) initClient() error {
+	headers, err := parseHeaders(l.proxyHeaders)
+	if err != nil {
+		return err
+	}
+	if l.concurrency < 1 || l.hostConcurrency < 1 {
+		return errors.New("-concurrency and -host-concurrency should be positive")
+	}
+	if l.dir != "" {
+		return nil
+	}
+	hc := &http.Client{
+		Transport: &headerTransport{
+			headers: headers,
+			base:    l.retryTransport(newHostLimitTransport(l.hostConcurrency, http.DefaultTransport)),
+		},
+	}
+	l.archiveClient = hc
+	tc := hc
+	if l.tokens != nil {
+		tc = &http.Client{
+			Transport: &tokenTransport{pool: l.tokens, base: hc.Transport},
+		}
+	}
+
+	l.rawClient = tc
+	if !l.proxyPassToken {
+		l.rawClient = hc
+	}
+
+	if l.apiURL == "" {
+		l.client = github.NewClient(tc)
+		return nil
+	}
+	baseURL, err := url.Parse(strings.TrimSuffix(l.apiURL, "/") + "/")
+	if err != nil {
+		return fmt.Errorf("parse -github-api-url: %v", err)
+	}
+	l.client = github.NewClient(l.rawClient)
+	l.client.BaseURL = baseURL
+
+	return nil
+}
+
+// retryTransport wraps base with -retries retry policy.
+func (l *Runner) retryTransport(base http.RoundTripper) http.RoundTripper {
+	if l.retries == 0 {
+		return base
+	}
+	return &retryTransport{retries: l.retries, delay: l.retryDelay, base: base}
+}
+
+func (l *Runner) loadBaseline() error {
+	if l.baselineFile == "" {
+		return nil
+	}
+	b, err := loadBaseline(l.baselineFile)
+	l.baseline = b
+	return err
+}
+
+func (l *Runner) openSuppressionDB() error {
+	if l.suppressDBFile == "" {
+		if l.suppressAdd {
+			return errors.New("-suppress-db-add requires -suppress-db argument")
+		}
+		return nil
+	}
+	db, err := openSuppressionDB(l.suppressDBFile)
+	l.suppressions = db
+	return err
+}
+
+func (l *Runner) writeBaseline() error {
+	if l.baselineCreate == "" {
+		return nil
+	}
+	return writeBaseline(l.baselineCreate, &l.results)
+}
+
+func (l *Runner) loadResultCache() error {
+	if l.resultCacheFile == "" || l.noCache {
+		return nil
+	}
+	rc, err := loadResultCache(l.resultCacheFile)
+	l.resultCache = rc
+	return err
+}
+
+func (l *Runner) saveResultCache() error {
+	if l.resultCache == nil {
+		return nil
+	}
+	return l.resultCache.save(l.resultCacheFile)
+}
+
+func (l *Runner) initFetcher() error {
+	if l.dir != "" {
+		l.fetcher = &localFetcher{l: l}
+		return nil
+	}
+	fetcher, err := newRepoFetcher(l, l.fetchMode)
+	l.fetcher = fetcher
+	return err
+}
+
+func (l *Runner) getReposList() error {
+	if l.repo != "" {
+		l.repos = []string{l.repo}
+		return nil
+	}
+
+	opts := newRepositoryListOptions()
+	for {
+		repos, resp, err := l.client.Repositories.List(l.ctx, l.user, opts)
+		l.countRequest()
+		if err != nil {
+			if resp.NextPage == 0 && opts.Page > 1 {
+				// Ignore last page list error.
+				return nil
+			}
+			return fmt.Errorf("list repos (page=%d): %v", opts.Page, err)
+		}
+
+		if l.verbose {
+			log.Printf("\t\tdebug: fetched %d repo names\n", len(repos))
+		}
+		for _, repo := range repos {
+			if reason := l.filterRepo(repo); reason != "" {
+				if l.verbose {
+					log.Printf("\t\tdebug: skip %s repo: %s", *repo.Name, reason)
+				}
+				l.results.Filtered = append(l.results.Filtered, filteredRepo{Name: *repo.Name, Reason: reason})
+				continue
+			}
+
+			l.repos = append(l.repos, *repo.Name)
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return nil
+}
+
+// filterRepo returns a reason to skip the listed repository,
+// or empty string if it should be checked.
+func (l *Runner) filterRepo(repo *github.Repository) string {
+	if l.skipForks && repo.GetFork() {
+		return "fork"
+	}
+	const hoursToExpire = 365 * 24
+	pushed := repo.GetPushedAt().Time
+	if l.skipInactive && time.Since(pushed).Hours() > hoursToExpire {
+		return "inactive for more than a year"
+	}
+	if !l.pushedSinceTime.IsZero() && pushed.Before(l.pushedSinceTime) {
+		return fmt.Sprintf("pushed before %s", l.pushedSince)
+	}
+	if stars := repo.GetStargazersCount(); stars < l.minStars {
+		return fmt.Sprintf("%d stars, less than %d", stars, l.minStars)
+	}
+	return ""
+}
+
+func (l *Runner) lintRepos() error {
+	for i := l.offset; i < len(l.repos); i++ {
+		if err := l.ctx.Err(); err != nil {
+			log.Printf("\tstop: %v, %d repos are not checked", err, len(l.repos)-i)
+			break
+		}
+		repo := l.repos[i]
+		log.Printf("\tchecking %s/%s (%d/%d, made %d requests so far) ...",
+			l.user, repo, i+1, len(l.repos), l.requestCount())
+		if l.maxAPICalls != 0 && l.requestCount() >= l.maxAPICalls {
+			l.overBudget = append(l.overBudget, repo)
+			continue
+		}
+		l.lintRepo(repo)
+	}
+
+	if l.suppressed != 0 {
+		log.Printf("\tsuppressed %d known warnings", l.suppressed)
+	}
+	if l.tokens != nil {
+		l.tokens.logQuota()
+	}
+	if len(l.overBudget) != 0 {
+		log.Printf("\tskipped %d repos due to -max-api-calls=%d limit: %s",
+			len(l.overBudget), l.maxAPICalls, strings.Join(l.overBudget, ", "))
+	}
+	return nil
+}
+
+// localPathsReplacer returns a replacer that turns local file paths
+// inside the warning texts into the repo-relative paths.
+func localPathsReplacer(tempDir string, files []*File) *strings.Replacer {
+	var oldnew []string
+	seen := make(map[string]bool)
+	for _, f := range files {
+		if f.rootDir == "" || seen[f.rootDir] {
+			continue
+		}
+		seen[f.rootDir] = true
+		for _, p := range localPathPrefixes(f.rootDir) {
+			oldnew = append(oldnew, p, "")
+		}
+	}
+	for _, p := range localPathPrefixes(tempDir) {
+		oldnew = append(oldnew, p, "")
+	}
+	return strings.NewReplacer(oldnew...)
+}
+
+func (l *Runner) lintRepo(repo string) {
+	defer l.fetcher.Cleanup(repo)
+	files := l.collectRepoFiles(repo)
+	l.fetchGitattributes(repo, files)
+	markGenerated(files)
+
+	for _, c := range l.checkers {
+		c.Reset()
+		l.pushFiles(c, files)
+	}
+	cached := l.applyResultCache(files)
+	l.requireSBOMFiles(files)
+	propagateLinkRequirements(files)
+	if !l.withinBudget(files) {
+		log.Printf("\tskip %s: it doesn't fit into API calls budget", repo)
+		l.overBudget = append(l.overBudget, repo)
+		return
+	}
+	l.resolveRequirements(repo, files)
+	copyLinkContents(files)
+	l.fetchLanguages(repo)
+	l.fetchMetadata(repo)
+	l.setRepoURL(repo)
+	l.setTree()
+	l.setCommitDate(repo, files)
+	l.setTags(repo, files)
+	l.setIssueStates(repo)
+	l.setOwnerStates(repo)
+	rr := l.results.addRepo(repo)
+	sha, err := l.fetcher.CommitSHA(repo)
+	if err != nil {
+		log.Printf("\terror: get %s commit: %v", repo, err)
+	}
+	rr.Commit = sha
+	l.addSBOMComponent(repo, sha, files)
+	unlocal := localPathsReplacer(l.tempDir, files)
+	names := l.checkerNames()
+	results := l.runCheckers(names)
+	var warnings []Warning
+	for i, name := range names {
+		c := l.checkers[name]
+		texts := results[i].warnings
+		if err := results[i].err; err != nil {
+			log.Printf("\terror: %s checker: %v, results are incomplete", name, err)
+		} else if l.resultCache != nil {
+			l.resultCache.put(name, c, c.AcceptedFiles(), texts)
+		}
+		for j, text := range append(texts, cached[name]...) {
+			text = unlocal.Replace(text)
+			w := Warning{Checker: name, Severity: l.severities[name], Text: text}
+			if j < len(texts) {
+				w.Fix = fixAt(c, j)
+			}
+			if !l.acceptWarning(repo, findWarningFile(files, text), w) {
+				continue
+			}
+			warnings = append(warnings, w)
+		}
+		switch c := c.(type) {
+		case *mirrorChecker:
+			rr.Mirror = c.canonical
+		case *licenseDetectionChecker:
+			rr.License = c.license
+		case *frictionChecker:
+			rr.ContributionFriction = c.report
+		}
+	}
+
+	if l.fix {
+		var fixes []*Fix
+		for _, w := range warnings {
+			if w.Fix != nil {
+				fixes = append(fixes, w.Fix)
+			}
+		}
+		l.applyFixes(fixes)
+	}
+	for _, w := range warnings {
+		if w.Fix != nil && w.Fix.applied {
+			log.Printf(l.messages.Text("%s: %s: fixed: %s"), repo, w.Checker, l.messages.Warning(w.Text))
+			continue
+		}
+		log.Printf("%s: %s: %s", repo, w.Checker, l.messages.Warning(w.Text))
+		rr.Warnings = append(rr.Warnings, w)
+		l.emitFinding(repo, w)
+	}
+	rr.Score = l.repoScore(rr.Warnings)
+	log.Printf(l.messages.Text("%s: score %d"), repo, rr.Score)
+}
+
+// resolveRequirements fetches required files concurrently.
+// Symlinks requirements are resolved by fetching their targets.
+func (l *Runner) resolveRequirements(repo string, files []*File) {
+	var queue []*File
+	seen := make(map[*File]bool, len(files))
+	for _, f := range files {
+		if f.linkTarget != nil {
+			f = f.linkTarget
+		}
+		if !seen[f] {
+			seen[f] = true
+			queue = append(queue, f)
+		}
+	}
+
+	ch := make(chan *File)
+	var wg sync.WaitGroup
+	for i := 0; i < l.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range ch {
+				l.fetcher.ResolveRequirements(repo, f)
+			}
+		}()
+	}
+	for _, f := range queue {
+		ch <- f
+	}
+	close(ch)
+	wg.Wait()
+}
+
+// checkerNames returns sorted checker names.
+func (l *Runner) checkerNames() []string {
+	names := make([]string, 0, len(l.checkers))
+	for name := range l.checkers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkerResult is a single checker run result.
+type checkerResult struct {
+	warnings []string
+
+	// err is set when the checker was interrupted
+	// and the warnings list is incomplete.
+	err error
+}
+
+// runCheckers runs the named checkers concurrently.
+// Returns every checker results in the names order.
+func (l *Runner) runCheckers(names []string) []checkerResult {
+	results := make([]checkerResult, len(names))
+	for r := range l.startCheckers(names) {
+		results[r.index] = r.checkerResult
+	}
+	return results
+}
+
+// indexedResult is a checker result with the checker index in the names list.
+type indexedResult struct {
+	index int
+	checkerResult
+}
+
+// startCheckers runs the named checkers concurrently and sends their results
+// as soon as they are ready. The channel is closed after all checkers finish.
+func (l *Runner) startCheckers(names []string) <-chan indexedResult {
+	out := make(chan indexedResult)
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.NumCPU() && i < len(names); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range queue {
+				out <- indexedResult{index: j, checkerResult: l.runChecker(l.checkers[names[j]])}
+			}
+		}()
+	}
+	go func() {
+		for i := range names {
+			queue <- i
+		}
+		close(queue)
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+func (l *Runner) runChecker(c Checker) checkerResult {
+	ctx := l.ctx
+	if l.checkerTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.checkerTimeout)
+		defer cancel()
+	}
+	warnings := c.CheckFiles(ctx)
+	return checkerResult{warnings: warnings, err: ctx.Err()}
+}
+
+// applyResultCache removes files with cached results from the checkers.
+// Returns cached warnings for every checker.
+func (l *Runner) applyResultCache(files []*File) map[string][]string {
+	if l.resultCache == nil {
+		return nil
+	}
+
+	cached := make(map[string][]string)
+	misses := make(map[string][]*File)
+	for name, c := range l.checkers {
+		if _, ok := c.(Fixer); ok && l.fix {
+			// Cached warnings have no fixes, so all files are checked again.
+			misses[name] = c.AcceptedFiles()
+			continue
+		}
+		for _, f := range c.AcceptedFiles() {
+			if warnings, ok := l.resultCache.get(name, c, f); ok {
+				cached[name] = append(cached[name], warnings...)
+				continue
+			}
+			misses[name] = append(misses[name], f)
+		}
+	}
+
+	// Push files once again, now without cached ones,
+	// so their contents are not fetched.
+	for _, f := range files {
+		f.require.localCopy = false
+		f.require.contents = false
+	}
+	for name, c := range l.checkers {
+		c.Reset()
+		pushed := misses[name]
+		if _, ok := c.(treeChecker); ok {
+			pushed = files
+		}
+		l.pushFiles(c, pushed)
+	}
+	return cached
+}
+
+// acceptWarning reports whether w should be reported.
+// f is a file w refers to, it can be nil.
+func (l *Runner) acceptWarning(repo string, f *File, w Warning) bool {
+	if w.Severity < l.minSeverity {
+		return false
+	}
+	if l.baseline != nil && l.baseline.contains(repo, w) {
+		l.suppressed++
+		return false
+	}
+	if l.suppressions == nil || f == nil || f.contentsHash() == "" {
+		return true
+	}
+	if l.suppressAdd {
+		if err := l.suppressions.add(repo, f, w); err != nil {
+			log.Printf("\terror: add suppression: %v", err)
+		}
+		return true
+	}
+	suppressed, err := l.suppressions.contains(f, w)
+	if err != nil {
+		log.Printf("\terror: query suppressions: %v", err)
+	}
+	if suppressed {
+		l.suppressed++
+		return false
+	}
+	return true
+}
+
+// countRequest records a single API call.
+func (l *Runner) countRequest() {
+	l.requestsMu.Lock()
+	l.requests++
+	l.requestsMu.Unlock()
+}
+
+// requestCount returns a number of API calls made so far.
+func (l *Runner) requestCount() int {
+	l.requestsMu.Lock()
+	defer l.requestsMu.Unlock()
+	return l.requests
+}
+
+// withinBudget reports whether fetching files requirements
+// can be done without exceeding the API calls limit.
+func (l *Runner) withinBudget(files []*File) bool {
+	if l.maxAPICalls == 0 {
+		return true
+	}
+	cost := 0
+	for _, f := range files {
+		cost += l.fetcher.RequestsCost(f)
+	}
+	return l.requestCount()+cost <= l.maxAPICalls
+}
+
+// vendorPathRE matches vendored dependencies paths, they are skipped with -skipVendor.
+var vendorPathRE = regexp.MustCompile(`/?vendor/|/?node_modules/|/?cargo-vendor/`)
+
+func (l *Runner) collectRepoFiles(repo string) []*File {
+	l.tree = nil
+	l.truncatedTree = false
+	all, err := l.fetcher.CollectFiles(repo)
+	if err != nil {
+		log.Printf("\terror: %v", err)
+		return nil
+	}
+
+	for _, f := range all {
+		f.origName = normalizeRepoPath(f.origName)
+		f.baseName = path.Base(f.origName)
+	}
+	l.resolveSymlinks(repo, all)
+	if !l.truncatedTree {
+		l.tree = newRepoTree(all)
+	}
+
+	var files []*File
+	for _, f := range all {
+		if l.skipVendor && vendorPathRE.MatchString(f.origName) {
+			continue
+		}
+		if l.onlyFiles != nil && !l.onlyFiles[f.origName] {
+			continue
+		}
+		if l.excluder.Match(f.origName) {
+			continue
+		}
+		files = append(files, f)
+	}
+
+	return dedupeFiles(files)
+}
+
+// treeRef returns a git ref that is used to get repository files.
+func (l *Runner) treeRef() string {
+	if l.ref != "" {
+		return l.ref
+	}
+	return "master"
+}
+
+func newRepositoryListOptions() *github.RepositoryListOptions {
+	// Use some high value, github will limit it anyway,
+	// but we're interested in getting more data per one request.
+	return &github.RepositoryListOptions{
+		ListOptions: github.ListOptions{PerPage: math.MaxInt32},
+	}
+}
@@ -0,0 +1,149 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dependencyManifests are base name patterns of the files
+// that declare project dependencies.
+var dependencyManifests = []string{
+	"go.mod", "go.sum", "Gopkg.toml", "Gopkg.lock",
+	"package.json", "package-lock.json", "yarn.lock", "pnpm-lock.yaml",
+	"Cargo.toml", "Cargo.lock",
+	"requirements*.txt", "Pipfile", "Pipfile.lock", "pyproject.toml", "poetry.lock", "setup.py", "setup.cfg",
+	"Gemfile", "Gemfile.lock", "*.gemspec",
+	"pom.xml", "build.gradle", "build.gradle.kts",
+	"composer.json", "composer.lock",
+	"*.csproj", "packages.config",
+	"mix.exs", "mix.lock", "pubspec.yaml", "Package.swift", "Podfile",
+}
+
+// sbomComponent is a CycloneDX component that describes a single repository.
+type sbomComponent struct {
+	Type       string          `json:"type"`
+	BOMRef     string          `json:"bom-ref"`
+	Name       string          `json:"name"`
+	Version    string          `json:"version,omitempty"`
+	Licenses   []sbomLicense   `json:"licenses,omitempty"`
+	References []sbomReference `json:"externalReferences,omitempty"`
+	Properties []sbomProperty  `json:"properties,omitempty"`
+}
+
+type sbomLicense struct {
+	License struct {
+		ID string `json:"id"`
+	} `json:"license"`
+}
+
+type sbomReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type sbomProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// requireSBOMFiles asks for the license files contents.
+// Usually they are already required by the checkers.
+func (l *Runner) requireSBOMFiles(files []*File) {
+	if l.sbomFile == "" {
+		return
+	}
+	for _, f := range files {
+		if !strings.Contains(f.origName, "/") && licenseFileRE.MatchString(f.origName) && !f.isDanglingLink() {
+			f.require.contents = true
+		}
+	}
+}
+
+// addSBOMComponent records the repository license and dependency manifests.
+func (l *Runner) addSBOMComponent(repo, commit string, files []*File) {
+	if l.sbomFile == "" {
+		return
+	}
+	c := sbomComponent{
+		Type:    "application",
+		BOMRef:  l.user + "/" + repo,
+		Name:    repo,
+		Version: commit,
+		References: []sbomReference{{
+			Type: "vcs",
+			URL:  fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(l.webURL, "/"), l.user, repo),
+		}},
+	}
+	for _, f := range files {
+		if !strings.Contains(f.origName, "/") && licenseFileRE.MatchString(f.origName) && len(c.Licenses) == 0 {
+			if id := detectLicense(f.contents); id != "" {
+				var lic sbomLicense
+				lic.License.ID = id
+				c.Licenses = append(c.Licenses, lic)
+			}
+		}
+		if matchAnyPattern(dependencyManifests, f.baseName) {
+			c.Properties = append(c.Properties, sbomProperty{
+				Name:  "repolint:manifest",
+				Value: f.origName,
+			})
+		}
+	}
+	l.sbom = append(l.sbom, c)
+}
+
+// writeSBOM writes a CycloneDX document with all checked repositories.
+func (l *Runner) writeSBOM() error {
+	if l.sbomFile == "" {
+		return nil
+	}
+	type tool struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	doc := struct {
+		BOMFormat   string `json:"bomFormat"`
+		SpecVersion string `json:"specVersion"`
+		Version     int    `json:"version"`
+		Metadata    struct {
+			Timestamp string `json:"timestamp"`
+			Tools     []tool `json:"tools"`
+		} `json:"metadata"`
+		Components []sbomComponent `json:"components"`
+	}{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.4",
+		Version:     1,
+		Components:  l.sbom,
+	}
+	scores := make(map[string]int, len(l.results.Repos))
+	for _, rr := range l.results.Repos {
+		scores[rr.Name] = rr.Score
+	}
+	for i := range doc.Components {
+		c := &doc.Components[i]
+		if score, ok := scores[c.Name]; ok {
+			c.Properties = append(c.Properties, sbomProperty{Name: "repolint:score", Value: strconv.Itoa(score)})
+		}
+	}
+	doc.Metadata.Timestamp = l.results.Started.UTC().Format(time.RFC3339)
+	doc.Metadata.Tools = []tool{{Name: "repolint", Version: Version}}
+	if doc.Components == nil {
+		doc.Components = []sbomComponent{}
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	if l.sbomFile == "-" {
+		_, err := os.Stdout.Write(append(data, '\n'))
+		return err
+	}
+	return ioutil.WriteFile(l.sbomFile, data, 0644)
+}
@@ -0,0 +1,46 @@
+package lint
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// schemaFiles are the JSON Schemas of the repolint machine-readable
+// inputs and outputs, see PrintSchema.
+//
+//go:embed schemas/*.json
+var schemaFiles embed.FS
+
+// schemaNames returns the names of the embedded schemas, like "results".
+func schemaNames() []string {
+	entries, err := schemaFiles.ReadDir("schemas")
+	if err != nil {
+		panic(err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PrintSchema prints a JSON Schema of a repolint file format to stdout.
+//
+// Usage: repolint schema <results|config|baseline|plugin-input|plugin-output>
+func PrintSchema(args []string) error {
+	names := schemaNames()
+	if len(args) != 1 {
+		return fmt.Errorf("usage: repolint schema <%s>", strings.Join(names, "|"))
+	}
+	data, err := schemaFiles.ReadFile(path.Join("schemas", args[0]+".json"))
+	if err != nil {
+		return fmt.Errorf("unknown schema %q, expected one of: %s", args[0], strings.Join(names, ", "))
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
@@ -0,0 +1,147 @@
+package lint
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// scoringConfig configures the repository health score.
+type scoringConfig struct {
+	// Weights multiply the penalties of the checker category findings,
+	// like 2 for security or 0 to ignore style findings. The default weight is 1.
+	// Checkers without a category, like plugins, belong to the "other" category.
+	Weights map[string]float64 `yaml:"weights"`
+}
+
+// Compliance score penalties per warning severity.
+// A repository without warnings scores 100.
+var scorePenalties = map[Severity]int{
+	SeverityInfo:    1,
+	SeverityWarning: 3,
+	SeverityError:   10,
+}
+
+// scoreModel converts warnings into a 0-100 repository health score.
+type scoreModel struct {
+	// weights are the category penalty multipliers.
+	weights map[string]float64
+}
+
+// defaultScoreModel is used when the scoring config section is not specified.
+var defaultScoreModel = &scoreModel{}
+
+func newScoreModel(cfg *scoringConfig) (*scoreModel, error) {
+	m := &scoreModel{}
+	if cfg == nil {
+		return m, nil
+	}
+	known := scoreCategories()
+	for category, weight := range cfg.Weights {
+		if !known[category] {
+			return nil, fmt.Errorf("unknown category %q, expected one of: %s", category, strings.Join(sortedKeys(known), ", "))
+		}
+		if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return nil, fmt.Errorf("%s: weight should be a non-negative number, got %v", category, weight)
+		}
+	}
+	m.weights = cfg.Weights
+	return m, nil
+}
+
+// scoreCategories returns a set of the registry checker categories and "other".
+func scoreCategories() map[string]bool {
+	categories := map[string]bool{"other": true}
+	for _, info := range checkerRegistry {
+		if info.Category != "" {
+			categories[info.Category] = true
+		}
+	}
+	return categories
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// score returns a 0-100 score of the repository with the given warnings.
+func (m *scoreModel) score(warnings []Warning) int {
+	penalty := 0.0
+	for _, w := range warnings {
+		weight, ok := m.weights[checkerCategory(w.Checker)]
+		if !ok {
+			weight = 1
+		}
+		penalty += float64(scorePenalties[w.Severity]) * weight
+	}
+	score := 100 - int(math.Round(penalty))
+	if score < 0 {
+		return 0
+	}
+	return score
+}
+
+// repoScore returns a repository score. With -soft-fail-network, network
+// checker warnings don't cost points, so a network outage can't fail -min-score.
+func (l *Runner) repoScore(warnings []Warning) int {
+	if !l.softFailNetwork {
+		return l.scoring.score(warnings)
+	}
+	scored := make([]Warning, 0, len(warnings))
+	for _, w := range warnings {
+		if !isNetworkChecker(l.checkers[w.Checker]) {
+			scored = append(scored, w)
+		}
+	}
+	return l.scoring.score(scored)
+}
+
+// scoreClass returns a CSS class for the score color.
+func scoreClass(score int) string {
+	switch {
+	case score >= 90:
+		return "good"
+	case score >= 70:
+		return "fair"
+	case score >= 40:
+		return "poor"
+	default:
+		return "bad"
+	}
+}
+
+// initScoring creates the score model from the scoring config section.
+func (l *Runner) initScoring() error {
+	if l.minScore < 0 || l.minScore > 100 {
+		return fmt.Errorf("-min-score should be in [0, 100] range, got %d", l.minScore)
+	}
+	m, err := newScoreModel(l.config.Scoring)
+	if err != nil {
+		return fmt.Errorf("config: scoring: %v", err)
+	}
+	l.scoring = m
+	return nil
+}
+
+// checkMinScore fails the run if any repository scored below -min-score.
+func (l *Runner) checkMinScore() error {
+	if l.minScore == 0 {
+		return nil
+	}
+	var low []string
+	for _, rr := range l.results.Repos {
+		if rr.Score < l.minScore {
+			low = append(low, fmt.Sprintf("%s (%d)", rr.Name, rr.Score))
+		}
+	}
+	if len(low) != 0 {
+		return fmt.Errorf("%d repositories scored below %d: %s", len(low), l.minScore, strings.Join(low, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity describes how important the warning is.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+var severityNames = [...]string{
+	SeverityInfo:    "info",
+	SeverityWarning: "warning",
+	SeverityError:   "error",
+}
+
+func (s Severity) String() string { return severityNames[s] }
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(data []byte) error {
+	v, err := parseSeverity(string(data))
+	*s = v
+	return err
+}
+
+func parseSeverity(s string) (Severity, error) {
+	for i, name := range severityNames {
+		if s == name {
+			return Severity(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown severity %q, expected one of: %s",
+		s, strings.Join(severityNames[:], ", "))
+}
+
+// defaultSeverity returns the checker severity without config overrides.
+func (l *Runner) defaultSeverity(name string) Severity {
+	if s, ok := l.customSeverities[name]; ok {
+		return s
+	}
+	info, _ := lookupChecker(name)
+	return info.Severity
+}
+
+// networkChecker is implemented by the checkers which results
+// depend on the network availability, apart from the file downloads.
+type networkChecker interface {
+	networkResults()
+}
+
+// isNetworkChecker reports whether c needs network to check a repository.
+func isNetworkChecker(c Checker) bool {
+	switch c.(type) {
+	case networkChecker, metadataChecker, languagesChecker:
+		return true
+	default:
+		return false
+	}
+}
+
+// initSeverities combines default checker severities with config overrides.
+// With -soft-fail-network, network checkers are downgraded to info.
+func (l *Runner) initSeverities() error {
+	minSeverity, err := parseSeverity(l.minSeverityName)
+	if err != nil {
+		return fmt.Errorf("-min-severity: %v", err)
+	}
+	l.minSeverity = minSeverity
+
+	l.severities = make(map[string]Severity, len(l.checkers))
+	for name := range l.checkers {
+		l.severities[name] = l.defaultSeverity(name)
+	}
+	names := make([]string, 0, len(l.config.Severity))
+	for name := range l.config.Severity {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := l.checkers[name]; !ok {
+			return fmt.Errorf("config: severity: unknown checker %q", name)
+		}
+		l.severities[name] = l.config.Severity[name]
+	}
+	if l.softFailNetwork {
+		for name, c := range l.checkers {
+			if isNetworkChecker(c) {
+				l.severities[name] = SeverityInfo
+			}
+		}
+	}
+	return nil
+}
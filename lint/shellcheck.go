@@ -0,0 +1,149 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// shellcheckConfig enables the shellcheck checker.
+type shellcheckConfig struct {
+	// Severity is the minimal reported severity: error, warning, info or style.
+	// Defaults to warning.
+	Severity string `yaml:"severity"`
+
+	// Exclude are the shellcheck codes that are not reported, like SC2086.
+	Exclude []string `yaml:"exclude"`
+}
+
+// shellcheckSeverities are the severities shellcheck accepts, most severe first.
+var shellcheckSeverities = []string{"error", "warning", "info", "style"}
+
+// shellScriptExts are the extensions of the shell scripts shellcheck understands.
+var shellScriptExts = map[string]bool{".sh": true, ".bash": true, ".ksh": true, ".dash": true}
+
+// shellShebangRE matches the shebangs of the shells shellcheck understands,
+// like "#!/bin/sh" or "#!/usr/bin/env bash".
+var shellShebangRE = regexp.MustCompile(`^#!\s*(?:/usr/bin/env\s+(?:-S\s+)?)?(?:/\S*/)?(?:sh|bash|dash|ksh)(?:\s|$)`)
+
+// shellScriptMaxSize limits the size of the files without an extension
+// that are read to find the shell shebang.
+const shellScriptMaxSize = 1 << 20
+
+// shellcheckOutput is the shellcheck json1 format output.
+type shellcheckOutput struct {
+	Comments []struct {
+		File    string `json:"file"`
+		Line    int    `json:"line"`
+		Column  int    `json:"column"`
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"comments"`
+}
+
+// shellcheckChecker runs shellcheck against the shell scripts: *.sh files
+// and, in clone and local modes, files without an extension with a shell shebang.
+// It's disabled until the shellcheck config section is specified.
+type shellcheckChecker struct {
+	CheckerBase
+
+	enabled bool
+
+	// severity is the minimal reported severity.
+	severity string
+
+	// exclude are the codes that are not reported.
+	exclude []string
+}
+
+func newShellcheckChecker() *shellcheckChecker {
+	return &shellcheckChecker{severity: "warning"}
+}
+
+func (c *shellcheckChecker) PushFile(f *File) {
+	if !c.enabled || f.dir || f.symlink {
+		return
+	}
+	ext := strings.ToLower(path.Ext(f.baseName))
+	// Reading every file to find shebangs is only cheap for local files.
+	if shellScriptExts[ext] || (ext == "" && f.rootDir != "" && f.size <= shellScriptMaxSize) {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+// Vendored scripts are not maintained here.
+func (c *shellcheckChecker) skipGenerated() {}
+
+func (c *shellcheckChecker) externalTool() string { return "shellcheck" }
+
+// Results depend on the installed shellcheck version.
+func (c *shellcheckChecker) uncachedResults() {}
+
+func (c *shellcheckChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	var scripts []string
+	for _, f := range c.files {
+		if f.tempName == "" {
+			continue
+		}
+		if shellScriptExts[strings.ToLower(path.Ext(f.baseName))] || shellShebangRE.MatchString(f.contents) {
+			scripts = append(scripts, f.tempName)
+		}
+	}
+	if len(scripts) == 0 {
+		return nil
+	}
+
+	args := []string{"--format", "json1", "--severity", c.severity}
+	if len(c.exclude) != 0 {
+		args = append(args, "--exclude", strings.Join(c.exclude, ","))
+	}
+	args = append(args, "--")
+	args = append(args, scripts...)
+	stdout := limitedBuffer{max: pluginMaxOutputSize}
+	cmd := exec.CommandContext(ctx, "shellcheck", args...)
+	cmd.Stdout = &stdout
+	err := cmd.Run()
+
+	// shellcheck exits with 1 when it reports anything.
+	var out shellcheckOutput
+	if jsonErr := json.Unmarshal(stdout.Bytes(), &out); jsonErr != nil {
+		if ctx.Err() == nil {
+			if err == nil {
+				err = jsonErr
+			}
+			log.Printf("\terror: shellcheck: %v", err)
+		}
+		return nil
+	}
+	manifest := c.manifest()
+	for _, comment := range out.Comments {
+		f := manifest[comment.File]
+		if f == nil {
+			continue
+		}
+		msg := pluginLineBreaks.Replace(comment.Message)
+		w := fmt.Sprintf("%s:%d:%d: SC%d %s", f.origName, comment.Line, comment.Column, comment.Code, msg)
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
+
+// shellcheckCodes normalizes the codes, like "2086" or "sc2086", to "SC2086".
+func shellcheckCodes(codes []string) ([]string, error) {
+	normalized := make([]string, 0, len(codes))
+	for _, code := range codes {
+		digits := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(code)), "SC")
+		if _, err := strconv.Atoi(digits); err != nil {
+			return nil, fmt.Errorf("bad code %q, expected a code like SC2086", code)
+		}
+		normalized = append(normalized, "SC"+digits)
+	}
+	return normalized, nil
+}
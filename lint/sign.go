@@ -0,0 +1,163 @@
+package lint
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+)
+
+// reportSignature is a detached report signature stored in a .sig file
+// next to the signed report.
+type reportSignature struct {
+	// File is the signed report base name.
+	File string `json:"file"`
+
+	// SHA256 is a hex-encoded report contents hash.
+	SHA256 string `json:"sha256"`
+
+	// Version is the repolint version that produced the report.
+	Version string `json:"version"`
+
+	// Signature is a base64-encoded Ed25519 signature of signedMessage.
+	Signature string `json:"signature"`
+}
+
+// signedMessage returns the message that is actually signed.
+// It binds the report hash to the repolint version.
+func (s *reportSignature) signedMessage() []byte {
+	return []byte("repolint report signature v1\n" + s.Version + "\n" + s.SHA256 + "\n")
+}
+
+// loadSignKey reads the -sign-key Ed25519 private key.
+func (l *Runner) loadSignKey() error {
+	if l.signKeyFile == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(l.signKeyFile)
+	if err != nil {
+		return err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return fmt.Errorf("%s: no PEM data found", l.signKeyFile)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("%s: %v", l.signKeyFile, err)
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return fmt.Errorf("%s: expected Ed25519 private key, got %T", l.signKeyFile, key)
+	}
+	l.signKey = edKey
+	return nil
+}
+
+// signReport writes a detached signature of a report file into filename.sig.
+// Does nothing if -sign-key is not set or the report is written to stdout.
+func (l *Runner) signReport(filename string) error {
+	if l.signKey == nil || filename == "-" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	sig := &reportSignature{
+		File:    filepath.Base(filename),
+		SHA256:  hex.EncodeToString(sum[:]),
+		Version: Version,
+	}
+	sig.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(l.signKey, sig.signedMessage()))
+	out, err := json.MarshalIndent(sig, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename+".sig", out, 0644)
+}
+
+// verifyReport checks the filename.sig signature of a report file.
+// Returns the repolint version that signed the report.
+func verifyReport(filename string, pub ed25519.PublicKey) (string, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	sigData, err := ioutil.ReadFile(filename + ".sig")
+	if err != nil {
+		return "", err
+	}
+	var sig reportSignature
+	if err := json.Unmarshal(sigData, &sig); err != nil {
+		return "", fmt.Errorf("decode signature: %v", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(sig.Signature)
+	if err != nil {
+		return "", fmt.Errorf("decode signature: %v", err)
+	}
+	if !ed25519.Verify(pub, sig.signedMessage(), signature) {
+		return "", errors.New("bad signature")
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != sig.SHA256 {
+		return "", errors.New("report contents were modified after signing")
+	}
+	return sig.Version, nil
+}
+
+// VerifyReports checks report signatures made with -sign-key.
+//
+// Usage: repolint verify -key public.pem results.json [results.html ...]
+func VerifyReports(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	keyFile := fs.String("key", "", `Ed25519 public key PEM file`)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *keyFile == "" || fs.NArg() == 0 {
+		return errors.New("usage: repolint verify -key public.pem report...")
+	}
+
+	data, err := ioutil.ReadFile(*keyFile)
+	if err != nil {
+		return err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return fmt.Errorf("%s: no PEM data found", *keyFile)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("%s: %v", *keyFile, err)
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return fmt.Errorf("%s: expected Ed25519 public key, got %T", *keyFile, key)
+	}
+
+	failed := 0
+	for _, filename := range fs.Args() {
+		version, err := verifyReport(filename, pub)
+		if err != nil {
+			log.Printf("%s: %v", filename, err)
+			failed++
+			continue
+		}
+		log.Printf("%s: OK, signed by repolint %s", filename, version)
+	}
+	if failed != 0 {
+		return fmt.Errorf("%d of %d reports failed verification", failed, fs.NArg())
+	}
+	return nil
+}
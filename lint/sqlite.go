@@ -0,0 +1,21 @@
+//go:build !nosqlite
+// +build !nosqlite
+
+package lint
+
+import (
+	"database/sql"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteSupported reports whether SQLite support is built in.
+const sqliteSupported = true
+
+// openSQLite opens a SQLite database file.
+//
+// The driver needs cgo, so minimal static builds
+// leave it out with the nosqlite build tag, see sqlite_stub.go.
+func openSQLite(filename string) (*sql.DB, error) {
+	return sql.Open("sqlite3", filename)
+}
@@ -0,0 +1,15 @@
+//go:build nosqlite
+// +build nosqlite
+
+package lint
+
+import (
+	"database/sql"
+	"errors"
+)
+
+const sqliteSupported = false
+
+func openSQLite(filename string) (*sql.DB, error) {
+	return nil, errors.New("SQLite support is not built in, rebuild repolint without the nosqlite tag")
+}
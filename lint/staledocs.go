@@ -0,0 +1,88 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// staleDocsMinCommits is a number of code commits made after
+// the last documentation update that makes the documentation stale.
+const staleDocsMinCommits = 20
+
+// staleDocRE matches root documentation files that
+// are expected to follow the code changes.
+var staleDocRE = regexp.MustCompile(`^(?i)(?:README|CHANGELOG|CHANGES|HISTORY)(?:\.[a-z]+)?$`)
+
+// staleDocsChecker finds README and CHANGELOG files that were not
+// updated for a long time, while the code keeps changing.
+//
+// It needs the repository history, so it only works in clone mode.
+type staleDocsChecker struct {
+	CheckerBase
+
+	// maxAge is a documentation age that is worth checking.
+	// Zero disables the checker.
+	maxAge time.Duration
+}
+
+func newStaleDocsChecker() *staleDocsChecker {
+	return &staleDocsChecker{}
+}
+
+func (c *staleDocsChecker) PushFile(f *File) {
+	if c.maxAge != 0 && f.rootDir != "" && staleDocRE.MatchString(f.origName) {
+		c.AcceptFile(f)
+	}
+}
+
+func (c *staleDocsChecker) externalTool() string { return "git" }
+
+// Results depend on the repository history.
+func (c *staleDocsChecker) uncachedResults() {}
+
+func (c *staleDocsChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	for _, f := range c.files {
+		if ctx.Err() != nil {
+			break
+		}
+		out, err := gitOutput(ctx, f.rootDir, "log", "-1", "--format=%ct", "--", f.origName)
+		if err != nil || out == "" {
+			continue
+		}
+		unix, err := strconv.ParseInt(out, 10, 64)
+		if err != nil {
+			continue
+		}
+		updated := time.Unix(unix, 0)
+		age := time.Since(updated)
+		if age < c.maxAge {
+			continue
+		}
+		// Documentation-only commits don't make the docs stale.
+		out, err = gitOutput(ctx, f.rootDir, "rev-list", "--count", "--since="+out, "HEAD",
+			"--", ".", ":(exclude)*.md", ":(exclude)*.txt", ":(exclude)*.rst")
+		if err != nil {
+			continue
+		}
+		commits, _ := strconv.Atoi(out)
+		if commits < staleDocsMinCommits {
+			continue
+		}
+		w := fmt.Sprintf("%s: not updated since %s (%d years), while the code got %d commits",
+			f.origName, updated.UTC().Format("2006-01-02"), int(age.Hours()/24/365), commits)
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
+
+// gitOutput runs git inside dir and returns its trimmed output.
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	args = append([]string{"-C", dir}, args...)
+	out, err := exec.CommandContext(ctx, "git", args...).Output()
+	return strings.TrimSpace(string(out)), err
+}
@@ -0,0 +1,79 @@
+package lint
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// suppressionDB is a shared knowledge base of accepted false positives.
+//
+// Suppressions are keyed by a checker name and a file content hash
+// instead of a repository and a file path, so the same file vendored
+// into many repositories only has to be triaged once.
+type suppressionDB struct {
+	db *sql.DB
+}
+
+func openSuppressionDB(filename string) (*suppressionDB, error) {
+	db, err := openSQLite(filename)
+	if err != nil {
+		return nil, err
+	}
+	const schema = `CREATE TABLE IF NOT EXISTS suppressions (
+		checker      TEXT NOT NULL,
+		content_hash TEXT NOT NULL,
+		message      TEXT NOT NULL,
+		repo         TEXT NOT NULL,
+		path         TEXT NOT NULL,
+		added        TEXT NOT NULL,
+		PRIMARY KEY (checker, content_hash, message)
+	)`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &suppressionDB{db: db}, nil
+}
+
+func (s *suppressionDB) Close() error {
+	return s.db.Close()
+}
+
+// suppressionMessage returns a warning text that doesn't depend
+// on the file location inside a repository.
+func suppressionMessage(f *File, w Warning) string {
+	return strings.Replace(normalizeWarningText(w.Text), f.origName, "", -1)
+}
+
+func (s *suppressionDB) contains(f *File, w Warning) (bool, error) {
+	var n int
+	err := s.db.QueryRow(
+		`SELECT COUNT(*) FROM suppressions WHERE checker = ? AND content_hash = ? AND message = ?`,
+		w.Checker, f.contentsHash(), suppressionMessage(f, w)).Scan(&n)
+	return n != 0, err
+}
+
+func (s *suppressionDB) add(repo string, f *File, w Warning) error {
+	_, err := s.db.Exec(
+		`INSERT OR IGNORE INTO suppressions VALUES (?, ?, ?, ?, ?, ?)`,
+		w.Checker, f.contentsHash(), suppressionMessage(f, w), repo, f.origName,
+		time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+// findWarningFile returns a file the warning text refers to.
+// Returns nil if there is no such file.
+func findWarningFile(files []*File, text string) *File {
+	var best *File
+	for _, f := range files {
+		if !strings.HasPrefix(text, f.origName+":") && !strings.HasSuffix(text, ": "+f.origName) {
+			continue
+		}
+		// Prefer the longest match: "a/README.md" over "README.md".
+		if best == nil || len(f.origName) > len(best.origName) {
+			best = f
+		}
+	}
+	return best
+}
@@ -0,0 +1,119 @@
+package lint
+
+import (
+	"log"
+	"path"
+	"strings"
+)
+
+// maxLinkChain limits the number of symlinks followed to find the link target.
+const maxLinkChain = 8
+
+// resolveSymlinks sets linkTarget for all symlinks that point to the repo files.
+func (l *Runner) resolveSymlinks(repo string, files []*File) {
+	byName := make(map[string]*File, len(files))
+	for _, f := range files {
+		byName[f.origName] = f
+	}
+
+	for _, f := range files {
+		if !f.symlink {
+			continue
+		}
+		// Follow the chain of links, but don't loop forever.
+		t := f
+		for i := 0; i < maxLinkChain && t != nil && t.symlink; i++ {
+			target, err := l.fetcher.LinkTarget(repo, t)
+			if err != nil {
+				log.Printf("\terror: resolve %s/%s link: %v", repo, t.origName, err)
+				t = nil
+				break
+			}
+			if i == 0 {
+				f.linkPath = target
+			}
+			t = byName[resolveLinkPath(t.origName, target)]
+		}
+		if t != nil && !t.symlink {
+			f.linkTarget = t
+		}
+	}
+}
+
+// resolveLinkPath returns a repo-relative path of the link target.
+// Returns empty string for targets outside of the repository.
+func resolveLinkPath(link, target string) string {
+	if path.IsAbs(target) {
+		return ""
+	}
+	p := path.Join(path.Dir(link), target)
+	if p == ".." || strings.HasPrefix(p, "../") {
+		return ""
+	}
+	return p
+}
+
+// dedupeFiles removes files which contents would be checked twice.
+//
+// This happens when README is a symlink to README.md or
+// when the same directory contains README and README.md
+// with identical contents.
+// Symlinks that can't be resolved are kept for the symlink checker,
+// other checkers don't get them, see Runner.pushFiles.
+func dedupeFiles(files []*File) []*File {
+	type docKey struct {
+		dir string
+		sha string
+	}
+	seen := make(map[docKey]bool)
+	result := files[:0]
+	for _, f := range files {
+		if f.symlink {
+			if f.linkTarget != nil && isDocumentationFile(f.linkTarget.baseName) {
+				continue
+			}
+			result = append(result, f)
+			continue
+		}
+		if isDocumentationFile(f.baseName) && f.sha != "" {
+			key := docKey{dir: path.Dir(f.origName), sha: f.sha}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		result = append(result, f)
+	}
+	return result
+}
+
+// isDanglingLink reports whether f is a symlink that can't be resolved
+// to a repository file. Its contents is not a real file contents.
+func (f *File) isDanglingLink() bool {
+	return f.symlink && f.linkTarget == nil
+}
+
+// propagateLinkRequirements moves symlinks requirements to their targets,
+// so link contents is fetched only once.
+func propagateLinkRequirements(files []*File) {
+	for _, f := range files {
+		t := f.linkTarget
+		if t == nil {
+			continue
+		}
+		t.require.localCopy = t.require.localCopy || f.require.localCopy
+		t.require.contents = t.require.contents || f.require.contents
+		f.require.localCopy = false
+		f.require.contents = false
+	}
+}
+
+// copyLinkContents makes symlinks share their targets contents.
+func copyLinkContents(files []*File) {
+	for _, f := range files {
+		if t := f.linkTarget; t != nil {
+			f.tempName = t.tempName
+			f.contents = t.contents
+		}
+	}
+}
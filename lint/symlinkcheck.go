@@ -0,0 +1,78 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// danglingLinkChecker is implemented by the checkers that get the symlinks
+// that can't be resolved to repository files, see Runner.pushFiles.
+type danglingLinkChecker interface {
+	checkDanglingLinks()
+}
+
+// symlinkChecker finds symlinks that point outside of the repository
+// or to paths that don't exist, like the leftovers of moved files.
+type symlinkChecker struct {
+	CheckerBase
+
+	// tree is the checked repository tree index, if available.
+	tree *repoTree
+}
+
+func newSymlinkChecker() *symlinkChecker {
+	return &symlinkChecker{}
+}
+
+func (c *symlinkChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.tree = nil
+}
+
+func (c *symlinkChecker) PushFile(f *File) {
+	if f.isDanglingLink() && f.linkPath != "" {
+		c.AcceptFile(f)
+	}
+}
+
+func (c *symlinkChecker) setTree(t *repoTree) {
+	c.tree = t
+}
+
+func (c *symlinkChecker) checkDanglingLinks() {}
+
+// Link targets are not pushed to the checker.
+func (c *symlinkChecker) uncachedResults() {}
+
+func (c *symlinkChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	for _, f := range c.files {
+		target := resolveLinkPath(f.origName, f.linkPath)
+		var problem string
+		switch {
+		case path.IsAbs(f.linkPath) || isWindowsAbsPath(f.linkPath):
+			problem = "points to an absolute path outside of the repository"
+		case target == "":
+			problem = "points outside of the repository"
+		case c.tree == nil:
+			// Truncated tree, the target may exist.
+			continue
+		case !c.tree.has(target):
+			problem = "points to a path that doesn't exist"
+			if moved := c.tree.findMoved(target); moved != "" {
+				problem += fmt.Sprintf(", maybe it was moved to %s", moved)
+			}
+		default:
+			problem = fmt.Sprintf("can't be resolved, it's a loop or a chain of more than %d links", maxLinkChain)
+		}
+		warnings = append(warnings, fmt.Sprintf("%s: symlink to %s %s", f.origName, f.linkPath, problem))
+	}
+	return warnings
+}
+
+// isWindowsAbsPath reports whether p is an absolute Windows path, like C:/dir.
+func isWindowsAbsPath(p string) bool {
+	drive := p != "" && 'a' <= p[0]|0x20 && p[0]|0x20 <= 'z'
+	return drive && strings.HasPrefix(p[1:], ":/")
+}
@@ -0,0 +1,179 @@
+package lint
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/github"
+)
+
+// maxTarballSize limits the extracted repository size in tarball mode.
+const maxTarballSize = 512 << 20
+
+// tarballFetcher downloads and extracts the repository tarball.
+//
+// It costs a single API call per repository, the archive download itself
+// doesn't count against the API rate limit, so the anonymous mode uses it.
+// Files excluded with the export-ignore attribute are not in the tarball.
+type tarballFetcher struct {
+	l *Runner
+
+	// commits are the archived commit hashes by repository.
+	commits map[string]string
+}
+
+func (tf *tarballFetcher) repoDir(repo string) string {
+	return filepath.Join(tf.l.tempDir, "tarball", repo)
+}
+
+func (tf *tarballFetcher) CollectFiles(repo string) ([]*File, error) {
+	l := tf.l
+	dir := tf.repoDir(repo)
+	if err := removeAll(dir); err != nil {
+		return nil, err
+	}
+
+	opts := &github.RepositoryContentGetOptions{Ref: l.ref}
+	u, _, err := l.client.Repositories.GetArchiveLink(l.ctx, l.user, repo, github.Tarball, opts)
+	l.countRequest()
+	if err != nil {
+		return nil, fmt.Errorf("get %s tarball link: %v", repo, err)
+	}
+	req, err := http.NewRequestWithContext(l.ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := l.archiveClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download %s tarball: %v", repo, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s tarball: %s", repo, resp.Status)
+	}
+
+	commit, symlinks, err := extractTarball(resp.Body, dir, maxTarballSize)
+	if err != nil {
+		return nil, fmt.Errorf("extract %s tarball: %v", repo, err)
+	}
+	if tf.commits == nil {
+		tf.commits = make(map[string]string)
+	}
+	tf.commits[repo] = commit
+	index := make(map[string]indexEntry, len(symlinks))
+	for _, p := range symlinks {
+		index[p] = indexEntry{mode: gitSymlinkMode}
+	}
+	return walkRepoDir(dir, index)
+}
+
+// extractTarball extracts a github repository tarball into dir.
+// Returns the archived commit hash and the symlink paths.
+//
+// Symlinks are extracted as plain files with the link target, like git does
+// on file systems without symlinks, so they can't point outside of dir.
+func extractTarball(r io.Reader, dir string, limit int64) (commit string, symlinks []string, err error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return "", nil, err
+	}
+	tr := tar.NewReader(gz)
+	var size int64
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		if hdr.Typeflag == tar.TypeXGlobalHeader {
+			// git archive stores the commit hash in the global header comment.
+			commit = hdr.PAXRecords["comment"]
+			continue
+		}
+		// Entries are inside the "owner-repo-sha/" directory.
+		i := strings.IndexByte(hdr.Name, '/')
+		if i == -1 || i == len(hdr.Name)-1 {
+			continue
+		}
+		rel := path.Clean(hdr.Name[i+1:])
+		if path.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, "../") {
+			return "", nil, fmt.Errorf("%s: path is outside of the archive directory", hdr.Name)
+		}
+		filename := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return "", nil, err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(filename, 0755)
+		case tar.TypeReg:
+			size += hdr.Size
+			if size > limit {
+				return "", nil, fmt.Errorf("repository is larger than %s, use -fetch=clone", formatSize(limit))
+			}
+			mode := os.FileMode(0644)
+			if hdr.Mode&0111 != 0 {
+				mode = 0755
+			}
+			err = writeFileFrom(filename, tr, mode)
+		case tar.TypeSymlink:
+			symlinks = append(symlinks, rel)
+			err = ioutil.WriteFile(filename, []byte(hdr.Linkname), 0644)
+		}
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	return commit, symlinks, nil
+}
+
+func writeFileFrom(filename string, r io.Reader, mode os.FileMode) error {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (tf *tarballFetcher) ResolveRequirements(repo string, f *File) {
+	readLocalContents(repo, f)
+}
+
+func (tf *tarballFetcher) LinkTarget(repo string, f *File) (string, error) {
+	return readLinkTarget(filepath.Join(tf.repoDir(repo), filepath.FromSlash(f.origName)))
+}
+
+func (tf *tarballFetcher) RequestsCost(f *File) int { return 0 }
+
+func (tf *tarballFetcher) CommitSHA(repo string) (string, error) {
+	if sha := tf.commits[repo]; sha != "" {
+		return sha, nil
+	}
+	l := tf.l
+	sha, _, err := l.client.Repositories.GetCommitSHA1(l.ctx, l.user, repo, l.treeRef(), "")
+	l.countRequest()
+	return sha, err
+}
+
+func (tf *tarballFetcher) Cleanup(repo string) {
+	delete(tf.commits, repo)
+	if err := removeAll(tf.repoDir(repo)); err != nil {
+		log.Printf("\terror: remove %s tarball: %v", repo, err)
+	}
+}
@@ -0,0 +1,168 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/github"
+)
+
+// templateConfig describes a golden template repository.
+type templateConfig struct {
+	// Repo is a template repository full name, like "owner/repo".
+	Repo string `yaml:"repo"`
+
+	// Ref is a template git ref. Empty means the default branch.
+	Ref string `yaml:"ref"`
+
+	// Files are path patterns of the template files that every
+	// repository must have. See compileGlob for the patterns syntax.
+	Files []string `yaml:"files"`
+
+	// Sections are markdown files which template headings
+	// must be preserved, like README.md.
+	Sections []string `yaml:"sections"`
+}
+
+// defaultTemplateFiles are required when templateConfig.Files is empty.
+var defaultTemplateFiles = []string{".github/**", "CODEOWNERS"}
+
+// repoTemplate is a loaded template repository.
+type repoTemplate struct {
+	name string
+
+	// files are the required file names.
+	files []string
+
+	// sections maps markdown file names to their headings.
+	sections map[string][]string
+}
+
+// loadTemplate fetches the template repository specified in the config.
+func (l *Runner) loadTemplate() error {
+	cfg := l.config.Template
+	if cfg == nil {
+		return nil
+	}
+	parts := strings.Split(cfg.Repo, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("template: repo should be in owner/repo form, got %q", cfg.Repo)
+	}
+	owner, repo := parts[0], parts[1]
+	patterns := cfg.Files
+	if len(patterns) == 0 {
+		patterns = defaultTemplateFiles
+	}
+	required, err := newPathMatcher(patterns)
+	if err != nil {
+		return fmt.Errorf("template: files: %v", err)
+	}
+
+	ref := cfg.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	tree, _, err := l.client.Git.GetTree(l.ctx, owner, repo, ref, true)
+	l.countRequest()
+	if err != nil {
+		return fmt.Errorf("get %s tree: %v", cfg.Repo, err)
+	}
+	t := &repoTemplate{
+		name:     cfg.Repo,
+		sections: make(map[string][]string),
+	}
+	for _, entry := range tree.Entries {
+		if entry.GetType() == "blob" && required.Match(entry.GetPath()) {
+			t.files = append(t.files, entry.GetPath())
+		}
+	}
+	for _, name := range cfg.Sections {
+		opts := &github.RepositoryContentGetOptions{Ref: cfg.Ref}
+		f, _, _, err := l.client.Repositories.GetContents(l.ctx, owner, repo, name, opts)
+		l.countRequest()
+		if err != nil {
+			return fmt.Errorf("get %s/%s: %v", cfg.Repo, name, err)
+		}
+		contents, err := f.GetContent()
+		if err != nil {
+			return fmt.Errorf("decode %s/%s: %v", cfg.Repo, name, err)
+		}
+		t.sections[name] = markdownHeadings(contents)
+	}
+
+	for _, c := range l.checkers {
+		if c, ok := c.(*templateChecker); ok {
+			c.template = t
+		}
+	}
+	return nil
+}
+
+// templateChecker reports drift from the golden template repository:
+// deleted required files and deleted markdown sections.
+type templateChecker struct {
+	CheckerBase
+	template *repoTemplate
+}
+
+func newTemplateChecker() *templateChecker {
+	return &templateChecker{}
+}
+
+func (c *templateChecker) PushFile(f *File) {
+	if c.template == nil {
+		return
+	}
+	if _, ok := c.template.sections[f.origName]; ok {
+		f.require.contents = true
+	}
+	c.AcceptFile(f)
+}
+
+// Results depend on the template repository state.
+func (c *templateChecker) uncachedResults() {}
+
+// Missing files are found using all repository files.
+func (c *templateChecker) fullTree() {}
+
+func (c *templateChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	if c.template == nil {
+		return nil
+	}
+	files := make(map[string]*File, len(c.files))
+	for _, f := range c.files {
+		files[f.origName] = f
+	}
+
+	for _, name := range c.template.files {
+		if files[name] == nil {
+			w := fmt.Sprintf("%s: missing, required by template %s", name, c.template.name)
+			warnings = append(warnings, w)
+		}
+	}
+	names := make([]string, 0, len(c.template.sections))
+	for name := range c.template.sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		headings := c.template.sections[name]
+		f := files[name]
+		if f == nil {
+			continue
+		}
+		have := make(map[string]bool)
+		for _, h := range f.headings() {
+			have[strings.ToLower(h)] = true
+		}
+		for _, h := range headings {
+			if !have[strings.ToLower(h)] {
+				w := fmt.Sprintf("%s: section %q from template %s is missing", name, h, c.template.name)
+				warnings = append(warnings, w)
+			}
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,158 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// todoConfig enables the TODO checker.
+type todoConfig struct {
+	// Source also collects the source file comments.
+	Source bool `yaml:"source"`
+}
+
+// todoMaxFileSize limits the size of the files that are scanned for TODOs.
+const todoMaxFileSize = 1 << 20
+
+var (
+	// todoRE matches TODO, FIXME and XXX markers with an optional
+	// parenthesized note, like "TODO(#123)" or "FIXME(alice)".
+	todoRE = regexp.MustCompile(`\b(TODO|FIXME|XXX)\b(?:\(([^)\n]*)\))?`)
+
+	// todoIssueRE matches an issue reference in the marker note.
+	todoIssueRE = regexp.MustCompile(`(?:^|[\s,])#(\d+)\b`)
+)
+
+// todoComment is a TODO marker found in a file.
+type todoComment struct {
+	file   *File
+	line   int
+	marker string
+
+	// issue is a referenced issue number, 0 if there is none.
+	issue int
+}
+
+// todoCommentChecker collects TODO, FIXME and XXX markers from documentation
+// and, optionally, source comments. It reports their count and the markers
+// that reference already closed issues, like "TODO(#123)".
+// It's disabled until the todo config section is specified.
+type todoCommentChecker struct {
+	CheckerBase
+
+	enabled bool
+
+	// source is set by the todo config section.
+	source bool
+
+	// todos are collected on demand, since the issues are looked up
+	// before the checkers run; scanned reports whether they're collected.
+	todos   []todoComment
+	scanned bool
+
+	// closed are the closed issues, nil if issue states are unknown.
+	closed map[int]bool
+}
+
+func newTodoCommentChecker() *todoCommentChecker {
+	return &todoCommentChecker{}
+}
+
+func (c *todoCommentChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.todos = nil
+	c.scanned = false
+	c.closed = nil
+}
+
+// Vendored and generated TODOs are not maintained here.
+func (c *todoCommentChecker) skipGenerated() {}
+
+// Results depend on the issue states.
+func (c *todoCommentChecker) uncachedResults() {}
+
+func (c *todoCommentChecker) PushFile(f *File) {
+	if !c.enabled || f.dir || f.symlink || f.size > todoMaxFileSize {
+		return
+	}
+	if strings.HasPrefix(strings.ToUpper(f.baseName), "TODO") {
+		// TODO lists are meant to be full of TODOs.
+		return
+	}
+	isDoc := isMarkdownFile(f.baseName) || isDocumentationFile(f.baseName)
+	if isDoc || c.source && isSourceFile(f.baseName) {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+// collect finds the TODO markers in the accepted files.
+func (c *todoCommentChecker) collect() []todoComment {
+	if c.scanned {
+		return c.todos
+	}
+	c.scanned = true
+	for _, f := range c.files {
+		text := f.contents
+		if comments, ok := sourceLiterals(f.baseName, f.contents, false); ok {
+			text = comments
+		}
+		for i, line := range strings.Split(text, "\n") {
+			for _, m := range todoRE.FindAllStringSubmatch(line, -1) {
+				todo := todoComment{file: f, line: i + 1, marker: m[1]}
+				if im := todoIssueRE.FindStringSubmatch(m[2]); im != nil {
+					todo.issue, _ = strconv.Atoi(im[1])
+				}
+				c.todos = append(c.todos, todo)
+			}
+		}
+	}
+	return c.todos
+}
+
+// referencedIssues returns the issue numbers referenced by the TODOs.
+func (c *todoCommentChecker) referencedIssues() []int {
+	var issues []int
+	seen := make(map[int]bool)
+	for _, todo := range c.collect() {
+		if todo.issue != 0 && !seen[todo.issue] {
+			seen[todo.issue] = true
+			issues = append(issues, todo.issue)
+		}
+	}
+	return issues
+}
+
+func (c *todoCommentChecker) setClosedIssues(closed map[int]bool) {
+	c.closed = closed
+}
+
+func (c *todoCommentChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	todos := c.collect()
+	if len(todos) == 0 {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	files := make(map[*File]bool)
+	for _, todo := range todos {
+		counts[todo.marker]++
+		files[todo.file] = true
+		if c.closed[todo.issue] {
+			w := fmt.Sprintf("%s:%d: %s references closed issue #%d",
+				todo.file.origName, todo.line, todo.marker, todo.issue)
+			warnings = append(warnings, w)
+		}
+	}
+	var summary []string
+	for _, marker := range []string{"TODO", "FIXME", "XXX"} {
+		if counts[marker] != 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[marker], marker))
+		}
+	}
+	w := fmt.Sprintf("%d TODO comments in %d files: %s", len(todos), len(files), strings.Join(summary, ", "))
+	return append([]string{w}, warnings...)
+}
@@ -0,0 +1,129 @@
+package lint
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenPool rotates github tokens based on their remaining rate limit,
+// so large scans can use the quota of several tokens.
+type tokenPool struct {
+	mu     sync.Mutex
+	tokens []*tokenState
+}
+
+type tokenState struct {
+	token string
+
+	// requests is a number of requests made with this token.
+	requests int
+
+	// remaining is a number of API calls left until reset.
+	// -1 means the limit is unknown yet.
+	remaining int
+	reset     time.Time
+}
+
+// parseTokens splits a comma or newline separated list of tokens.
+func parseTokens(s string) []string {
+	var tokens []string
+	for _, t := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r'
+	}) {
+		if t = strings.TrimSpace(t); t != "" {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
+
+func newTokenPool(tokens []string) *tokenPool {
+	p := &tokenPool{}
+	for _, t := range tokens {
+		p.tokens = append(p.tokens, &tokenState{token: t, remaining: -1})
+	}
+	return p
+}
+
+// pick returns a token with the most remaining API calls.
+func (p *tokenPool) pick() *tokenState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var best *tokenState
+	bestRemaining := 0
+	for _, t := range p.tokens {
+		remaining := t.remaining
+		if remaining == -1 || now.After(t.reset) {
+			// Unknown or already restored limit.
+			remaining = math.MaxInt32
+		}
+		if best == nil || remaining > bestRemaining {
+			best = t
+			bestRemaining = remaining
+		}
+	}
+	best.requests++
+	return best
+}
+
+// update records the rate limit state reported in the response headers.
+func (p *tokenPool) update(t *tokenState, resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	p.mu.Lock()
+	t.remaining = remaining
+	t.reset = time.Unix(reset, 0)
+	p.mu.Unlock()
+}
+
+// logQuota prints per-token usage and the remaining rate limit.
+// Tokens are identified by their last 4 characters.
+func (p *tokenPool) logQuota() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, t := range p.tokens {
+		name := t.token
+		if len(name) > 4 {
+			name = "..." + name[len(name)-4:]
+		}
+		if t.remaining == -1 {
+			log.Printf("\ttoken %s: made %d requests", name, t.requests)
+			continue
+		}
+		log.Printf("\ttoken %s: made %d requests, %d remaining until %s",
+			name, t.requests, t.remaining, t.reset.Format("15:04:05"))
+	}
+}
+
+// tokenTransport authorizes requests with a token from the pool.
+type tokenTransport struct {
+	pool *tokenPool
+	base http.RoundTripper
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token := t.pool.pick()
+	// RoundTripper should not modify the original request.
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token.token)
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	t.pool.update(token, resp)
+	return resp, nil
+}
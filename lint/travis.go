@@ -0,0 +1,133 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// travisKeys are the known .travis.yml top-level keys.
+var travisKeys = map[string]bool{
+	// Common keys.
+	"language": true, "os": true, "dist": true, "arch": true, "sudo": true,
+	"group": true, "services": true, "addons": true, "cache": true, "env": true,
+	"matrix": true, "jobs": true, "stages": true, "branches": true, "notifications": true,
+	"git": true, "import": true, "version": true, "if": true, "filter_secrets": true,
+	"trace": true, "osx_image": true, "virt": true, "compiler": true,
+	"before_install": true, "install": true, "before_script": true, "script": true,
+	"after_success": true, "after_failure": true, "after_script": true,
+	"before_cache": true, "before_deploy": true, "deploy": true, "after_deploy": true,
+
+	// Language-specific keys.
+	"go": true, "go_import_path": true, "gobuild_args": true,
+	"node_js": true, "npm_args": true, "python": true, "virtualenv": true,
+	"jdk": true, "ruby": true, "rvm": true, "gemfile": true, "bundler_args": true,
+	"php": true, "composer_args": true, "rust": true, "scala": true, "sbt_args": true,
+	"perl": true, "julia": true, "dart": true, "elixir": true, "otp_release": true,
+	"ghc": true, "r": true, "r_packages": true, "d": true, "crystal": true,
+	"haxe": true, "nix": true, "smalltalk": true, "mono": true, "dotnet": true,
+	"solution": true, "xcode_project": true, "xcode_workspace": true,
+	"xcode_scheme": true, "xcode_sdk": true, "podfile": true,
+}
+
+// travisYmlChecker validates .travis.yml and finds Travis configs
+// that no longer run, since travis-ci.org is shut down.
+type travisYmlChecker struct {
+	CheckerBase
+
+	travisYml *File
+
+	// workflows reports whether the repository has GitHub Actions workflows.
+	workflows bool
+}
+
+func newTravisYmlChecker() *travisYmlChecker {
+	return &travisYmlChecker{}
+}
+
+func (c *travisYmlChecker) Reset() {
+	c.CheckerBase.Reset()
+	c.travisYml = nil
+	c.workflows = false
+}
+
+func (c *travisYmlChecker) PushFile(f *File) {
+	switch {
+	case f.origName == ".travis.yml":
+		f.require.contents = true
+		c.travisYml = f
+		c.AcceptFile(f)
+	case isWorkflowFile(f.origName):
+		c.workflows = true
+	case strings.HasPrefix(strings.ToUpper(f.baseName), "README"):
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+// Unused configs are found using all repository files.
+func (c *travisYmlChecker) fullTree() {}
+
+func (c *travisYmlChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	if c.travisYml == nil {
+		return nil
+	}
+	// Builds that still run on travis-ci.com are usually shown in README.
+	travisCom := false
+	for _, f := range c.files {
+		if f != c.travisYml && strings.Contains(f.contents, "travis-ci.com") {
+			travisCom = true
+		}
+	}
+
+	for _, err := range c.checkContents([]byte(c.travisYml.contents)) {
+		warnings = append(warnings, fmt.Sprintf(".travis.yml: %v", err))
+	}
+	switch {
+	case travisCom:
+		// Builds still run.
+	case c.workflows:
+		warnings = append(warnings, ".travis.yml: no active Travis builds, the repository uses GitHub Actions, remove the unused config")
+	default:
+		warnings = append(warnings, ".travis.yml: no active Travis builds, consider migrating to GitHub Actions")
+	}
+	return warnings
+}
+
+// CheckFile validates the specified .travis.yml file.
+func (c *travisYmlChecker) CheckFile(filename string) []error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return []error{err}
+	}
+	return c.checkContents(data)
+}
+
+// checkContents validates .travis.yml top-level keys.
+// Errors are worded like the Travis config validation messages.
+func (c *travisYmlChecker) checkContents(data []byte) []error {
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	if _, ok := config["language"]; !ok {
+		errs = append(errs, fmt.Errorf(`missing key "language", defaulting to "ruby"`))
+	}
+	var unknown []string
+	for key := range config {
+		if !travisKeys[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		errs = append(errs, fmt.Errorf("unexpected key %q, dropping", key))
+	}
+	return errs
+}
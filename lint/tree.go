@@ -0,0 +1,101 @@
+package lint
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// repoTree is an index of all repository paths, including the files
+// that are not pushed to the checkers, like vendored, excluded
+// or unchanged in -diff mode files.
+type repoTree struct {
+	// paths is a set of file and directory paths.
+	paths map[string]bool
+
+	// byBase maps lower-cased base names to the paths.
+	byBase map[string][]string
+
+	// files are all listed repository files and directories.
+	files []*File
+}
+
+func newRepoTree(files []*File) *repoTree {
+	t := &repoTree{
+		paths:  make(map[string]bool, len(files)),
+		byBase: make(map[string][]string, len(files)),
+		files:  files,
+	}
+	for _, f := range files {
+		for p := f.origName; p != "." && p != "/" && !t.paths[p]; p = path.Dir(p) {
+			t.paths[p] = true
+			base := strings.ToLower(path.Base(p))
+			t.byBase[base] = append(t.byBase[base], p)
+		}
+	}
+	return t
+}
+
+// has reports whether p is a repository file or directory.
+func (t *repoTree) has(p string) bool {
+	return t.paths[p]
+}
+
+// findMoved returns the likely new location of a missing path p:
+// a path with the same base name that shares most of the parent
+// directories with p. Returns empty string if there are no such paths
+// or several paths are equally likely, like for common README.md names.
+func (t *repoTree) findMoved(p string) string {
+	candidates := t.byBase[strings.ToLower(path.Base(p))]
+	if len(candidates) == 0 {
+		return ""
+	}
+	dirs := strings.Split(path.Dir(p), "/")
+	score := func(candidate string) int {
+		n := 0
+		for _, d := range strings.Split(path.Dir(candidate), "/") {
+			for _, x := range dirs {
+				if d != "." && strings.EqualFold(d, x) {
+					n++
+					break
+				}
+			}
+		}
+		if path.Base(candidate) == path.Base(p) {
+			// Prefer the same case over renamed files, like install.md to INSTALL.md.
+			n++
+		}
+		return n
+	}
+	sorted := append([]string{}, candidates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		x, y := score(sorted[i]), score(sorted[j])
+		if x != y {
+			return x > y
+		}
+		if len(sorted[i]) != len(sorted[j]) {
+			return len(sorted[i]) < len(sorted[j])
+		}
+		return sorted[i] < sorted[j]
+	})
+	if len(sorted) > 1 && score(sorted[0]) == score(sorted[1]) {
+		return ""
+	}
+	return sorted[0]
+}
+
+// treeIndexChecker is implemented by the checkers that need to know
+// which paths exist in the repository, besides the files pushed to them.
+type treeIndexChecker interface {
+	// setTree passes the repository tree index, nil if it's incomplete.
+	setTree(t *repoTree)
+}
+
+// setTree passes the current repository tree index to the checkers that need it.
+func (l *Runner) setTree() {
+	for _, c := range l.checkers {
+		if c, ok := c.(treeIndexChecker); ok {
+			c.setTree(l.tree)
+		}
+	}
+}
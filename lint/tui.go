@@ -0,0 +1,387 @@
+package lint
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// triagePageSize is a number of warnings listed at once.
+const triagePageSize = 20
+
+var (
+	// warningLineRE matches warning locations with a line number,
+	// like "README.md:12: " or "README.md:12:5: ".
+	warningLineRE = regexp.MustCompile(`^([^:\s][^:]*):(\d+)(?::\d+)?: `)
+
+	// warningFileRE matches warning locations without a line number, like "LICENSE: ".
+	warningFileRE = regexp.MustCompile(`^([^:\s]+): `)
+)
+
+// triageItem is a warning of the browsed results.
+type triageItem struct {
+	repo    string
+	commit  string
+	warning Warning
+	triaged bool
+}
+
+// triageSession is an interactive results browser.
+// Items are numbered by their position in the results,
+// so the numbers don't change when a filter is applied.
+type triageSession struct {
+	user         string
+	webURL       string
+	baselineFile string
+	items        []*triageItem
+
+	// kept are the baseline entries of warnings that are not in the results,
+	// they're saved as is.
+	kept []baselineEntry
+
+	// checkerFilter is a checker name of the listed warnings, empty for all checkers.
+	checkerFilter string
+
+	// repoFilter is a repository name of the listed warnings, empty for all repositories.
+	repoFilter string
+
+	page int
+
+	// dirty reports whether the triage state has unsaved changes.
+	dirty bool
+
+	in  *bufio.Scanner
+	out io.Writer
+
+	// open opens the URL in a browser.
+	open func(u string) error
+}
+
+// RunTUI browses the -json results in the terminal: warnings can be filtered
+// by checker, opened on github and marked as triaged.
+// Triaged warnings are saved to a baseline file.
+func RunTUI(args []string) error {
+	fs := flag.NewFlagSet("tui", flag.ContinueOnError)
+	baselineFile := fs.String("baseline", "baseline.json",
+		`baseline file to load and save the triaged warnings`)
+	webURL := fs.String("github-url", "https://github.com",
+		`github web URL, used to open the files`)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: repolint tui [-baseline baseline.json] results.json")
+	}
+
+	data, err := ioutil.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	var r runReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		return fmt.Errorf("%s: %v", fs.Arg(0), err)
+	}
+	s, err := newTriageSession(&r, *baselineFile, *webURL)
+	if err != nil {
+		return err
+	}
+	s.in = bufio.NewScanner(os.Stdin)
+	s.out = os.Stdout
+	s.open = openBrowser
+	return s.run()
+}
+
+// newTriageSession returns a session with the warnings
+// of the baseline file marked as triaged.
+func newTriageSession(r *runReport, baselineFile, webURL string) (*triageSession, error) {
+	s := &triageSession{
+		user:         r.User,
+		webURL:       strings.TrimSuffix(webURL, "/"),
+		baselineFile: baselineFile,
+	}
+	entries, err := loadBaselineEntries(baselineFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	triaged := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		triaged[e.Fingerprint] = true
+	}
+	reported := make(map[string]bool)
+	for _, rr := range r.Repos {
+		for _, w := range rr.Warnings {
+			fp := warningFingerprint(rr.Name, w)
+			reported[fp] = true
+			s.items = append(s.items, &triageItem{
+				repo:    rr.Name,
+				commit:  rr.Commit,
+				warning: w,
+				triaged: triaged[fp],
+			})
+		}
+	}
+	for _, e := range entries {
+		if !reported[e.Fingerprint] {
+			s.kept = append(s.kept, e)
+		}
+	}
+	return s, nil
+}
+
+func (s *triageSession) run() error {
+	s.list()
+	quit := false
+	for {
+		fmt.Fprint(s.out, "> ")
+		if !s.in.Scan() {
+			if s.dirty {
+				fmt.Fprintln(s.out, "\ntriage state is not saved")
+			}
+			return s.in.Err()
+		}
+		cmd, arg := s.in.Text(), ""
+		if i := strings.IndexByte(cmd, ' '); i != -1 {
+			cmd, arg = cmd[:i], strings.TrimSpace(cmd[i+1:])
+		}
+		switch cmd {
+		case "", "n":
+			s.page++
+			s.list()
+		case "p":
+			s.page--
+			s.list()
+		case "f":
+			s.setFilter(&s.checkerFilter, arg)
+		case "r":
+			s.setFilter(&s.repoFilter, arg)
+		case "o":
+			s.openItem(arg)
+		case "t":
+			s.toggle(arg)
+		case "w":
+			if err := s.save(); err != nil {
+				fmt.Fprintf(s.out, "error: %v\n", err)
+			}
+		case "q":
+			if s.dirty && !quit {
+				fmt.Fprintln(s.out, "triage state is not saved, w saves it, q quits anyway")
+				quit = true
+				continue
+			}
+			return nil
+		case "h", "?":
+			s.help()
+		default:
+			fmt.Fprintf(s.out, "unknown command %q, h prints help\n", cmd)
+		}
+		quit = false
+	}
+}
+
+func (s *triageSession) help() {
+	fmt.Fprint(s.out, `commands:
+  n, Enter       next page
+  p              previous page
+  f [checker]    list only the checker warnings, like "f broken link"; f lists all checkers
+  r [repo]       list only the repository warnings; r lists all repositories
+  o N            open warning N file on github
+  t N [M-K ...]  mark warnings as triaged or not
+  w              save the triaged warnings to the baseline file
+  q              quit
+`)
+}
+
+// filtered returns the indexes of the listed items.
+func (s *triageSession) filtered() []int {
+	var indexes []int
+	for i, item := range s.items {
+		if s.checkerFilter != "" && !strings.EqualFold(item.warning.Checker, s.checkerFilter) {
+			continue
+		}
+		if s.repoFilter != "" && !strings.EqualFold(item.repo, s.repoFilter) {
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+func (s *triageSession) list() {
+	indexes := s.filtered()
+	pages := (len(indexes) + triagePageSize - 1) / triagePageSize
+	if s.page >= pages {
+		s.page = pages - 1
+	}
+	if s.page < 0 {
+		s.page = 0
+	}
+	from := s.page * triagePageSize
+	to := from + triagePageSize
+	if to > len(indexes) {
+		to = len(indexes)
+	}
+	triaged := 0
+	for _, i := range indexes {
+		if s.items[i].triaged {
+			triaged++
+		}
+	}
+	for _, i := range indexes[from:to] {
+		item := s.items[i]
+		mark := " "
+		if item.triaged {
+			mark = "x"
+		}
+		fmt.Fprintf(s.out, "%4d [%s] %s: %s: %s\n", i+1, mark, item.repo, item.warning.Checker, item.warning.Text)
+	}
+	status := fmt.Sprintf("page %d/%d, %d warnings, %d triaged", s.page+1, pages, len(indexes), triaged)
+	if pages == 0 {
+		status = "no warnings"
+	}
+	if s.repoFilter != "" {
+		status += fmt.Sprintf(", repository %q", s.repoFilter)
+	}
+	if s.checkerFilter != "" {
+		status += fmt.Sprintf(", checker %q", s.checkerFilter)
+	}
+	fmt.Fprintln(s.out, status+"; h prints help")
+}
+
+// setFilter sets the checker or repository filter,
+// unless nothing matches it.
+func (s *triageSession) setFilter(filter *string, value string) {
+	prev := *filter
+	*filter = value
+	if len(s.filtered()) == 0 {
+		fmt.Fprintf(s.out, "no %q warnings\n", value)
+		*filter = prev
+		return
+	}
+	s.page = 0
+	s.list()
+}
+
+// parseItems parses space-separated item numbers and ranges, like "1 3-5".
+func (s *triageSession) parseItems(arg string) ([]*triageItem, error) {
+	var items []*triageItem
+	for _, field := range strings.Fields(arg) {
+		bounds := strings.SplitN(field, "-", 2)
+		from, err := strconv.Atoi(bounds[0])
+		to := from
+		if err == nil && len(bounds) == 2 {
+			to, err = strconv.Atoi(bounds[1])
+		}
+		if err != nil || from < 1 || to < from || to > len(s.items) {
+			return nil, fmt.Errorf("%q is not a warning number or range between 1 and %d", field, len(s.items))
+		}
+		items = append(items, s.items[from-1:to]...)
+	}
+	if len(items) == 0 {
+		return nil, errors.New("no warning numbers specified")
+	}
+	return items, nil
+}
+
+func (s *triageSession) toggle(arg string) {
+	items, err := s.parseItems(arg)
+	if err != nil {
+		fmt.Fprintf(s.out, "error: %v\n", err)
+		return
+	}
+	for _, item := range items {
+		item.triaged = !item.triaged
+	}
+	s.dirty = true
+	s.list()
+}
+
+func (s *triageSession) openItem(arg string) {
+	items, err := s.parseItems(arg)
+	if err != nil {
+		fmt.Fprintf(s.out, "error: %v\n", err)
+		return
+	}
+	for _, item := range items {
+		u := s.itemURL(item)
+		if u == "" {
+			fmt.Fprintln(s.out, "error: local results have no github URL")
+			return
+		}
+		fmt.Fprintln(s.out, u)
+		if err := s.open(u); err != nil {
+			fmt.Fprintf(s.out, "error: open browser: %v\n", err)
+		}
+	}
+}
+
+// itemURL returns a github URL of the warning file and line,
+// or of the repository if the warning has no file location.
+func (s *triageSession) itemURL(item *triageItem) string {
+	if s.user == "" {
+		return ""
+	}
+	repoURL := s.webURL + "/" + s.user + "/" + item.repo
+	ref := item.commit
+	if ref == "" {
+		ref = "HEAD"
+	}
+	fileURL := func(p string) string {
+		elems := strings.Split(strings.TrimSuffix(p, "/"), "/")
+		for i, elem := range elems {
+			elems[i] = url.PathEscape(elem)
+		}
+		return repoURL + "/blob/" + ref + "/" + strings.Join(elems, "/")
+	}
+	text := item.warning.Text
+	if m := warningLineRE.FindStringSubmatch(text); m != nil {
+		return fileURL(m[1]) + "#L" + m[2]
+	}
+	if m := warningFileRE.FindStringSubmatch(text); m != nil {
+		return fileURL(m[1])
+	}
+	return repoURL
+}
+
+// save writes the triaged warnings to the baseline file,
+// along with the baseline entries of warnings that are not in the results.
+func (s *triageSession) save() error {
+	entries := append([]baselineEntry{}, s.kept...)
+	n := 0
+	for _, item := range s.items {
+		if item.triaged {
+			entries = append(entries, newBaselineEntry(item.repo, item.warning))
+			n++
+		}
+	}
+	if err := saveBaselineEntries(s.baselineFile, entries); err != nil {
+		return err
+	}
+	s.dirty = false
+	fmt.Fprintf(s.out, "saved %d triaged warnings to %s\n", n, s.baselineFile)
+	return nil
+}
+
+// openBrowser opens the URL in the default browser.
+func openBrowser(u string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", u)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	default:
+		cmd = exec.Command("xdg-open", u)
+	}
+	return cmd.Run()
+}
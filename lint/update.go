@@ -0,0 +1,225 @@
+package lint
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/google/go-github/github"
+)
+
+// Version is set during the release build with
+// -ldflags="-X github.com/Quasilyte/repolint/lint.Version=vX.Y.Z".
+var Version = "dev"
+
+// releasePublicKey is a base64-encoded Ed25519 public key that signs the release checksums.
+// It's pinned during the release build with
+// -ldflags="-X github.com/Quasilyte/repolint/lint.releasePublicKey=<key>".
+// Binaries without it can't update themselves.
+var releasePublicKey = ""
+
+// releaseVersionRE matches release tags, like "v1.2.3" or "v1.3.0-rc.1".
+var releaseVersionRE = regexp.MustCompile(`^v\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?$`)
+
+const (
+	releaseOwner = "Quasilyte"
+	releaseRepo  = "repolint"
+)
+
+// releaseAssetName returns a binary asset name for the current platform.
+func releaseAssetName() string {
+	name := fmt.Sprintf("repolint_%s_%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+func latestRelease(ctx context.Context) (*github.RepositoryRelease, error) {
+	client := github.NewClient(nil)
+	release, _, err := client.Repositories.GetLatestRelease(ctx, releaseOwner, releaseRepo)
+	return release, err
+}
+
+// newerRelease reports whether the release tag is a newer version than current.
+// Versions that are not release tags, like "dev", are never updated.
+func newerRelease(tag, current string) bool {
+	if !releaseVersionRE.MatchString(tag) || !releaseVersionRE.MatchString(current) {
+		return false
+	}
+	return compareReleaseVersions(tag[1:], current[1:]) > 0
+}
+
+// pinnedReleaseKey decodes releasePublicKey.
+func pinnedReleaseKey() (ed25519.PublicKey, error) {
+	if releasePublicKey == "" {
+		return nil, errors.New("this build has no release signing key, download the release manually")
+	}
+	key, err := base64.StdEncoding.DecodeString(releasePublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, errors.New("bad release signing key")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// SelfUpdate replaces the running binary with the latest release.
+func SelfUpdate(args []string) error {
+	if !releaseVersionRE.MatchString(Version) {
+		return fmt.Errorf("repolint %s is not a release build and can't be updated", Version)
+	}
+	pub, err := pinnedReleaseKey()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	release, err := latestRelease(ctx)
+	if err != nil {
+		return fmt.Errorf("get latest release: %v", err)
+	}
+	tag := release.GetTagName()
+	if !newerRelease(tag, Version) {
+		log.Printf("repolint %s is up to date", Version)
+		return nil
+	}
+
+	assets := make(map[string]string)
+	for _, a := range release.Assets {
+		assets[a.GetName()] = a.GetBrowserDownloadURL()
+	}
+	binaryURL := assets[releaseAssetName()]
+	if binaryURL == "" {
+		return fmt.Errorf("release %s has no %s asset", tag, releaseAssetName())
+	}
+	checksumsURL := assets["checksums.txt"]
+	if checksumsURL == "" {
+		return fmt.Errorf("release %s has no checksums.txt asset", tag)
+	}
+	signatureURL := assets["checksums.txt.sig"]
+	if signatureURL == "" {
+		return fmt.Errorf("release %s has no checksums.txt.sig asset", tag)
+	}
+
+	checksums, err := download(checksumsURL)
+	if err != nil {
+		return err
+	}
+	signature, err := download(signatureURL)
+	if err != nil {
+		return err
+	}
+	checksum, err := releaseChecksum(checksums, signature, pub, releaseAssetName())
+	if err != nil {
+		return fmt.Errorf("release %s: %v", tag, err)
+	}
+	data, err := download(binaryURL)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != checksum {
+		return fmt.Errorf("%s checksum mismatch", releaseAssetName())
+	}
+
+	if err := replaceExecutable(data); err != nil {
+		return err
+	}
+	log.Printf("updated repolint %s -> %s", Version, tag)
+	return nil
+}
+
+// releaseChecksum verifies the checksums file signature and finds a sha256 checksum
+// of the asset in it. File format is the sha256sum output: "<hex checksum>  <file name>".
+// The signature is a base64-encoded Ed25519 signature of the whole file.
+func releaseChecksum(checksums, signature []byte, pub ed25519.PublicKey, assetName string) (string, error) {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return "", fmt.Errorf("decode checksums.txt signature: %v", err)
+	}
+	if !ed25519.Verify(pub, checksums, sig) {
+		return "", errors.New("bad checksums.txt signature")
+	}
+	scanner := bufio.NewScanner(strings.NewReader(string(checksums)))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == assetName {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no %s checksum in checksums.txt", assetName)
+}
+
+func download(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return ioutil.ReadAll(io.LimitReader(resp.Body, 256<<20))
+}
+
+func replaceExecutable(data []byte) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	exe, err = filepath.EvalSymlinks(exe)
+	if err != nil {
+		return err
+	}
+
+	newExe := exe + ".new"
+	oldExe := exe + ".old"
+	if err := ioutil.WriteFile(newExe, data, 0755); err != nil {
+		return err
+	}
+	// Running executable can't be overwritten on some systems,
+	// but it can be renamed.
+	os.Remove(oldExe)
+	if err := os.Rename(exe, oldExe); err != nil {
+		os.Remove(newExe)
+		return err
+	}
+	if err := os.Rename(newExe, exe); err != nil {
+		os.Rename(oldExe, exe)
+		return err
+	}
+	// Fails on Windows, the file will be removed on the next update.
+	os.Remove(oldExe)
+	return nil
+}
+
+// checkForUpdates prints a notice if a newer release is available.
+func (l *Runner) checkForUpdates() error {
+	if !l.updateCheck || !releaseVersionRE.MatchString(Version) || l.apiURL != "" {
+		return nil
+	}
+	release, err := latestRelease(l.ctx)
+	if err != nil {
+		if l.verbose {
+			log.Printf("\t\tdebug: check for updates: %v", err)
+		}
+		return nil
+	}
+	if tag := release.GetTagName(); newerRelease(tag, Version) {
+		log.Printf("repolint %s is available (current version is %s), run `repolint self-update` to update",
+			tag, Version)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// trailingWhitespaceChecker finds spaces and tabs at the end of lines,
+// whitespace-only lines and missing final newlines in documentation and config files.
+type trailingWhitespaceChecker struct {
+	CheckerBase
+	fixBase
+}
+
+func newTrailingWhitespaceChecker() *trailingWhitespaceChecker {
+	return &trailingWhitespaceChecker{}
+}
+
+// Whitespace in generated files is up to the generator.
+func (c *trailingWhitespaceChecker) skipGenerated() {}
+
+// whitespaceConfigExts are the config file extensions checked for whitespace problems.
+var whitespaceConfigExts = map[string]bool{
+	".yml": true, ".yaml": true, ".toml": true, ".json": true,
+	".ini": true, ".cfg": true, ".conf": true, ".properties": true,
+}
+
+// whitespaceConfigNames are the config file base names without an extension.
+var whitespaceConfigNames = map[string]bool{
+	".editorconfig": true, ".gitignore": true, ".gitattributes": true,
+	".dockerignore": true, ".npmignore": true, "CODEOWNERS": true,
+}
+
+func isWhitespaceCheckedFile(filename string) bool {
+	return isDocumentationFile(filename) ||
+		whitespaceConfigExts[strings.ToLower(path.Ext(filename))] ||
+		whitespaceConfigNames[filename]
+}
+
+func (c *trailingWhitespaceChecker) PushFile(f *File) {
+	if isWhitespaceCheckedFile(f.baseName) {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *trailingWhitespaceChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	c.fixes = c.fixes[:0]
+	for _, f := range c.files {
+		if ctx.Err() != nil {
+			break
+		}
+		markdown := strings.EqualFold(path.Ext(f.baseName), ".md")
+		offset := 0
+		lines := f.lines()
+		for i, l := range lines {
+			lineStart := offset
+			offset += len(l) + len("\n")
+			text := strings.TrimSuffix(l, "\r")
+			trimmed := strings.TrimRight(text, " \t")
+			if trimmed == text {
+				continue
+			}
+			problem := "trailing whitespace"
+			if trimmed == "" {
+				problem = "line contains only whitespace"
+			} else if markdown && strings.HasSuffix(text, "  ") && !strings.ContainsRune(text[len(trimmed):], '\t') {
+				// Two or more trailing spaces make a markdown line break.
+				continue
+			}
+			w := fmt.Sprintf("%s:%d: %s", f.origName, i+1, problem)
+			warnings = append(warnings, w)
+			c.fixes = append(c.fixes, &Fix{
+				File:  f.origName,
+				Start: lineStart + len(trimmed),
+				End:   lineStart + len(text),
+			})
+		}
+		if f.contents != "" && !strings.HasSuffix(f.contents, "\n") {
+			w := fmt.Sprintf("%s:%d: no newline at end of file", f.origName, len(lines))
+			warnings = append(warnings, w)
+			c.fixes = append(c.fixes, &Fix{
+				File:        f.origName,
+				Start:       len(f.contents),
+				End:         len(f.contents),
+				Replacement: lineBreak(f.contents),
+			})
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,89 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// deprecatedActions maps actions to their oldest supported major version.
+// Older versions run on removed Node.js runtimes or use shut down services.
+var deprecatedActions = map[string]int{
+	"actions/cache":             4,
+	"actions/checkout":          4,
+	"actions/download-artifact": 4,
+	"actions/github-script":     7,
+	"actions/setup-go":          5,
+	"actions/setup-java":        4,
+	"actions/setup-node":        4,
+	"actions/setup-python":      5,
+	"actions/upload-artifact":   4,
+}
+
+// deprecatedWorkflowCommands maps deprecated workflow commands
+// to the environment files that replace them.
+var deprecatedWorkflowCommands = map[string]string{
+	"set-output": "$GITHUB_OUTPUT",
+	"save-state": "$GITHUB_STATE",
+	"set-env":    "$GITHUB_ENV",
+	"add-path":   "$GITHUB_PATH",
+}
+
+var (
+	// actionUsesRE matches "uses: owner/repo@v1" and "uses: owner/repo/path@v1.2.3" steps.
+	actionUsesRE = regexp.MustCompile(`^\s*(?:-\s*)?uses:\s*["']?([\w.-]+/[\w.-]+)(?:/[^@\s"']*)?@v?(\d+)`)
+
+	// workflowCommandRE matches "::set-output name=x::value" and similar commands.
+	workflowCommandRE = regexp.MustCompile(`::(set-output|save-state|set-env|add-path)\b`)
+)
+
+// isWorkflowFile reports whether filename is a GitHub Actions workflow.
+func isWorkflowFile(filename string) bool {
+	ext := path.Ext(filename)
+	return path.Dir(filename) == ".github/workflows" && (ext == ".yml" || ext == ".yaml")
+}
+
+// workflowChecker finds GitHub Actions workflows that use deprecated
+// action versions and deprecated workflow commands, like set-output.
+type workflowChecker struct {
+	CheckerBase
+}
+
+func newWorkflowChecker() *workflowChecker {
+	return &workflowChecker{}
+}
+
+func (c *workflowChecker) PushFile(f *File) {
+	if isWorkflowFile(f.origName) {
+		f.require.contents = true
+		c.AcceptFile(f)
+	}
+}
+
+func (c *workflowChecker) CheckFiles(ctx context.Context) (warnings []string) {
+	for _, f := range c.files {
+		for i, line := range strings.Split(f.contents, "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "#") {
+				continue
+			}
+			if m := actionUsesRE.FindStringSubmatch(line); m != nil {
+				action := strings.ToLower(m[1])
+				major, err := strconv.Atoi(m[2])
+				if min, ok := deprecatedActions[action]; ok && err == nil && major < min {
+					w := fmt.Sprintf("%s:%d: %s@v%d is deprecated, use %s@v%d or later",
+						f.origName, i+1, action, major, action, min)
+					warnings = append(warnings, w)
+				}
+			}
+			for _, m := range workflowCommandRE.FindAllStringSubmatch(line, -1) {
+				w := fmt.Sprintf("%s:%d: ::%s workflow command is deprecated, use %s",
+					f.origName, i+1, m[1], deprecatedWorkflowCommands[m[1]])
+				warnings = append(warnings, w)
+			}
+		}
+	}
+	return warnings
+}